@@ -0,0 +1,321 @@
+package case_report
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// maxChainsPerImpact は1つの影響について列挙する因果の連鎖の上限です。
+const maxChainsPerImpact = 20
+
+// maxWeakestLinks は弱点として挙げる、切断されると影響を失うノード・エッジの上限です。
+const maxWeakestLinks = 5
+
+// RebuttalEntry は対象に向けられた反論と、その反論への再反論です。
+type RebuttalEntry struct {
+	Kind      string          `json:"kind"`  // domain.RebuttalKind* のいずれか
+	Label     string          `json:"label"` // 反論の種類の表示名
+	Argument  string          `json:"argument"`
+	Responses []RebuttalEntry `json:"responses,omitempty"`
+}
+
+// NodeSection はノードの主張と、その根拠・反論です。
+type NodeSection struct {
+	Argument   string            `json:"argument"`
+	Importance []domain.Evidence `json:"importance"`
+	Uniqueness []domain.Evidence `json:"uniqueness"`
+	Rebuttals  []RebuttalEntry   `json:"rebuttals"`
+}
+
+// LinkSection は因果関係と、その根拠・反論です。
+type LinkSection struct {
+	Cause      string            `json:"cause"`
+	Effect     string            `json:"effect"`
+	Certainty  []domain.Evidence `json:"certainty"`
+	Uniqueness []domain.Evidence `json:"uniqueness"`
+	Rebuttals  []RebuttalEntry   `json:"rebuttals"`
+}
+
+// ImpactSection は1つの影響と、それを支える因果の連鎖です。
+// Nodes と Links は連鎖に現れる影響以外のノードとエッジで、前提に近いものから並びます。
+type ImpactSection struct {
+	Impact      NodeSection   `json:"impact"`
+	Polarity    string        `json:"polarity,omitempty"`
+	Stakeholder string        `json:"stakeholder,omitempty"`
+	Magnitude   *float64      `json:"magnitude,omitempty"`
+	Chains      [][]string    `json:"chains"`
+	Truncated   bool          `json:"truncated"` // 連鎖が maxChainsPerImpact を超えて省略された
+	Nodes       []NodeSection `json:"nodes"`
+	Links       []LinkSection `json:"links"`
+}
+
+// SideSection は同じ立場 (NodeMetadata.Side) の影響をまとめたものです。
+type SideSection struct {
+	Side    string          `json:"side"`
+	Label   string          `json:"label"`
+	Impacts []ImpactSection `json:"impacts"`
+}
+
+// Weaknesses は立論の中で補強や応答が必要な箇所です。
+type Weaknesses struct {
+	UnansweredRebuttals    []RebuttalEntry      `json:"unanswered_rebuttals"` // 応答していない相手の反論。自分たちの再反論は含まない
+	NodesWithoutImportance []string             `json:"nodes_without_importance"`
+	NodesWithoutUniqueness []string             `json:"nodes_without_uniqueness"`
+	EdgesWithoutCertainty  []domain.EdgeRef     `json:"edges_without_certainty"`
+	WeakestLinks           []domain.WeakestLink `json:"weakest_links"`
+}
+
+// CaseReport はディベーターに渡すための、立論の構造と反論の状況をまとめた資料です。
+type CaseReport struct {
+	Title      string        `json:"title"`
+	Sides      []SideSection `json:"sides"`
+	Weaknesses Weaknesses    `json:"weaknesses"`
+}
+
+// sideLabels は立場の表示名です。
+var sideLabels = map[string]string{
+	domain.SideStatusQuo:       "現状",
+	domain.SideAffirmativePlan: "プラン",
+	"":                         "立場未設定",
+}
+
+// BuildCaseReport はDebateGraphから資料を作成します。
+//
+// 影響は domain.DebateGraph.ImpactNodes が返すノードで、立場ごと・Argumentの辞書順に並べます。
+// 各影響について、前提から影響までの元の主張のエッジだけをたどる連鎖を列挙し、
+// 連鎖に現れるノードとエッジの根拠と、それに向けられた反論・ターンをまとめます。
+// 反論への反論や反論関係への反論は、対象の反論の下に入れ子で並べます。
+// 弱点は domain.BuildGraphReport と domain.AnalyzeWeakestLinks の結果から作成します。
+// 応答していない反論は相手側 (opponent) の反論関係だけで、反論の連鎖の中の自分たちの再反論は弱点に含めません。
+func BuildCaseReport(dg *domain.DebateGraph, title string) (*CaseReport, error) {
+	if dg == nil {
+		return nil, fmt.Errorf("cannot build case report from nil DebateGraph")
+	}
+	snapshot, err := dg.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot debate graph: %w", err)
+	}
+	dg = snapshot
+	graphReport, err := domain.BuildGraphReport(dg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build graph report for case report: %w", err)
+	}
+	weakestLinks, err := domain.AnalyzeWeakestLinks(dg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze weakest links for case report: %w", err)
+	}
+
+	index := newRebuttalIndex(dg)
+	report := &CaseReport{Title: title, Sides: make([]SideSection, 0)}
+
+	sideIndex := make(map[string]int)
+	for _, impact := range dg.ImpactNodes() {
+		section := index.impactSection(impact)
+		i, exists := sideIndex[impact.Side]
+		if !exists {
+			i = len(report.Sides)
+			sideIndex[impact.Side] = i
+			report.Sides = append(report.Sides, SideSection{Side: impact.Side, Label: sideLabel(impact.Side), Impacts: make([]ImpactSection, 0)})
+		}
+		report.Sides[i].Impacts = append(report.Sides[i].Impacts, section)
+	}
+	sort.SliceStable(report.Sides, func(i, j int) bool {
+		return sideOrder(report.Sides[i].Side) < sideOrder(report.Sides[j].Side)
+	})
+
+	report.Weaknesses = Weaknesses{
+		UnansweredRebuttals:    make([]RebuttalEntry, 0, len(graphReport.UnansweredRebuttals)),
+		NodesWithoutImportance: graphReport.NodesWithoutImportance,
+		NodesWithoutUniqueness: graphReport.NodesWithoutUniqueness,
+		EdgesWithoutCertainty:  graphReport.EdgesWithoutCertainty,
+		WeakestLinks:           weakestLinks.Links[:min(len(weakestLinks.Links), maxWeakestLinks)],
+	}
+	for _, relation := range graphReport.UnansweredRebuttals {
+		report.Weaknesses.UnansweredRebuttals = append(report.Weaknesses.UnansweredRebuttals, RebuttalEntry{
+			Kind:     relation.Kind,
+			Label:    rebuttalLabel(relation),
+			Argument: relation.RebuttalArgument,
+		})
+	}
+	return report, nil
+}
+
+func sideLabel(side string) string {
+	if label, exists := sideLabels[side]; exists {
+		return label
+	}
+	return side
+}
+
+// sideOrder は現状、プラン、立場未設定の順に並べるための順位です。
+func sideOrder(side string) int {
+	switch side {
+	case domain.SideStatusQuo:
+		return 0
+	case domain.SideAffirmativePlan:
+		return 1
+	}
+	return 2
+}
+
+// rebuttalLabel は反論関係の種類の表示名を返します。
+func rebuttalLabel(relation domain.RebuttalRelation) string {
+	switch relation.Kind {
+	case domain.RebuttalKindNode:
+		if relation.RebuttalType == "importance" {
+			return "重要性への反論"
+		}
+		return "固有性への反論"
+	case domain.RebuttalKindEdge:
+		if relation.RebuttalType == "certainty" {
+			return "確実性への反論"
+		}
+		return "固有性への反論"
+	case domain.RebuttalKindCounterArgument:
+		return "反対意見"
+	case domain.RebuttalKindTurnArgument:
+		return "ターン"
+	case domain.RebuttalKindRelation:
+		return "反論への再反論"
+	}
+	return relation.Kind
+}
+
+// rebuttalIndex は反論関係を対象ごとに引けるようにしたものです。
+type rebuttalIndex struct {
+	dg         *domain.DebateGraph
+	byNode     map[string][]domain.RebuttalRelation // 対象のノードのArgument
+	byEdge     map[domain.EdgeRef][]domain.RebuttalRelation
+	byRelation map[string][]domain.RebuttalRelation // 対象の RebuttalRelation.Key()
+}
+
+func newRebuttalIndex(dg *domain.DebateGraph) *rebuttalIndex {
+	index := &rebuttalIndex{
+		dg:         dg,
+		byNode:     make(map[string][]domain.RebuttalRelation),
+		byEdge:     make(map[domain.EdgeRef][]domain.RebuttalRelation),
+		byRelation: make(map[string][]domain.RebuttalRelation),
+	}
+	for _, relation := range dg.RebuttalRelations() {
+		switch relation.Kind {
+		case domain.RebuttalKindNode, domain.RebuttalKindCounterArgument:
+			index.byNode[relation.TargetArgument] = append(index.byNode[relation.TargetArgument], relation)
+		case domain.RebuttalKindEdge:
+			ref := domain.EdgeRef{Cause: relation.TargetCauseArgument, Effect: relation.TargetEffectArgument}
+			index.byEdge[ref] = append(index.byEdge[ref], relation)
+		case domain.RebuttalKindTurnArgument:
+			// ターンは認めているノードの下に並べる
+			for _, argument := range relation.TargetCauseArguments {
+				index.byNode[argument] = append(index.byNode[argument], relation)
+			}
+		case domain.RebuttalKindRelation:
+			key := relation.TargetRelation.Key()
+			index.byRelation[key] = append(index.byRelation[key], relation)
+		}
+	}
+	return index
+}
+
+// entries は反論関係を、再反論を入れ子にしたRebuttalEntryに変換します。
+// 反論ノードへの反論と、その反論関係への反論の両方を再反論として扱います。
+func (index *rebuttalIndex) entries(relations []domain.RebuttalRelation, visited map[string]bool) []RebuttalEntry {
+	entries := make([]RebuttalEntry, 0, len(relations))
+	for _, relation := range relations {
+		key := relation.Key()
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+		responses := append(append([]domain.RebuttalRelation(nil), index.byRelation[key]...), index.byNode[relation.RebuttalArgument]...)
+		entries = append(entries, RebuttalEntry{
+			Kind:      relation.Kind,
+			Label:     rebuttalLabel(relation),
+			Argument:  relation.RebuttalArgument,
+			Responses: index.entries(responses, visited),
+		})
+		delete(visited, key)
+	}
+	return entries
+}
+
+func (index *rebuttalIndex) nodeSection(node *domain.DebateGraphNode) NodeSection {
+	return NodeSection{
+		Argument:   node.Argument,
+		Importance: node.Importance,
+		Uniqueness: node.Uniqueness,
+		Rebuttals:  index.entries(index.byNode[node.Argument], make(map[string]bool)),
+	}
+}
+
+func (index *rebuttalIndex) impactSection(impact *domain.DebateGraphNode) ImpactSection {
+	section := ImpactSection{
+		Impact:      index.nodeSection(impact),
+		Polarity:    impact.Polarity,
+		Stakeholder: impact.Stakeholder,
+		Magnitude:   impact.Magnitude,
+		Chains:      make([][]string, 0),
+		Nodes:       make([]NodeSection, 0),
+		Links:       make([]LinkSection, 0),
+	}
+
+	// 影響から元の主張のエッジを逆向きにたどり、前提から影響までの連鎖を列挙する
+	var walk func(node *domain.DebateGraphNode, path []*domain.DebateGraphNode)
+	walk = func(node *domain.DebateGraphNode, path []*domain.DebateGraphNode) {
+		if len(section.Chains) >= maxChainsPerImpact {
+			section.Truncated = true
+			return
+		}
+		path = append([]*domain.DebateGraphNode{node}, path...)
+		causes := make([]*domain.DebateGraphEdge, 0)
+		for _, edge := range node.Causes {
+			if !edge.IsRebuttal && !edge.Cause.IsRebuttal {
+				causes = append(causes, edge)
+			}
+		}
+		if len(causes) == 0 {
+			chain := make([]string, 0, len(path))
+			for _, n := range path {
+				chain = append(chain, n.Argument)
+			}
+			section.Chains = append(section.Chains, chain)
+			return
+		}
+		sort.Slice(causes, func(i, j int) bool { return causes[i].Cause.Argument < causes[j].Cause.Argument })
+		for _, edge := range causes {
+			walk(edge.Cause, path)
+		}
+	}
+	walk(impact, nil)
+
+	// 連鎖に現れるノードとエッジを、前提に近いものから重複なく並べる
+	seenNodes := map[string]bool{impact.Argument: true}
+	seenLinks := make(map[domain.EdgeRef]bool)
+	for _, chain := range section.Chains {
+		for i, argument := range chain {
+			if !seenNodes[argument] {
+				seenNodes[argument] = true
+				node, _ := index.dg.GetNode(argument)
+				section.Nodes = append(section.Nodes, index.nodeSection(node))
+			}
+			if i == 0 {
+				continue
+			}
+			ref := domain.EdgeRef{Cause: chain[i-1], Effect: argument}
+			if seenLinks[ref] {
+				continue
+			}
+			seenLinks[ref] = true
+			edge, _ := index.dg.GetEdge(ref.Cause, ref.Effect)
+			section.Links = append(section.Links, LinkSection{
+				Cause:      ref.Cause,
+				Effect:     ref.Effect,
+				Certainty:  edge.Certainty,
+				Uniqueness: edge.Uniqueness,
+				Rebuttals:  index.entries(index.byEdge[ref], make(map[string]bool)),
+			})
+		}
+	}
+	return section
+}
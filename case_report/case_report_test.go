@@ -0,0 +1,102 @@
+package case_report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+func TestBuildCaseReport(t *testing.T) {
+	dg := domain.NewDebateGraph()
+	for _, argument := range []string{"法人税を減税する", "企業の投資が増える", "雇用が増える"} {
+		require.NoError(t, dg.AddNode(domain.NewDebateGraphNode(argument, false)))
+	}
+	for _, argument := range []string{"内部留保に回る", "投資は景気で決まる"} {
+		require.NoError(t, dg.AddNode(domain.NewDebateGraphNode(argument, true)))
+	}
+	impact, _ := dg.GetNode("雇用が増える")
+	impact.NodeMetadata = domain.NewImpactMetadata(domain.SideAffirmativePlan, domain.PolarityBenefit, "労働者")
+	addEdge := func(cause, effect string) {
+		causeNode, _ := dg.GetNode(cause)
+		effectNode, _ := dg.GetNode(effect)
+		require.NoError(t, dg.AddEdge(domain.NewDebateGraphEdge(causeNode, effectNode, false)))
+	}
+	addEdge("法人税を減税する", "企業の投資が増える")
+	addEdge("企業の投資が増える", "雇用が増える")
+	require.NoError(t, dg.AddEdgeAnnotation("法人税を減税する", "企業の投資が増える", domain.EdgeAnnotationCertainty, domain.Evidence{Claim: "過去の減税で投資が増えた", Citation: "経済白書"}))
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{
+		Kind: domain.RebuttalKindEdge, TargetCauseArgument: "法人税を減税する", TargetEffectArgument: "企業の投資が増える",
+		RebuttalType: "certainty", RebuttalArgument: "内部留保に回る",
+	}))
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{Kind: domain.RebuttalKindCounterArgument, TargetArgument: "内部留保に回る", RebuttalArgument: "投資は景気で決まる"}))
+
+	report, err := BuildCaseReport(dg, "法人税減税")
+	require.NoError(t, err)
+	require.Len(t, report.Sides, 1)
+	assert.Equal(t, "プラン", report.Sides[0].Label)
+	require.Len(t, report.Sides[0].Impacts, 1)
+
+	section := report.Sides[0].Impacts[0]
+	assert.Equal(t, [][]string{{"法人税を減税する", "企業の投資が増える", "雇用が増える"}}, section.Chains)
+	require.Len(t, section.Links, 2)
+	require.Len(t, section.Links[0].Rebuttals, 1)
+	assert.Equal(t, "内部留保に回る", section.Links[0].Rebuttals[0].Argument)
+	// 反論ノードへの反対意見は、その反論への再反論として入れ子になる
+	require.Len(t, section.Links[0].Rebuttals[0].Responses, 1)
+	assert.Equal(t, "投資は景気で決まる", section.Links[0].Rebuttals[0].Responses[0].Argument)
+
+	// 相手の反論には再反論しているため、応答のない反論はない
+	assert.Empty(t, report.Weaknesses.UnansweredRebuttals)
+
+	markdown := report.ToMarkdown()
+	assert.Contains(t, markdown, "# 法人税減税")
+	assert.Contains(t, markdown, "1. 法人税を減税する → 企業の投資が増える → 雇用が増える")
+	assert.Contains(t, markdown, "  - 確実性: 過去の減税で投資が増えた (経済白書)")
+	assert.Contains(t, markdown, "    - [反対意見] 投資は景気で決まる")
+
+	html, err := report.ToHTML()
+	require.NoError(t, err)
+	assert.Contains(t, html, "<title>法人税減税</title>")
+	assert.Contains(t, html, "<style>")
+	assert.Contains(t, html, "内部留保に回る")
+}
+
+func TestBuildCaseReportNestedRebuttalChain(t *testing.T) {
+	dg := domain.NewDebateGraph()
+	for _, argument := range []string{"法人税を減税する", "雇用が増える"} {
+		require.NoError(t, dg.AddNode(domain.NewDebateGraphNode(argument, false)))
+	}
+	for _, argument := range []string{"内部留保に回る", "その反論は投資の統計を無視している", "統計は一時的な変動である", "変動は10年続いている", "雇用は人口で決まる"} {
+		require.NoError(t, dg.AddNode(domain.NewDebateGraphNode(argument, true)))
+	}
+	cause, _ := dg.GetNode("法人税を減税する")
+	effect, _ := dg.GetNode("雇用が増える")
+	require.NoError(t, dg.AddEdge(domain.NewDebateGraphEdge(cause, effect, false)))
+
+	// 相手の反論 ← 自分たちの再反論 ← 相手の再々反論 ← 自分たちの応答 の連鎖
+	edgeRebuttal := domain.RebuttalRelation{
+		Kind: domain.RebuttalKindEdge, TargetCauseArgument: "法人税を減税する", TargetEffectArgument: "雇用が増える",
+		RebuttalType: "certainty", RebuttalArgument: "内部留保に回る",
+	}
+	require.NoError(t, dg.AddRebuttalRelation(edgeRebuttal))
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{Kind: domain.RebuttalKindRelation, TargetRelation: &edgeRebuttal, RebuttalArgument: "その反論は投資の統計を無視している"}))
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{Kind: domain.RebuttalKindCounterArgument, TargetArgument: "その反論は投資の統計を無視している", RebuttalArgument: "統計は一時的な変動である"}))
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{Kind: domain.RebuttalKindCounterArgument, TargetArgument: "統計は一時的な変動である", RebuttalArgument: "変動は10年続いている"}))
+	// 別の相手の反論には応答していない
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{Kind: domain.RebuttalKindCounterArgument, TargetArgument: "雇用が増える", RebuttalArgument: "雇用は人口で決まる"}))
+
+	report, err := BuildCaseReport(dg, "法人税減税")
+	require.NoError(t, err)
+	require.Len(t, report.Weaknesses.UnansweredRebuttals, 1)
+	assert.Equal(t, "雇用は人口で決まる", report.Weaknesses.UnansweredRebuttals[0].Argument)
+
+	markdown := report.ToMarkdown()
+	weaknesses := markdown[strings.Index(markdown, "## 弱点"):]
+	assert.Contains(t, weaknesses, "雇用は人口で決まる")
+	for _, defense := range []string{"その反論は投資の統計を無視している", "変動は10年続いている"} {
+		assert.NotContains(t, weaknesses, defense)
+	}
+}
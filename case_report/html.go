@@ -0,0 +1,34 @@
+package case_report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+//go:embed case_report.html
+var caseReportHTMLTemplate string
+
+var htmlTemplate = template.Must(template.New("case_report").Funcs(template.FuncMap{
+	"evidence":     evidenceText,
+	"weakestLink":  weakestLinkDescription,
+	"joinChain":    func(chain []string) string { return strings.Join(chain, " → ") },
+	"maxChains":    func() int { return maxChainsPerImpact },
+	"impactDetail": func(s ImpactSection) string { return s.details() },
+}).Parse(caseReportHTMLTemplate))
+
+type htmlData struct {
+	Title string
+	*CaseReport
+}
+
+// ToHTML は資料を、外部のファイルを参照しない1つのHTML文書に変換します。
+func (r *CaseReport) ToHTML() (string, error) {
+	var buffer bytes.Buffer
+	if err := htmlTemplate.Execute(&buffer, htmlData{Title: r.displayTitle(), CaseReport: r}); err != nil {
+		return "", fmt.Errorf("failed to render case report as HTML: %w", err)
+	}
+	return buffer.String(), nil
+}
@@ -0,0 +1,153 @@
+package case_report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// defaultTitle はタイトルが指定されていない資料の見出しです。
+const defaultTitle = "ケースブリーフ"
+
+// ToMarkdown は資料をMarkdownの文書に変換します。
+func (r *CaseReport) ToMarkdown() string {
+	var builder strings.Builder
+	builder.WriteString("# " + r.displayTitle() + "\n")
+
+	for _, side := range r.Sides {
+		builder.WriteString("\n## " + side.Label + "\n")
+		for _, impact := range side.Impacts {
+			builder.WriteString("\n### 影響: " + impact.Impact.Argument + "\n\n")
+			if details := impact.details(); details != "" {
+				builder.WriteString("- " + details + "\n")
+			}
+			writeNodeDetails(&builder, impact.Impact, "")
+
+			builder.WriteString("\n#### 因果の連鎖\n\n")
+			for i, chain := range impact.Chains {
+				builder.WriteString(fmt.Sprintf("%d. %s\n", i+1, strings.Join(chain, " → ")))
+			}
+			if impact.Truncated {
+				builder.WriteString(fmt.Sprintf("\n(連鎖が多いため、最初の%d件のみ表示しています)\n", maxChainsPerImpact))
+			}
+
+			if len(impact.Nodes) > 0 {
+				builder.WriteString("\n#### 主張\n\n")
+				for _, node := range impact.Nodes {
+					builder.WriteString("- **" + node.Argument + "**\n")
+					writeNodeDetails(&builder, node, "  ")
+				}
+			}
+			if len(impact.Links) > 0 {
+				builder.WriteString("\n#### 因果関係\n\n")
+				for _, link := range impact.Links {
+					builder.WriteString("- **" + link.Cause + " → " + link.Effect + "**\n")
+					writeEvidenceList(&builder, "  ", "確実性", link.Certainty)
+					writeEvidenceList(&builder, "  ", "固有性", link.Uniqueness)
+					writeRebuttalEntries(&builder, "  ", link.Rebuttals)
+				}
+			}
+		}
+	}
+
+	builder.WriteString("\n## 弱点\n")
+	weaknesses := r.Weaknesses
+	builder.WriteString("\n### 応答していない反論\n\n")
+	if len(weaknesses.UnansweredRebuttals) == 0 {
+		builder.WriteString("なし\n")
+	}
+	writeRebuttalEntries(&builder, "", weaknesses.UnansweredRebuttals)
+	writeStringList(&builder, "重要性の根拠がない主張", weaknesses.NodesWithoutImportance)
+	writeStringList(&builder, "固有性の根拠がない主張", weaknesses.NodesWithoutUniqueness)
+	edges := make([]string, 0, len(weaknesses.EdgesWithoutCertainty))
+	for _, edge := range weaknesses.EdgesWithoutCertainty {
+		edges = append(edges, edge.Cause+" → "+edge.Effect)
+	}
+	writeStringList(&builder, "確実性の根拠がない因果関係", edges)
+	links := make([]string, 0, len(weaknesses.WeakestLinks))
+	for _, link := range weaknesses.WeakestLinks {
+		links = append(links, weakestLinkDescription(link))
+	}
+	writeStringList(&builder, "否定されると影響を失う箇所", links)
+
+	return builder.String()
+}
+
+func (r *CaseReport) displayTitle() string {
+	if r.Title == "" {
+		return defaultTitle
+	}
+	return r.Title
+}
+
+// details は影響のメタデータを1行にまとめた文字列を返します。
+func (s ImpactSection) details() string {
+	parts := make([]string, 0, 3)
+	if s.Polarity != "" {
+		parts = append(parts, "極性: "+s.Polarity)
+	}
+	if s.Stakeholder != "" {
+		parts = append(parts, "利害関係者: "+s.Stakeholder)
+	}
+	if s.Magnitude != nil {
+		parts = append(parts, fmt.Sprintf("大きさ: %g", *s.Magnitude))
+	}
+	return strings.Join(parts, " / ")
+}
+
+func writeNodeDetails(builder *strings.Builder, node NodeSection, indent string) {
+	writeEvidenceList(builder, indent, "重要性", node.Importance)
+	writeEvidenceList(builder, indent, "固有性", node.Uniqueness)
+	writeRebuttalEntries(builder, indent, node.Rebuttals)
+}
+
+func writeEvidenceList(builder *strings.Builder, indent string, label string, evidences []domain.Evidence) {
+	for _, evidence := range evidences {
+		builder.WriteString(indent + "- " + label + ": " + evidenceText(evidence) + "\n")
+	}
+}
+
+func writeRebuttalEntries(builder *strings.Builder, indent string, entries []RebuttalEntry) {
+	for _, entry := range entries {
+		builder.WriteString(indent + "- [" + entry.Label + "] " + entry.Argument + "\n")
+		writeRebuttalEntries(builder, indent+"  ", entry.Responses)
+	}
+}
+
+func writeStringList(builder *strings.Builder, title string, items []string) {
+	builder.WriteString("\n### " + title + "\n\n")
+	if len(items) == 0 {
+		builder.WriteString("なし\n")
+		return
+	}
+	for _, item := range items {
+		builder.WriteString("- " + item + "\n")
+	}
+}
+
+// evidenceText は根拠の主張に、出典の情報があれば括弧書きで付け加えます。
+func evidenceText(evidence domain.Evidence) string {
+	source := make([]string, 0, 4)
+	for _, value := range []string{evidence.Author, evidence.Citation, evidence.Date, evidence.URL} {
+		if value != "" {
+			source = append(source, value)
+		}
+	}
+	if len(source) == 0 {
+		return evidence.Claim
+	}
+	return evidence.Claim + " (" + strings.Join(source, ", ") + ")"
+}
+
+// weakestLinkDescription は弱点を、対象と失われる影響の説明にします。
+func weakestLinkDescription(link domain.WeakestLink) string {
+	target := link.Argument
+	if link.Kind == domain.CutTargetEdge {
+		target = link.Cause + " → " + link.Effect
+	}
+	if len(link.LostImpacts) == 0 {
+		return fmt.Sprintf("%s (期待値が %.2f 減少)", target, link.ExpectedValueLoss)
+	}
+	return fmt.Sprintf("%s (失われる影響: %s)", target, strings.Join(link.LostImpacts, ", "))
+}
@@ -106,9 +106,9 @@ func (creator *RebuttalCreator) CreateRebuttal(
 			for _, pmfRebuttal := range allPMFRebuttals {
 				rebuttalArgument := pmfRebuttal.Rebuttal
 				// ターゲットノードの存在確認
-				targetNode, exists := subGraph.GetNode(pmfRebuttal.TargetArgument)
-				if !exists {
-					log.Printf("WARN: Target node '%s' for PMF rebuttal not found in subGraph. Skipping.", pmfRebuttal.TargetArgument)
+				targetNode, _, err := subGraph.ResolveNode(pmfRebuttal.TargetArgument)
+				if err != nil {
+					log.Printf("WARN: Target node for PMF rebuttal not found in subGraph. Skipping: %v", err)
 					continue
 				}
 
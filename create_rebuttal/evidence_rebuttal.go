@@ -56,7 +56,7 @@ func (finder *EvidenceRebuttalFinder) FindeEvidenceRebuttalFinder(
 	targetEffectNode *domain.DebateGraphNode,
 	targetEdge *domain.DebateGraphEdge) (*EvidenceRebuttals, error) {
 
-	debateGraphJSON, err := debateGraph.ToJSON()
+	debateGraphJSON, err := debateGraph.ToCompactJSON()
 	if err != nil {
 		return nil, fmt.Errorf("JSONに変換に失敗しました: %w", err)
 	}
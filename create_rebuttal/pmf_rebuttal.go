@@ -51,12 +51,12 @@ func (finder *PMFRebuttalFinder) FindPMFRebuttal(
 	debateGraph *domain.DebateGraph,
 	subGraph *domain.DebateGraph) (*PMFRebuttals, error) {
 
-	debateGraphJSON, err := debateGraph.ToJSON()
+	debateGraphJSON, err := debateGraph.ToCompactJSON()
 	if err != nil {
 		return nil, fmt.Errorf("JSONに変換に失敗しました: %w", err)
 	}
 
-	subGraphJSON, err := subGraph.ToJSON()
+	subGraphJSON, err := subGraph.ToCompactJSON()
 	if err != nil {
 		return nil, fmt.Errorf("JSONに変換に失敗しました: %w", err)
 	}
@@ -0,0 +1,432 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// 判定における立場。元の主張 (IsRebuttal = false) を行った側を proponent、反論を行った側を opponent とします。
+const (
+	AdjudicationSideProponent = "proponent"
+	AdjudicationSideOpponent  = "opponent"
+	AdjudicationTie           = "tie"
+)
+
+// 影響の種類
+const (
+	ImpactKindImpact = "impact" // 元の主張の影響
+	ImpactKindTurn   = "turn"   // ターンにより相手側の影響として主張されたもの
+)
+
+// adjudicationEpsilon は反復計算が収束したとみなす変化量です。
+const adjudicationEpsilon = 1e-9
+
+// AdjudicationConfig は反論が成立しているときに、対象の強さをどれだけ割り引くかの設定です。
+// 割引は反論ノード自身の強さに比例し、反論ノードが再反論で弱められていれば割引も小さくなります。
+type AdjudicationConfig struct {
+	EdgeRebuttalDiscount    float64 `json:"edge_rebuttal_discount"`    // エッジへの反論 (certainty, uniqueness) 1件あたりの割引率
+	NodeRebuttalDiscount    float64 `json:"node_rebuttal_discount"`    // ノードへの反論 (importance, uniqueness) 1件あたりの割引率
+	CounterArgumentDiscount float64 `json:"counter_argument_discount"` // 反対意見1件あたりの割引率
+	// 反論関係への反論1件あたりに、対象の反論関係の効き目を弱める割合
+	RelationRebuttalDiscount float64 `json:"relation_rebuttal_discount"`
+}
+
+// DefaultAdjudicationConfig は既定の判定設定を返します。
+func DefaultAdjudicationConfig() AdjudicationConfig {
+	return AdjudicationConfig{
+		EdgeRebuttalDiscount:     0.5,
+		NodeRebuttalDiscount:     0.5,
+		CounterArgumentDiscount:  0.5,
+		RelationRebuttalDiscount: 0.5,
+	}
+}
+
+// Validate は割引率がすべて0〜1の範囲にあることを確認します。
+func (c AdjudicationConfig) Validate() error {
+	for name, value := range map[string]float64{
+		"edge_rebuttal_discount":     c.EdgeRebuttalDiscount,
+		"node_rebuttal_discount":     c.NodeRebuttalDiscount,
+		"counter_argument_discount":  c.CounterArgumentDiscount,
+		"relation_rebuttal_discount": c.RelationRebuttalDiscount,
+	} {
+		if value < 0 || value > 1 || math.IsNaN(value) {
+			return fmt.Errorf("%s must be between 0 and 1, got %v", name, value)
+		}
+	}
+	return nil
+}
+
+// ImpactScore は1つの影響が判定後にどれだけ残っているかを表します。
+type ImpactScore struct {
+	Argument        string  `json:"argument"`
+	Side            string  `json:"side"`               // 影響が有利に働く立場 (AdjudicationSide*)
+	Kind            string  `json:"kind"`               // ImpactKind* のいずれか
+	Polarity        string  `json:"polarity,omitempty"` // ノードのメタデータの影響の向き。不明な場合は空
+	BaseWeight      float64 `json:"base_weight"`        // 反論がない場合の重み (ノードのMagnitude。未推定なら1)
+	Strength        float64 `json:"strength"`           // 前提からの支持と反論による割引を反映した強さ (0〜1)
+	SurvivingWeight float64 `json:"surviving_weight"`   // BaseWeight * Strength
+}
+
+// AdjudicationStep は判定の説明のための計算過程の1ステップです。
+type AdjudicationStep struct {
+	Argument  string  `json:"argument,omitempty"` // ノードに関するステップのとき
+	Cause     string  `json:"cause,omitempty"`    // エッジに関するステップのとき
+	Effect    string  `json:"effect,omitempty"`   // エッジに関するステップのとき
+	Operation string  `json:"operation"`          // "premise", "support", "discount", "impact", "verdict" のいずれか
+	Rebuttal  string  `json:"rebuttal,omitempty"` // 割引の原因となった反論ノード
+	Factor    float64 `json:"factor,omitempty"`   // 掛け合わせた係数
+	Value     float64 `json:"value"`              // ステップ後の値
+	Message   string  `json:"message"`
+}
+
+// AdjudicationResult はDebateGraphの判定結果です。
+type AdjudicationResult struct {
+	Impacts        []ImpactScore      `json:"impacts"`
+	ProponentScore float64            `json:"proponent_score"`
+	OpponentScore  float64            `json:"opponent_score"`
+	Verdict        string             `json:"verdict"` // AdjudicationSideProponent, AdjudicationSideOpponent, AdjudicationTie のいずれか
+	Trace          []AdjudicationStep `json:"trace"`
+}
+
+// adjudicator は判定の計算中の状態を保持します。
+type adjudicator struct {
+	dg     *DebateGraph
+	config AdjudicationConfig
+	order  []*DebateGraphNode
+
+	nodeRebuttals     map[*DebateGraphNode][]*DebateGraphNodeRebuttal
+	edgeRebuttals     map[*DebateGraphEdge][]*DebateGraphEdgeRebuttal
+	counterArguments  map[*DebateGraphNode][]*CounterArgumentRebuttal
+	relationRebuttals map[string][]*RelationRebuttal // キー: 対象の RebuttalRelation.Key()
+
+	strength map[*DebateGraphNode]float64
+}
+
+// Adjudicate はDebateGraphを決定的に評価し、どちらの立場が優勢かを判定します。
+//
+//  1. 原因を持たないノード (前提) の強さを1とし、因果エッジに沿って最も強い支持を結果に伝播させます。
+//  2. エッジへの反論・ノードへの反論・反対意見は、反論ノードの強さに比例して対象を割り引きます。
+//     反論ノード自身も同じ方法で評価されるため、再反論されていない反論ほど強く効きます。
+//  3. 反論関係への反論は、その反論ノードの強さに比例して対象の反論関係の効き目を弱めます。
+//     反論関係への反論もまた反論されうるため、効き目は入れ子の深さに関わらず再帰的に計算します。
+//  4. 元の主張の影響は、メタデータの側と向きから有利になる立場を決めて加算します (impactSide を参照)。
+//     ターンの影響は相手側である opponent に加算します。
+//
+// 影響は ImpactNodes が返すノードとターンのノードで、重みはノードのMagnitude (未推定なら1) です。
+func Adjudicate(dg *DebateGraph, config AdjudicationConfig) (*AdjudicationResult, error) {
+	if dg == nil {
+		return nil, fmt.Errorf("cannot adjudicate nil DebateGraph")
+	}
+	return readLocked(func() (*AdjudicationResult, error) { return adjudicate(dg, config) }, dg)
+}
+
+// adjudicate はロックを取得せずに Adjudicate の判定を行います。
+func adjudicate(dg *DebateGraph, config AdjudicationConfig) (*AdjudicationResult, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid adjudication config: %w", err)
+	}
+	order, err := dg.topologicalOrder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to order debate graph for adjudication: %w", err)
+	}
+
+	a := &adjudicator{
+		dg:                dg,
+		config:            config,
+		order:             order,
+		nodeRebuttals:     make(map[*DebateGraphNode][]*DebateGraphNodeRebuttal),
+		edgeRebuttals:     make(map[*DebateGraphEdge][]*DebateGraphEdgeRebuttal),
+		counterArguments:  make(map[*DebateGraphNode][]*CounterArgumentRebuttal),
+		relationRebuttals: make(map[string][]*RelationRebuttal),
+		strength:          make(map[*DebateGraphNode]float64, len(dg.Nodes)),
+	}
+	for _, r := range dg.NodeRebuttals {
+		a.nodeRebuttals[r.TargetNode] = append(a.nodeRebuttals[r.TargetNode], r)
+	}
+	for _, r := range dg.EdgeRebuttals {
+		a.edgeRebuttals[r.TargetEdge] = append(a.edgeRebuttals[r.TargetEdge], r)
+	}
+	for _, r := range dg.CounterArgumentRebuttals {
+		a.counterArguments[r.TargetNode] = append(a.counterArguments[r.TargetNode], r)
+	}
+	for _, r := range dg.RelationRebuttals {
+		key := r.TargetRelation.Key()
+		a.relationRebuttals[key] = append(a.relationRebuttals[key], r)
+	}
+
+	// 反論ノードの強さは対象ノードの計算に必要だが、反論関係は因果エッジの順序に含まれないため、
+	// 値が変化しなくなるまで順序に沿った計算を繰り返す
+	for _, node := range order {
+		a.strength[node] = 1
+	}
+	for iteration := 0; iteration <= len(order)+1; iteration++ {
+		maxChange := 0.0
+		for _, node := range order {
+			value, _ := a.evaluateNode(node, false)
+			maxChange = math.Max(maxChange, math.Abs(value-a.strength[node]))
+			a.strength[node] = value
+		}
+		if maxChange < adjudicationEpsilon {
+			break
+		}
+	}
+
+	result := &AdjudicationResult{
+		Impacts: make([]ImpactScore, 0),
+		Trace:   make([]AdjudicationStep, 0),
+	}
+	for _, node := range order {
+		_, steps := a.evaluateNode(node, true)
+		result.Trace = append(result.Trace, steps...)
+	}
+
+	for _, impact := range a.impacts() {
+		score := ImpactScore{
+			Argument:   impact.node.Argument,
+			Side:       impact.side,
+			Kind:       impact.kind,
+			Polarity:   impact.node.Polarity,
+			BaseWeight: impact.node.ImpactMagnitude(),
+			Strength:   a.strength[impact.node],
+		}
+		score.SurvivingWeight = score.BaseWeight * score.Strength
+		result.Impacts = append(result.Impacts, score)
+		if score.Side == AdjudicationSideProponent {
+			result.ProponentScore += score.SurvivingWeight
+		} else {
+			result.OpponentScore += score.SurvivingWeight
+		}
+		result.Trace = append(result.Trace, AdjudicationStep{
+			Argument: score.Argument, Operation: "impact", Value: score.SurvivingWeight,
+			Message: fmt.Sprintf("%s impact credited to %s: weight %.3f x strength %.3f", score.Kind, score.Side, score.BaseWeight, score.Strength),
+		})
+	}
+
+	switch {
+	case result.ProponentScore-result.OpponentScore > adjudicationEpsilon:
+		result.Verdict = AdjudicationSideProponent
+	case result.OpponentScore-result.ProponentScore > adjudicationEpsilon:
+		result.Verdict = AdjudicationSideOpponent
+	default:
+		result.Verdict = AdjudicationTie
+	}
+	result.Trace = append(result.Trace, AdjudicationStep{
+		Operation: "verdict", Value: result.ProponentScore - result.OpponentScore,
+		Message: fmt.Sprintf("proponent %.3f vs opponent %.3f: %s", result.ProponentScore, result.OpponentScore, result.Verdict),
+	})
+
+	return result, nil
+}
+
+// evaluateNode は現在の強さの推定値を使ってノードの強さを計算します。
+// withTrace がtrueの場合は説明のためのステップも返します。
+func (a *adjudicator) evaluateNode(node *DebateGraphNode, withTrace bool) (float64, []AdjudicationStep) {
+	var steps []AdjudicationStep
+
+	value := 1.0
+	if len(node.Causes) == 0 {
+		if withTrace {
+			steps = append(steps, AdjudicationStep{
+				Argument: node.Argument, Operation: "premise", Value: value,
+				Message: "premise starts with full strength",
+			})
+		}
+	} else {
+		value = 0
+		var strongest *DebateGraphEdge
+		for _, edge := range a.sortedCauses(node) {
+			weight, edgeSteps := a.evaluateEdge(edge, withTrace)
+			steps = append(steps, edgeSteps...)
+			if support := a.strength[edge.Cause] * weight; support > value || strongest == nil {
+				value, strongest = support, edge
+			}
+		}
+		if withTrace {
+			steps = append(steps, AdjudicationStep{
+				Argument: node.Argument, Cause: strongest.Cause.Argument, Operation: "support", Value: value,
+				Message: fmt.Sprintf("strongest support comes from '%s'", strongest.Cause.Argument),
+			})
+		}
+	}
+
+	for _, r := range a.nodeRebuttals[node] {
+		effectiveness := a.relationEffectiveness(r.Relation())
+		factor := 1 - a.config.NodeRebuttalDiscount*a.strength[r.RebuttalNode]*effectiveness
+		value *= factor
+		if withTrace {
+			steps = append(steps, AdjudicationStep{
+				Argument: node.Argument, Operation: "discount", Rebuttal: r.RebuttalNode.Argument, Factor: factor, Value: value,
+				Message: fmt.Sprintf("%s rebuttal with strength %.3f%s", r.RebuttalType, a.strength[r.RebuttalNode], effectivenessNote(effectiveness)),
+			})
+		}
+	}
+	for _, r := range a.counterArguments[node] {
+		effectiveness := a.relationEffectiveness(r.Relation())
+		factor := 1 - a.config.CounterArgumentDiscount*a.strength[r.RebuttalNode]*effectiveness
+		value *= factor
+		if withTrace {
+			steps = append(steps, AdjudicationStep{
+				Argument: node.Argument, Operation: "discount", Rebuttal: r.RebuttalNode.Argument, Factor: factor, Value: value,
+				Message: fmt.Sprintf("counter argument with strength %.3f%s", a.strength[r.RebuttalNode], effectivenessNote(effectiveness)),
+			})
+		}
+	}
+
+	return value, steps
+}
+
+// evaluateEdge はエッジへの反論による割引を反映したエッジの重みを計算します。
+func (a *adjudicator) evaluateEdge(edge *DebateGraphEdge, withTrace bool) (float64, []AdjudicationStep) {
+	var steps []AdjudicationStep
+	weight := 1.0
+	for _, r := range a.edgeRebuttals[edge] {
+		effectiveness := a.relationEffectiveness(r.Relation())
+		factor := 1 - a.config.EdgeRebuttalDiscount*a.strength[r.RebuttalNode]*effectiveness
+		weight *= factor
+		if withTrace {
+			steps = append(steps, AdjudicationStep{
+				Cause: edge.Cause.Argument, Effect: edge.Effect.Argument, Operation: "discount",
+				Rebuttal: r.RebuttalNode.Argument, Factor: factor, Value: weight,
+				Message: fmt.Sprintf("%s rebuttal on edge with strength %.3f%s", r.RebuttalType, a.strength[r.RebuttalNode], effectivenessNote(effectiveness)),
+			})
+		}
+	}
+	return weight, steps
+}
+
+// relationEffectiveness は反論関係への反論を反映した、反論関係の効き目 (0〜1) を計算します。
+// 反論関係への反論がなければ1です。反論関係は対象より後にしか作成できないため、再帰は必ず終了します。
+func (a *adjudicator) relationEffectiveness(relation RebuttalRelation) float64 {
+	effectiveness := 1.0
+	for _, r := range a.relationRebuttals[relation.Key()] {
+		effectiveness *= 1 - a.config.RelationRebuttalDiscount*a.strength[r.RebuttalNode]*a.relationEffectiveness(r.Relation())
+	}
+	return effectiveness
+}
+
+// effectivenessNote は反論関係の効き目が弱められている場合に、説明に付け加える文を返します。
+func effectivenessNote(effectiveness float64) string {
+	if effectiveness >= 1 {
+		return ""
+	}
+	return fmt.Sprintf(", effectiveness %.3f after rebuttals against the relation", effectiveness)
+}
+
+// sortedCauses はノードへのエッジを原因のArgumentの辞書順で返します。
+func (a *adjudicator) sortedCauses(node *DebateGraphNode) []*DebateGraphEdge {
+	causes := make([]*DebateGraphEdge, len(node.Causes))
+	copy(causes, node.Causes)
+	sort.Slice(causes, func(i, j int) bool { return causes[i].Cause.Argument < causes[j].Cause.Argument })
+	return causes
+}
+
+type adjudicatedImpact struct {
+	node *DebateGraphNode
+	side string
+	kind string
+}
+
+// impacts は判定の対象となる影響を、評価順に返します。
+func (a *adjudicator) impacts() []adjudicatedImpact {
+	turns := make(map[*DebateGraphNode]*TurnArgumentRebuttal)
+	for _, r := range a.dg.TurnArgumentRebuttals {
+		turns[r.RebuttalNode] = r
+	}
+
+	isImpact := make(map[*DebateGraphNode]bool)
+	for _, node := range a.dg.impactNodes() {
+		isImpact[node] = true
+	}
+
+	impacts := make([]adjudicatedImpact, 0)
+	for _, node := range a.order {
+		switch {
+		case turns[node] != nil:
+			// ターンは元の主張を逆手にとった相手側の影響として扱う
+			impacts = append(impacts, adjudicatedImpact{node: node, side: turnSide(turns[node]), kind: ImpactKindTurn})
+		case isImpact[node]:
+			impacts = append(impacts, adjudicatedImpact{node: node, side: impactSide(node.NodeMetadata), kind: ImpactKindImpact})
+		}
+	}
+	return impacts
+}
+
+// impactSide は元の主張の影響が有利に働く立場を、ノードのメタデータの側と向きから決めます。
+// proponent はプランを主張する側なので、プランのメリットと、プランで解消される現状のデメリットが proponent の影響になります。
+// プランのデメリットと、プランで失われる現状のメリットは opponent の影響になります。
+// 向きが不明な場合は、元の主張の影響として proponent の影響とします。
+func impactSide(metadata NodeMetadata) string {
+	switch {
+	case metadata.Polarity == "":
+		return AdjudicationSideProponent
+	case metadata.Side == SideStatusQuo && metadata.Polarity == PolarityHarm,
+		metadata.Side != SideStatusQuo && metadata.Polarity == PolarityBenefit:
+		return AdjudicationSideProponent
+	default:
+		return AdjudicationSideOpponent
+	}
+}
+
+// ImpactNodes は元の主張 (IsRebuttal = false) の影響となるノードをArgumentの辞書順で返します。
+// 役割が impact のノードがあればそのノード、なければ他の元の主張を引き起こさないノードを影響とみなします。
+func (dg *DebateGraph) ImpactNodes() []*DebateGraphNode {
+	dg.mu.RLock()
+	defer dg.mu.RUnlock()
+	return dg.impactNodes()
+}
+
+// impactNodes はロックを取得せずに影響となるノードを返します。
+func (dg *DebateGraph) impactNodes() []*DebateGraphNode {
+	hasImpactRole := false
+	for _, node := range dg.Nodes {
+		if !node.IsRebuttal && node.Role == RoleImpact {
+			hasImpactRole = true
+			break
+		}
+	}
+	causesOriginal := make(map[*DebateGraphNode]bool)
+	for _, edge := range dg.allEdges() {
+		if !edge.Effect.IsRebuttal {
+			causesOriginal[edge.Cause] = true
+		}
+	}
+
+	impacts := make([]*DebateGraphNode, 0)
+	for _, node := range dg.Nodes {
+		if node.IsRebuttal {
+			continue
+		}
+		if (hasImpactRole && node.Role == RoleImpact) || (!hasImpactRole && !causesOriginal[node]) {
+			impacts = append(impacts, node)
+		}
+	}
+	sort.Slice(impacts, func(i, j int) bool { return impacts[i].Argument < impacts[j].Argument })
+	return impacts
+}
+
+// ImpactMagnitude はノードの影響の大きさを返します。未推定の場合は DefaultImpactMagnitude を返します。
+func (n *DebateGraphNode) ImpactMagnitude() float64 {
+	if n.Magnitude == nil {
+		return DefaultImpactMagnitude
+	}
+	return *n.Magnitude
+}
+
+// turnSide はターンの影響が有利に働く立場を返します。ターンは相手の主張を逆手にとるため、ターンを行った側の影響になります。
+// 認めているノードが分かっている場合は、そのノードを主張した側の相手をターンを行った側とします。
+// 再反論に対するターンのように、反論ノードを認めている場合は proponent の影響になります。
+func turnSide(turn *TurnArgumentRebuttal) string {
+	if len(turn.TargetCauseNodes) > 0 {
+		for _, node := range turn.TargetCauseNodes {
+			if !node.IsRebuttal {
+				return AdjudicationSideOpponent
+			}
+		}
+		return AdjudicationSideProponent
+	}
+	if turn.RebuttalNode.IsRebuttal {
+		return AdjudicationSideOpponent
+	}
+	return AdjudicationSideProponent
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdjudicate(t *testing.T) {
+	dg := NewDebateGraph()
+	for _, argument := range []string{"法人税を減税する", "企業の投資が増える", "雇用が増える"} {
+		require.NoError(t, dg.AddNode(NewDebateGraphNode(argument, false)))
+	}
+	addEdge := func(cause, effect string, isRebuttal bool) {
+		causeNode, _ := dg.GetNode(cause)
+		effectNode, _ := dg.GetNode(effect)
+		require.NoError(t, dg.AddEdge(NewDebateGraphEdge(causeNode, effectNode, isRebuttal)))
+	}
+	addEdge("法人税を減税する", "企業の投資が増える", false)
+	addEdge("企業の投資が増える", "雇用が増える", false)
+
+	result, err := Adjudicate(dg, DefaultAdjudicationConfig())
+	require.NoError(t, err)
+	require.Len(t, result.Impacts, 1)
+	assert.Equal(t, "雇用が増える", result.Impacts[0].Argument)
+	assert.InDelta(t, 1.0, result.ProponentScore, 1e-9)
+	assert.Equal(t, AdjudicationSideProponent, result.Verdict)
+
+	// 再反論のないエッジへの反論は、エッジの重みを割り引く
+	require.NoError(t, dg.AddNode(NewDebateGraphNode("減税分は内部留保に回る", true)))
+	require.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{
+		Kind: RebuttalKindEdge, TargetCauseArgument: "企業の投資が増える", TargetEffectArgument: "雇用が増える",
+		RebuttalType: "certainty", RebuttalArgument: "減税分は内部留保に回る",
+	}))
+	// ターンは相手側の影響として扱われる
+	require.NoError(t, dg.AddNode(NewDebateGraphNode("財政赤字が拡大する", true)))
+	addEdge("法人税を減税する", "財政赤字が拡大する", true)
+	require.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{Kind: RebuttalKindTurnArgument, RebuttalArgument: "財政赤字が拡大する"}))
+
+	result, err = Adjudicate(dg, DefaultAdjudicationConfig())
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, result.ProponentScore, 1e-9)
+	assert.InDelta(t, 1.0, result.OpponentScore, 1e-9)
+	assert.Equal(t, AdjudicationSideOpponent, result.Verdict)
+
+	// 反論自体が反対意見で弱められると、割引も小さくなる
+	require.NoError(t, dg.AddNode(NewDebateGraphNode("内部留保も設備投資に使われる", true)))
+	require.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{
+		Kind: RebuttalKindCounterArgument, TargetArgument: "減税分は内部留保に回る", RebuttalArgument: "内部留保も設備投資に使われる",
+	}))
+	result, err = Adjudicate(dg, DefaultAdjudicationConfig())
+	require.NoError(t, err)
+	assert.InDelta(t, 0.75, result.ProponentScore, 1e-9)
+
+	discounted := false
+	for _, step := range result.Trace {
+		if step.Operation == "discount" && step.Rebuttal == "減税分は内部留保に回る" {
+			discounted = true
+			assert.InDelta(t, 0.75, step.Factor, 1e-9)
+		}
+	}
+	assert.True(t, discounted, "割引の理由が説明に含まれるべきです。")
+
+	_, err = Adjudicate(dg, AdjudicationConfig{EdgeRebuttalDiscount: 1.5})
+	assert.Error(t, err)
+}
+
+func TestAdjudicateUsesImpactPolarity(t *testing.T) {
+	dg := NewDebateGraph()
+	for _, argument := range []string{"法人税を減税する", "雇用が増える", "財政赤字が拡大する", "企業の海外移転が続く"} {
+		require.NoError(t, dg.AddNode(NewDebateGraphNode(argument, false)))
+	}
+	setMetadata := func(argument string, metadata NodeMetadata) {
+		node, _ := dg.GetNode(argument)
+		node.NodeMetadata = metadata
+	}
+	addEdge := func(cause, effect string) {
+		causeNode, _ := dg.GetNode(cause)
+		effectNode, _ := dg.GetNode(effect)
+		require.NoError(t, dg.AddEdge(NewDebateGraphEdge(causeNode, effectNode, false)))
+	}
+	addEdge("法人税を減税する", "雇用が増える")
+	addEdge("法人税を減税する", "財政赤字が拡大する")
+	setMetadata("雇用が増える", NewImpactMetadata(SideAffirmativePlan, PolarityBenefit, "労働者"))
+
+	result, err := Adjudicate(dg, DefaultAdjudicationConfig())
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, result.ProponentScore, 1e-9)
+
+	// プランのデメリットは proponent の合計を増やさず、相手側に加算される
+	setMetadata("財政赤字が拡大する", NewImpactMetadata(SideAffirmativePlan, PolarityHarm, "将来世代"))
+	magnitude := 2.0
+	deficit, _ := dg.GetNode("財政赤字が拡大する")
+	deficit.Magnitude = &magnitude
+	result, err = Adjudicate(dg, DefaultAdjudicationConfig())
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, result.ProponentScore, 1e-9)
+	assert.InDelta(t, 2.0, result.OpponentScore, 1e-9)
+	assert.Equal(t, AdjudicationSideOpponent, result.Verdict)
+	for _, impact := range result.Impacts {
+		if impact.Argument == "財政赤字が拡大する" {
+			assert.Equal(t, AdjudicationSideOpponent, impact.Side)
+			assert.Equal(t, PolarityHarm, impact.Polarity)
+		}
+	}
+
+	// プランで解消される現状のデメリットは proponent の影響になる
+	setMetadata("企業の海外移転が続く", NewImpactMetadata(SideStatusQuo, PolarityHarm, "地域経済"))
+	magnitude = 1.5
+	migration, _ := dg.GetNode("企業の海外移転が続く")
+	migration.Magnitude = &magnitude
+	result, err = Adjudicate(dg, DefaultAdjudicationConfig())
+	require.NoError(t, err)
+	assert.InDelta(t, 2.5, result.ProponentScore, 1e-9)
+	assert.Equal(t, AdjudicationSideProponent, result.Verdict)
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultEdgeProbability は確率が未推定のエッジに使う値です。
+// 確率を導入する前のグラフと同じく、因果関係は確実に成立するものとして扱います。
+const DefaultEdgeProbability = 1.0
+
+// DefaultImpactMagnitude は大きさが未推定の影響ノードに使う値です。
+const DefaultImpactMagnitude = 1.0
+
+// CausalProbability はエッジの確率を返します。未推定の場合は DefaultEdgeProbability を返します。
+func (e *DebateGraphEdge) CausalProbability() float64 {
+	if e.Probability == nil {
+		return DefaultEdgeProbability
+	}
+	return *e.Probability
+}
+
+// NodeBelief はノードの主張が成立する確率です。
+type NodeBelief struct {
+	Argument    string  `json:"argument"`
+	Probability float64 `json:"probability"`
+}
+
+// ImpactExpectation は影響ノードの期待値です。
+type ImpactExpectation struct {
+	Argument      string  `json:"argument"`
+	Probability   float64 `json:"probability"`
+	Magnitude     float64 `json:"magnitude"`
+	ExpectedValue float64 `json:"expected_value"` // Probability * Magnitude
+}
+
+// EdgeSensitivity はエッジの確率が影響の期待値の合計にどれだけ効いているかを表します。
+// Swing はエッジが確実に成立する場合 (確率1) と成立しない場合 (確率0) の期待値の合計の差です。
+type EdgeSensitivity struct {
+	Cause       string  `json:"cause"`
+	Effect      string  `json:"effect"`
+	Probability float64 `json:"probability"`
+	Swing       float64 `json:"swing"`
+}
+
+// BeliefPropagationResult は確率の伝播結果です。
+// Edges は Swing の大きい順に並ぶため、先頭ほど議論全体にとって重要な因果関係です。
+type BeliefPropagationResult struct {
+	Nodes              []NodeBelief        `json:"nodes"`
+	Impacts            []ImpactExpectation `json:"impacts"`
+	TotalExpectedValue float64             `json:"total_expected_value"`
+	Edges              []EdgeSensitivity   `json:"edges"`
+}
+
+// PropagateBeliefs はエッジの確率を前提から結果へ伝播させ、各影響の期待値を計算します。
+// 原因を持たないノードは確率1で成立するものとし、複数の原因を持つノードの確率は
+// 各原因が独立に結果を引き起こすとみなす noisy-OR で計算します。
+//
+//	P(結果) = 1 - Π (1 - P(原因) * P(エッジ))
+//
+// 影響は ImpactNodes が返すノードで、期待値は P(影響) * Magnitude です。反論関係は考慮しません。
+func PropagateBeliefs(dg *DebateGraph) (*BeliefPropagationResult, error) {
+	if dg == nil {
+		return nil, fmt.Errorf("cannot propagate beliefs on nil DebateGraph")
+	}
+	return readLocked(func() (*BeliefPropagationResult, error) { return propagateBeliefs(dg) }, dg)
+}
+
+// propagateBeliefs はロックを取得せずに PropagateBeliefs の計算を行います。
+func propagateBeliefs(dg *DebateGraph) (*BeliefPropagationResult, error) {
+	order, err := dg.topologicalOrder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to order debate graph for belief propagation: %w", err)
+	}
+	impacts := dg.impactNodes()
+
+	probabilities := propagateProbabilities(order, nil, nil, 0)
+
+	result := &BeliefPropagationResult{
+		Nodes:   make([]NodeBelief, 0, len(order)),
+		Impacts: make([]ImpactExpectation, 0, len(impacts)),
+		Edges:   make([]EdgeSensitivity, 0),
+	}
+	for _, node := range order {
+		result.Nodes = append(result.Nodes, NodeBelief{Argument: node.Argument, Probability: probabilities[node]})
+	}
+	for _, node := range impacts {
+		expectation := ImpactExpectation{
+			Argument:    node.Argument,
+			Probability: probabilities[node],
+			Magnitude:   node.ImpactMagnitude(),
+		}
+		expectation.ExpectedValue = expectation.Probability * expectation.Magnitude
+		result.Impacts = append(result.Impacts, expectation)
+		result.TotalExpectedValue += expectation.ExpectedValue
+	}
+
+	// 各エッジの確率を0と1に固定したときの期待値の合計の差を感度とする
+	for _, edge := range dg.allEdges() {
+		withEdge := totalExpectedValue(impacts, propagateProbabilities(order, nil, edge, 1))
+		withoutEdge := totalExpectedValue(impacts, propagateProbabilities(order, nil, edge, 0))
+		result.Edges = append(result.Edges, EdgeSensitivity{
+			Cause:       edge.Cause.Argument,
+			Effect:      edge.Effect.Argument,
+			Probability: edge.CausalProbability(),
+			Swing:       withEdge - withoutEdge,
+		})
+	}
+	sort.SliceStable(result.Edges, func(i, j int) bool {
+		return result.Edges[i].Swing > result.Edges[j].Swing
+	})
+
+	return result, nil
+}
+
+// propagateProbabilities はトポロジカル順序に沿って各ノードの確率を計算します。
+// cutNode がnilでない場合、そのノードは成立しない (確率0) ものとします。
+// fixedEdge がnilでない場合、そのエッジの確率を fixedProbability に置き換えます。
+func propagateProbabilities(order []*DebateGraphNode, cutNode *DebateGraphNode, fixedEdge *DebateGraphEdge, fixedProbability float64) map[*DebateGraphNode]float64 {
+	probabilities := make(map[*DebateGraphNode]float64, len(order))
+	for _, node := range order {
+		if node == cutNode {
+			probabilities[node] = 0
+			continue
+		}
+		if len(node.Causes) == 0 {
+			probabilities[node] = 1
+			continue
+		}
+		failure := 1.0
+		for _, edge := range node.Causes {
+			edgeProbability := edge.CausalProbability()
+			if edge == fixedEdge {
+				edgeProbability = fixedProbability
+			}
+			failure *= 1 - probabilities[edge.Cause]*edgeProbability
+		}
+		probabilities[node] = 1 - failure
+	}
+	return probabilities
+}
+
+func totalExpectedValue(impacts []*DebateGraphNode, probabilities map[*DebateGraphNode]float64) float64 {
+	total := 0.0
+	for _, node := range impacts {
+		total += probabilities[node] * node.ImpactMagnitude()
+	}
+	return total
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropagateBeliefs(t *testing.T) {
+	dg := NewDebateGraph()
+	for _, argument := range []string{"法人税を減税する", "企業の投資が増える", "海外企業が進出する", "雇用が増える"} {
+		require.NoError(t, dg.AddNode(NewDebateGraphNode(argument, false)))
+	}
+	addEdge := func(cause, effect string, probability float64) {
+		causeNode, _ := dg.GetNode(cause)
+		effectNode, _ := dg.GetNode(effect)
+		edge := NewDebateGraphEdge(causeNode, effectNode, false)
+		edge.Probability = &probability
+		require.NoError(t, dg.AddEdge(edge))
+	}
+	addEdge("法人税を減税する", "企業の投資が増える", 0.8)
+	addEdge("法人税を減税する", "海外企業が進出する", 0.5)
+	addEdge("企業の投資が増える", "雇用が増える", 0.5)
+	addEdge("海外企業が進出する", "雇用が増える", 0.2)
+	magnitude := 2.0
+	employment, _ := dg.GetNode("雇用が増える")
+	employment.Magnitude = &magnitude
+
+	result, err := PropagateBeliefs(dg)
+	require.NoError(t, err)
+
+	// P(雇用) = 1 - (1 - 0.8*0.5) * (1 - 0.5*0.2) = 0.46
+	require.Len(t, result.Impacts, 1)
+	assert.InDelta(t, 0.46, result.Impacts[0].Probability, 1e-9)
+	assert.InDelta(t, 0.92, result.TotalExpectedValue, 1e-9)
+
+	// 投資の経路の方が期待値への寄与が大きい
+	require.Len(t, result.Edges, 4)
+	assert.Equal(t, "企業の投資が増える", result.Edges[0].Cause)
+	assert.Equal(t, "雇用が増える", result.Edges[0].Effect)
+
+	jsonData, err := dg.ToJSON()
+	require.NoError(t, err)
+	restored, err := NewDebateGraphFromJSON(jsonData)
+	require.NoError(t, err)
+	edge, err := restored.ResolveEdge("企業の投資が増える", "雇用が増える")
+	require.NoError(t, err)
+	require.NotNil(t, edge.Probability)
+	assert.InDelta(t, 0.5, *edge.Probability, 1e-9)
+
+	edge.Probability = new(float64)
+	*edge.Probability = 1.5
+	jsonData, err = restored.ToJSON()
+	require.NoError(t, err)
+	_, err = NewDebateGraphFromJSON(jsonData)
+	assert.Error(t, err)
+}
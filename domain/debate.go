@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Team はディベートに参加するチームです。
+type Team struct {
+	Name    string   `json:"name"`
+	Side    string   `json:"side"` // AdjudicationSideProponent または AdjudicationSideOpponent
+	Members []string `json:"members,omitempty"`
+}
+
+// Speech はディベート中の1回のスピーチです。
+type Speech struct {
+	ID      string `json:"id"`
+	Speaker string `json:"speaker"`
+	Side    string `json:"side"` // AdjudicationSideProponent または AdjudicationSideOpponent
+	Text    string `json:"text"`
+}
+
+// Debate は論題・チーム・スピーチの履歴と、各スピーチを反映したDebateGraphの版をまとめたものです。
+// Revisions[i] は Speeches[i] までを反映したグラフで、Speeches と常に同じ長さです。
+type Debate struct {
+	Motion    string
+	Teams     []Team
+	Speeches  []Speech
+	Revisions []*DebateGraph
+}
+
+// DebateReplayStep はスピーチ1回分の再生結果です。
+// Diff は直前の版からこのスピーチの版への差分で、最初のスピーチでは空のグラフからの差分です。
+type DebateReplayStep struct {
+	Speech Speech       `json:"speech"`
+	Diff   *GraphDiff   `json:"diff"`
+	Before *DebateGraph `json:"-"`
+	After  *DebateGraph `json:"-"`
+}
+
+// NewDebate は論題とチームから、スピーチのないDebateを作成します。
+func NewDebate(motion string, teams []Team) (*Debate, error) {
+	for _, team := range teams {
+		if err := validateSpeechSide(team.Side); err != nil {
+			return nil, fmt.Errorf("invalid side for team '%s': %w", team.Name, err)
+		}
+	}
+	return &Debate{
+		Motion:    motion,
+		Teams:     teams,
+		Speeches:  make([]Speech, 0),
+		Revisions: make([]*DebateGraph, 0),
+	}, nil
+}
+
+func validateSpeechSide(side string) error {
+	if side != AdjudicationSideProponent && side != AdjudicationSideOpponent {
+		return fmt.Errorf("side must be '%s' or '%s', got '%s'", AdjudicationSideProponent, AdjudicationSideOpponent, side)
+	}
+	return nil
+}
+
+// LatestGraph は最後のスピーチを反映したグラフを返します。スピーチがない場合は空のグラフを返します。
+func (d *Debate) LatestGraph() *DebateGraph {
+	if len(d.Revisions) == 0 {
+		return NewDebateGraph()
+	}
+	return d.Revisions[len(d.Revisions)-1]
+}
+
+// NextRevision は次のスピーチを反映するための、最新の版の複製を返します。
+// 複製に変更を加えてから AddSpeech に渡すことで、過去の版は変更されずに残ります。
+func (d *Debate) NextRevision() (*DebateGraph, error) {
+	return d.LatestGraph().Clone()
+}
+
+// GraphAfter は指定したスピーチまでを反映したグラフを返します。
+func (d *Debate) GraphAfter(speechID string) (*DebateGraph, error) {
+	for i, speech := range d.Speeches {
+		if speech.ID == speechID {
+			return d.Revisions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("speech '%s' not found in debate", speechID)
+}
+
+// AddSpeech はスピーチと、そのスピーチを反映した新しい版のグラフを履歴に追加します。
+// 直前の版に存在しないノードのうち IntroducedIn が空のものには、このスピーチのIDを記録します。
+// revision に直前の版と同じグラフを渡すと過去の版が変更されるため、NextRevision で得た複製を使用してください。
+func (d *Debate) AddSpeech(speech Speech, revision *DebateGraph) error {
+	if speech.ID == "" {
+		return fmt.Errorf("speech ID must not be empty")
+	}
+	for _, existing := range d.Speeches {
+		if existing.ID == speech.ID {
+			return fmt.Errorf("speech with ID '%s' already exists in debate", speech.ID)
+		}
+	}
+	if err := validateSpeechSide(speech.Side); err != nil {
+		return fmt.Errorf("invalid side for speech '%s': %w", speech.ID, err)
+	}
+	if revision == nil {
+		return fmt.Errorf("revision for speech '%s' must not be nil", speech.ID)
+	}
+	previous := d.LatestGraph()
+	if len(d.Revisions) > 0 && revision == previous {
+		return fmt.Errorf("revision for speech '%s' must be a new graph, not the previous revision", speech.ID)
+	}
+
+	revision.mu.Lock()
+	for _, node := range revision.Nodes {
+		if _, exists := previous.GetNode(node.Argument); !exists && node.IntroducedIn == "" {
+			node.IntroducedIn = speech.ID
+		}
+	}
+	revision.mu.Unlock()
+
+	d.Speeches = append(d.Speeches, speech)
+	d.Revisions = append(d.Revisions, revision)
+	return nil
+}
+
+// Replay はスピーチを順にたどり、各スピーチによるグラフの変更内容を返します。
+func (d *Debate) Replay() ([]DebateReplayStep, error) {
+	steps := make([]DebateReplayStep, 0, len(d.Speeches))
+	before := NewDebateGraph()
+	for i, speech := range d.Speeches {
+		after := d.Revisions[i]
+		diff, err := DiffDebateGraphs(before, after)
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff revision for speech '%s': %w", speech.ID, err)
+		}
+		steps = append(steps, DebateReplayStep{Speech: speech, Diff: diff, Before: before, After: after})
+		before = after
+	}
+	return steps, nil
+}
+
+type jsonSpeech struct {
+	Speech
+	Graph json.RawMessage `json:"graph"`
+}
+
+type jsonDebate struct {
+	Motion   string       `json:"motion"`
+	Teams    []Team       `json:"teams"`
+	Speeches []jsonSpeech `json:"speeches"`
+}
+
+// ToJSON はDebateをJSON文字列に変換します。各スピーチには、そのスピーチを反映したグラフが含まれます。
+func (d *Debate) ToJSON() (string, error) {
+	if d == nil {
+		return "", fmt.Errorf("cannot convert nil Debate to JSON")
+	}
+
+	jDebate := jsonDebate{
+		Motion:   d.Motion,
+		Teams:    d.Teams,
+		Speeches: make([]jsonSpeech, 0, len(d.Speeches)),
+	}
+	if jDebate.Teams == nil {
+		jDebate.Teams = make([]Team, 0)
+	}
+	for i, speech := range d.Speeches {
+		graphJSON, err := d.Revisions[i].ToCompactJSON()
+		if err != nil {
+			return "", fmt.Errorf("failed to convert revision for speech '%s' to JSON: %w", speech.ID, err)
+		}
+		jDebate.Speeches = append(jDebate.Speeches, jsonSpeech{Speech: speech, Graph: json.RawMessage(graphJSON)})
+	}
+
+	jsonData, err := json.MarshalIndent(jDebate, "", "    ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Debate to JSON: %w", err)
+	}
+	return string(jsonData), nil
+}
+
+// NewDebateFromJSON はJSON文字列からDebateを復元します。
+// スピーチは AddSpeech と同じ検証を受け、記録されていない IntroducedIn は補完されます。
+func NewDebateFromJSON(jsonData string) (*Debate, error) {
+	var jDebate jsonDebate
+	if err := json.Unmarshal([]byte(jsonData), &jDebate); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON to Debate: %w", err)
+	}
+
+	debate, err := NewDebate(jDebate.Motion, jDebate.Teams)
+	if err != nil {
+		return nil, err
+	}
+	for _, jSpeech := range jDebate.Speeches {
+		if len(jSpeech.Graph) == 0 {
+			return nil, fmt.Errorf("graph for speech '%s' is missing", jSpeech.ID)
+		}
+		revision, err := NewDebateGraphFromJSON(string(jSpeech.Graph))
+		if err != nil {
+			return nil, fmt.Errorf("invalid graph for speech '%s': %w", jSpeech.ID, err)
+		}
+		if err := debate.AddSpeech(jSpeech.Speech, revision); err != nil {
+			return nil, err
+		}
+	}
+	return debate, nil
+}
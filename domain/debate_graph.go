@@ -1,19 +1,29 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // DebateGraphNode と DebateGraphEdge の定義は変更なし
 type DebateGraphNode struct {
 	Argument            string
 	Causes              []*DebateGraphEdge
-	Importance          []string
-	Uniqueness          []string
-	ImportanceRebuttals []string
-	UniquenessRebuttals []string
+	Importance          []Evidence
+	Uniqueness          []Evidence
+	ImportanceRebuttals []Evidence
+	UniquenessRebuttals []Evidence
+	Sources             []SourceSpan // ノードが述べられている文書中の範囲
+	Magnitude           *float64     // 影響の大きさ (0以上)。未推定の場合はnil
+	IntroducedIn        string       // ノードを導入したスピーチのID。不明な場合は空
+	NodeMetadata
 
 	IsRebuttal bool
 }
@@ -21,10 +31,12 @@ type DebateGraphNode struct {
 type DebateGraphEdge struct {
 	Cause               *DebateGraphNode
 	Effect              *DebateGraphNode
-	Certainty           []string
-	Uniqueness          []string
-	CertaintyRebuttal   []string
-	UniquenessRebuttals []string
+	Certainty           []Evidence
+	Uniqueness          []Evidence
+	CertaintyRebuttal   []Evidence
+	UniquenessRebuttals []Evidence
+	Sources             []SourceSpan // エッジの根拠となった文書中の範囲
+	Probability         *float64     // 原因が結果を引き起こす確率 (0〜1)。未推定の場合はnil
 
 	IsRebuttal bool
 }
@@ -33,10 +45,11 @@ func NewDebateGraphNode(argument string, isRebuttal bool) *DebateGraphNode {
 	return &DebateGraphNode{
 		Argument:            argument,
 		Causes:              make([]*DebateGraphEdge, 0),
-		Importance:          make([]string, 0),
-		Uniqueness:          make([]string, 0),
-		ImportanceRebuttals: make([]string, 0),
-		UniquenessRebuttals: make([]string, 0),
+		Importance:          make([]Evidence, 0),
+		Uniqueness:          make([]Evidence, 0),
+		ImportanceRebuttals: make([]Evidence, 0),
+		UniquenessRebuttals: make([]Evidence, 0),
+		Sources:             make([]SourceSpan, 0),
 		IsRebuttal:          isRebuttal,
 	}
 }
@@ -45,25 +58,42 @@ func NewDebateGraphEdge(cause, effect *DebateGraphNode, isRebuttal bool) *Debate
 	return &DebateGraphEdge{
 		Cause:               cause,
 		Effect:              effect,
-		Certainty:           make([]string, 0),
-		Uniqueness:          make([]string, 0),
-		CertaintyRebuttal:   make([]string, 0),
-		UniquenessRebuttals: make([]string, 0),
+		Certainty:           make([]Evidence, 0),
+		Uniqueness:          make([]Evidence, 0),
+		CertaintyRebuttal:   make([]Evidence, 0),
+		UniquenessRebuttals: make([]Evidence, 0),
+		Sources:             make([]SourceSpan, 0),
 		IsRebuttal:          isRebuttal,
 	}
 }
 
+// DebateGraph はディベートの議論を表すグラフです。
+//
+// ノード・エッジ・反論関係の追加と削除、GetNode などの参照、アノテーションの追加、JSONへの変換、
+// および TopologicalOrder や Adjudicate などこのパッケージの解析は、内部のロックを1回だけ取得して行うため、
+// 複数のゴルーチンから変更と並行して呼び出せます。パッケージの内部では、公開関数がロックを取得し、
+// ロックを取得しない内部の関数 (getNode, allEdges, topologicalOrder など) にグラフを渡します。
+//
+// Nodes などの公開フィールドを直接読み書きする場合は保護されません。描画や出力のように他のパッケージで
+// 公開フィールドを読み取る処理は、並行して変更される可能性がある場合、Clone で得たスナップショットを使用してください。
 type DebateGraph struct {
 	Nodes                    []*DebateGraphNode
 	NodeRebuttals            []*DebateGraphNodeRebuttal
 	EdgeRebuttals            []*DebateGraphEdgeRebuttal
 	CounterArgumentRebuttals []*CounterArgumentRebuttal
 	TurnArgumentRebuttals    []*TurnArgumentRebuttal
+	RelationRebuttals        []*RelationRebuttal
 
 	nodeMap map[string]*DebateGraphNode // 小文字で非公開にし、メソッド経由でアクセス
 	edgeMap map[string]*DebateGraphEdge // キー: "CauseArgument->EffectArgument"
+
+	mu sync.RWMutex // nodeMap, edgeMap, Nodes, Causes, 反論関係のリスト, アノテーションを保護する
+	id uint64       // 複数のグラフをロックする順序を決める、作成順の番号
 }
 
+// debateGraphCount は作成したDebateGraphの数で、DebateGraph.id の採番に使用します。
+var debateGraphCount atomic.Uint64
+
 func NewDebateGraph() *DebateGraph {
 	return &DebateGraph{
 		Nodes:         make([]*DebateGraphNode, 0),
@@ -71,6 +101,7 @@ func NewDebateGraph() *DebateGraph {
 		EdgeRebuttals: make([]*DebateGraphEdgeRebuttal, 0),
 		nodeMap:       make(map[string]*DebateGraphNode),
 		edgeMap:       make(map[string]*DebateGraphEdge),
+		id:            debateGraphCount.Add(1),
 	}
 }
 
@@ -80,6 +111,13 @@ func (dg *DebateGraph) AddNode(node *DebateGraphNode) error {
 	if node == nil {
 		return fmt.Errorf("cannot add a nil node to DebateGraph")
 	}
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	return dg.addNode(node)
+}
+
+// addNode はロックを取得せずにノードを追加します。
+func (dg *DebateGraph) addNode(node *DebateGraphNode) error {
 	if _, exists := dg.nodeMap[node.Argument]; exists {
 		// 既に存在する場合、エラーを返すか、既存ノードを返すか、何もしないかは設計次第。
 		// ここではエラーとして、呼び出し元に重複を通知します。
@@ -92,6 +130,13 @@ func (dg *DebateGraph) AddNode(node *DebateGraphNode) error {
 
 // GetNode はArgument文字列によってノードを取得します。
 func (dg *DebateGraph) GetNode(argument string) (*DebateGraphNode, bool) {
+	dg.mu.RLock()
+	defer dg.mu.RUnlock()
+	return dg.getNode(argument)
+}
+
+// getNode はロックを取得せずにノードを取得します。
+func (dg *DebateGraph) getNode(argument string) (*DebateGraphNode, bool) {
 	node, exists := dg.nodeMap[argument]
 	return node, exists
 }
@@ -111,7 +156,13 @@ func (dg *DebateGraph) AddEdge(edge *DebateGraphEdge) error {
 	if edge.Cause == nil || edge.Effect == nil {
 		return fmt.Errorf("edge must have valid cause and effect nodes")
 	}
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	return dg.addEdge(edge)
+}
 
+// addEdge はロックを取得せずにエッジを追加します。
+func (dg *DebateGraph) addEdge(edge *DebateGraphEdge) error {
 	// エッジが参照するノードがグラフに存在することを確認
 	if _, exists := dg.nodeMap[edge.Cause.Argument]; !exists {
 		return fmt.Errorf("cause node '%s' of the edge is not in the graph", edge.Cause.Argument)
@@ -137,7 +188,15 @@ func (dg *DebateGraph) AddEdge(edge *DebateGraphEdge) error {
 	return nil
 }
 
+// RemoveEdge はグラフからエッジを削除し、EffectノードのCausesリストからも取り除きます。
 func (dg *DebateGraph) RemoveEdge(causeArgument, effectArgument string) error {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	return dg.removeEdge(causeArgument, effectArgument)
+}
+
+// removeEdge はロックを取得せずにエッジを削除します。
+func (dg *DebateGraph) removeEdge(causeArgument, effectArgument string) error {
 	edgeKey := generateEdgeKey(causeArgument, effectArgument)
 	edge, exists := dg.edgeMap[edgeKey]
 	if !exists {
@@ -163,21 +222,45 @@ func (dg *DebateGraph) RemoveEdge(causeArgument, effectArgument string) error {
 	return nil
 }
 
+// GetEdge はCauseとEffectのArgument文字列によってエッジを取得します。
 func (dg *DebateGraph) GetEdge(causeArgument string, effectArgument string) (*DebateGraphEdge, bool) {
-	edgeKey := generateEdgeKey(causeArgument, effectArgument)
-	edge, exists := dg.edgeMap[edgeKey]
+	dg.mu.RLock()
+	defer dg.mu.RUnlock()
+	return dg.getEdge(causeArgument, effectArgument)
+}
+
+// getEdge はロックを取得せずにエッジを取得します。
+func (dg *DebateGraph) getEdge(causeArgument string, effectArgument string) (*DebateGraphEdge, bool) {
+	edge, exists := dg.edgeMap[generateEdgeKey(causeArgument, effectArgument)]
 	return edge, exists
 }
 
+// GetAllEdges はグラフ内の全エッジを (Cause, Effect) の辞書順で返します。
+// edgeMapの走査順に依存しないため、同じグラフからは常に同じ順序が得られます。
 func (dg *DebateGraph) GetAllEdges() []*DebateGraphEdge {
+	dg.mu.RLock()
+	defer dg.mu.RUnlock()
+	return dg.allEdges()
+}
+
+// allEdges はロックを取得せずに GetAllEdges と同じ順序の全エッジを返します。
+func (dg *DebateGraph) allEdges() []*DebateGraphEdge {
 	edges := make([]*DebateGraphEdge, 0, len(dg.edgeMap))
 	for _, edge := range dg.edgeMap {
 		edges = append(edges, edge)
 	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Cause.Argument != edges[j].Cause.Argument {
+			return edges[i].Cause.Argument < edges[j].Cause.Argument
+		}
+		return edges[i].Effect.Argument < edges[j].Effect.Argument
+	})
 	return edges
 }
 
 func (dg *DebateGraph) DisplayGraph() {
+	dg.mu.RLock()
+	defer dg.mu.RUnlock()
 	fmt.Println("--- Debate Graph ---")
 
 	if len(dg.Nodes) == 0 {
@@ -190,16 +273,16 @@ func (dg *DebateGraph) DisplayGraph() {
 	for i, node := range dg.Nodes {
 		fmt.Printf("[%d] Argument: %s\n", i, node.Argument)
 		if len(node.Importance) > 0 {
-			fmt.Printf("    Importance: %s\n", strings.Join(node.Importance, ", "))
+			fmt.Printf("    Importance: %s\n", strings.Join(EvidenceClaims(node.Importance), ", "))
 		}
 		if len(node.Uniqueness) > 0 {
-			fmt.Printf("    Uniqueness: %s\n", strings.Join(node.Uniqueness, ", "))
+			fmt.Printf("    Uniqueness: %s\n", strings.Join(EvidenceClaims(node.Uniqueness), ", "))
 		}
 		if len(node.ImportanceRebuttals) > 0 {
-			fmt.Printf("    Importance Rebuttals: %s\n", strings.Join(node.ImportanceRebuttals, ", "))
+			fmt.Printf("    Importance Rebuttals: %s\n", strings.Join(EvidenceClaims(node.ImportanceRebuttals), ", "))
 		}
 		if len(node.UniquenessRebuttals) > 0 {
-			fmt.Printf("    Uniqueness Rebuttals: %s\n", strings.Join(node.UniquenessRebuttals, ", "))
+			fmt.Printf("    Uniqueness Rebuttals: %s\n", strings.Join(EvidenceClaims(node.UniquenessRebuttals), ", "))
 		}
 		// Display incoming edges (causes for this node)
 		if len(node.Causes) > 0 {
@@ -207,13 +290,13 @@ func (dg *DebateGraph) DisplayGraph() {
 			for _, edge := range node.Causes {
 				fmt.Printf("      - From: %s (Certainty: %s, Uniqueness: %s)\n",
 					edge.Cause.Argument,
-					strings.Join(edge.Certainty, ", "),
-					strings.Join(edge.Uniqueness, ", "))
+					strings.Join(EvidenceClaims(edge.Certainty), ", "),
+					strings.Join(EvidenceClaims(edge.Uniqueness), ", "))
 				if len(edge.CertaintyRebuttal) > 0 {
-					fmt.Printf("        Certainty Rebuttals: %s\n", strings.Join(edge.CertaintyRebuttal, ", "))
+					fmt.Printf("        Certainty Rebuttals: %s\n", strings.Join(EvidenceClaims(edge.CertaintyRebuttal), ", "))
 				}
 				if len(edge.UniquenessRebuttals) > 0 {
-					fmt.Printf("        Uniqueness Rebuttals: %s\n", strings.Join(edge.UniquenessRebuttals, ", "))
+					fmt.Printf("        Uniqueness Rebuttals: %s\n", strings.Join(EvidenceClaims(edge.UniquenessRebuttals), ", "))
 				}
 			}
 		}
@@ -224,37 +307,39 @@ func (dg *DebateGraph) DisplayGraph() {
 	if len(dg.edgeMap) == 0 {
 		fmt.Println("No edges in the graph.")
 	} else {
-		i := 0
-		for key, edge := range dg.edgeMap {
-			fmt.Printf("[%d] Edge: %s\n", i, key)
+		for i, edge := range dg.allEdges() {
+			fmt.Printf("[%d] Edge: %s\n", i, generateEdgeKey(edge.Cause.Argument, edge.Effect.Argument))
 			fmt.Printf("    Cause: %s\n", edge.Cause.Argument)
 			fmt.Printf("    Effect: %s\n", edge.Effect.Argument)
 			if len(edge.Certainty) > 0 {
-				fmt.Printf("    Certainty: %s\n", strings.Join(edge.Certainty, ", "))
+				fmt.Printf("    Certainty: %s\n", strings.Join(EvidenceClaims(edge.Certainty), ", "))
 			}
 			if len(edge.Uniqueness) > 0 {
-				fmt.Printf("    Uniqueness: %s\n", strings.Join(edge.Uniqueness, ", "))
+				fmt.Printf("    Uniqueness: %s\n", strings.Join(EvidenceClaims(edge.Uniqueness), ", "))
 			}
 			if len(edge.CertaintyRebuttal) > 0 {
-				fmt.Printf("    Certainty Rebuttals: %s\n", strings.Join(edge.CertaintyRebuttal, ", "))
+				fmt.Printf("    Certainty Rebuttals: %s\n", strings.Join(EvidenceClaims(edge.CertaintyRebuttal), ", "))
 			}
 			if len(edge.UniquenessRebuttals) > 0 {
-				fmt.Printf("    Uniqueness Rebuttals: %s\n", strings.Join(edge.UniquenessRebuttals, ", "))
+				fmt.Printf("    Uniqueness Rebuttals: %s\n", strings.Join(EvidenceClaims(edge.UniquenessRebuttals), ", "))
 			}
 			fmt.Println("  ---")
-			i++
 		}
 	}
 	fmt.Println("--------------------")
 }
 
 type jsonNode struct {
-	Argument            string   `json:"argument"`
-	IsRebuttal          bool     `json:"is_rebuttal"`
-	Importance          []string `json:"importance,omitempty"`
-	Uniqueness          []string `json:"uniqueness,omitempty"`
-	ImportanceRebuttals []string `json:"importance_rebuttals,omitempty"`
-	UniquenessRebuttals []string `json:"uniqueness_rebuttals,omitempty"`
+	Argument            string       `json:"argument"`
+	IsRebuttal          bool         `json:"is_rebuttal"`
+	Importance          []Evidence   `json:"importance,omitempty"`
+	Uniqueness          []Evidence   `json:"uniqueness,omitempty"`
+	ImportanceRebuttals []Evidence   `json:"importance_rebuttals,omitempty"`
+	UniquenessRebuttals []Evidence   `json:"uniqueness_rebuttals,omitempty"`
+	Sources             []SourceSpan `json:"sources,omitempty"`
+	Magnitude           *float64     `json:"magnitude,omitempty"`
+	IntroducedIn        string       `json:"introduced_in,omitempty"`
+	NodeMetadata
 }
 
 func (n *DebateGraphNode) ToJSON() (string, error) {
@@ -269,6 +354,10 @@ func (n *DebateGraphNode) ToJSON() (string, error) {
 		Uniqueness:          n.Uniqueness,
 		ImportanceRebuttals: n.ImportanceRebuttals,
 		UniquenessRebuttals: n.UniquenessRebuttals,
+		Sources:             n.Sources,
+		Magnitude:           n.Magnitude,
+		IntroducedIn:        n.IntroducedIn,
+		NodeMetadata:        n.NodeMetadata,
 	}
 
 	jsonData, err := json.MarshalIndent(jNode, "", "    ")
@@ -280,13 +369,15 @@ func (n *DebateGraphNode) ToJSON() (string, error) {
 }
 
 type jsonEdge struct {
-	Cause               string   `json:"cause"`
-	Effect              string   `json:"effect"`
-	IsRebuttal          bool     `json:"is_rebuttal"`
-	Certainty           []string `json:"certainty,omitempty"`
-	Uniqueness          []string `json:"uniqueness,omitempty"`
-	CertaintyRebuttal   []string `json:"certainty_rebuttal,omitempty"`
-	UniquenessRebuttals []string `json:"uniqueness_rebuttals,omitempty"`
+	Cause               string       `json:"cause"`
+	Effect              string       `json:"effect"`
+	IsRebuttal          bool         `json:"is_rebuttal"`
+	Certainty           []Evidence   `json:"certainty,omitempty"`
+	Uniqueness          []Evidence   `json:"uniqueness,omitempty"`
+	CertaintyRebuttal   []Evidence   `json:"certainty_rebuttal,omitempty"`
+	UniquenessRebuttals []Evidence   `json:"uniqueness_rebuttals,omitempty"`
+	Sources             []SourceSpan `json:"sources,omitempty"`
+	Probability         *float64     `json:"probability,omitempty"`
 }
 
 type jsonNodeRebuttal struct {
@@ -318,6 +409,8 @@ func (e *DebateGraphEdge) ToJSON() (string, error) {
 		Uniqueness:          e.Uniqueness,
 		CertaintyRebuttal:   e.CertaintyRebuttal,
 		UniquenessRebuttals: e.UniquenessRebuttals,
+		Sources:             e.Sources,
+		Probability:         e.Probability,
 	}
 
 	jsonData, err := json.MarshalIndent(jEdge, "", "    ")
@@ -334,7 +427,13 @@ type jsonCounterArgumentRebuttal struct {
 }
 
 type jsonTurnArgumentRebuttal struct {
-	RebuttalArgument string `json:"rebuttal_argument"`
+	RebuttalArgument     string   `json:"rebuttal_argument"`
+	TargetCauseArguments []string `json:"target_cause_arguments,omitempty"`
+}
+
+type jsonRelationRebuttal struct {
+	TargetRelation   RebuttalRelation `json:"target_relation"`
+	RebuttalArgument string           `json:"rebuttal_argument"`
 }
 
 type jsonGraph struct {
@@ -344,14 +443,17 @@ type jsonGraph struct {
 	EdgeRebuttals            []*jsonEdgeRebuttal            `json:"edge_rebuttals,omitempty"`
 	CounterArgumentRebuttals []*jsonCounterArgumentRebuttal `json:"counter_argument_rebuttals,omitempty"`
 	TurnArgumentRebuttals    []*jsonTurnArgumentRebuttal    `json:"turn_argument_rebuttals,omitempty"`
+	RelationRebuttals        []*jsonRelationRebuttal        `json:"relation_rebuttals,omitempty"`
 }
 
-// ToJSON はDebateGraphをJSON文字列に変換します。(修正)
-func (dg *DebateGraph) ToJSON() (string, error) {
-	if dg == nil {
-		return "", fmt.Errorf("cannot convert nil DebateGraph to JSON")
-	}
-
+// toCanonicalJSONGraph はDebateGraphを正規化された順序のjsonGraphに変換します。
+// ノード・エッジ・各種反論は内容に基づいてソートされるため、
+// 構築順序やedgeMapの走査順が異なっても同じグラフからは同じ出力が得られます。
+// アノテーションのリストは順序に意味があるため、そのままの順序を保持します。
+// 読み取りロックを取得するため、ロックを保持したまま呼び出さないでください。
+func (dg *DebateGraph) toCanonicalJSONGraph() *jsonGraph {
+	dg.mu.RLock()
+	defer dg.mu.RUnlock()
 	jGraph := &jsonGraph{
 		Nodes:                    make([]*jsonNode, 0, len(dg.Nodes)),
 		Edges:                    make([]*jsonEdge, 0, len(dg.edgeMap)),
@@ -359,6 +461,7 @@ func (dg *DebateGraph) ToJSON() (string, error) {
 		EdgeRebuttals:            make([]*jsonEdgeRebuttal, 0, len(dg.EdgeRebuttals)),
 		CounterArgumentRebuttals: make([]*jsonCounterArgumentRebuttal, 0, len(dg.CounterArgumentRebuttals)),
 		TurnArgumentRebuttals:    make([]*jsonTurnArgumentRebuttal, 0, len(dg.TurnArgumentRebuttals)),
+		RelationRebuttals:        make([]*jsonRelationRebuttal, 0, len(dg.RelationRebuttals)),
 	}
 
 	// ノードの変換
@@ -370,11 +473,18 @@ func (dg *DebateGraph) ToJSON() (string, error) {
 			Uniqueness:          node.Uniqueness,
 			ImportanceRebuttals: node.ImportanceRebuttals,
 			UniquenessRebuttals: node.UniquenessRebuttals,
+			Sources:             node.Sources,
+			Magnitude:           node.Magnitude,
+			IntroducedIn:        node.IntroducedIn,
+			NodeMetadata:        node.NodeMetadata,
 		})
 	}
+	sort.Slice(jGraph.Nodes, func(i, j int) bool {
+		return jGraph.Nodes[i].Argument < jGraph.Nodes[j].Argument
+	})
 
-	// エッジの変換
-	for _, edge := range dg.edgeMap {
+	// エッジの変換 (allEdgesは (Cause, Effect) 順にソート済み)
+	for _, edge := range dg.allEdges() {
 		jGraph.Edges = append(jGraph.Edges, &jsonEdge{
 			Cause:               edge.Cause.Argument,
 			Effect:              edge.Effect.Argument,
@@ -383,6 +493,8 @@ func (dg *DebateGraph) ToJSON() (string, error) {
 			Uniqueness:          edge.Uniqueness,
 			CertaintyRebuttal:   edge.CertaintyRebuttal,
 			UniquenessRebuttals: edge.UniquenessRebuttals,
+			Sources:             edge.Sources,
+			Probability:         edge.Probability,
 		})
 	}
 
@@ -394,6 +506,13 @@ func (dg *DebateGraph) ToJSON() (string, error) {
 			RebuttalArgument: r.RebuttalNode.Argument,
 		})
 	}
+	sort.Slice(jGraph.NodeRebuttals, func(i, j int) bool {
+		a, b := jGraph.NodeRebuttals[i], jGraph.NodeRebuttals[j]
+		return compareStrings(
+			[]string{a.TargetArgument, a.RebuttalType, a.RebuttalArgument},
+			[]string{b.TargetArgument, b.RebuttalType, b.RebuttalArgument},
+		) < 0
+	})
 
 	// エッジ反論の変換
 	for _, r := range dg.EdgeRebuttals {
@@ -404,6 +523,13 @@ func (dg *DebateGraph) ToJSON() (string, error) {
 			RebuttalArgument:     r.RebuttalNode.Argument,
 		})
 	}
+	sort.Slice(jGraph.EdgeRebuttals, func(i, j int) bool {
+		a, b := jGraph.EdgeRebuttals[i], jGraph.EdgeRebuttals[j]
+		return compareStrings(
+			[]string{a.TargetCauseArgument, a.TargetEffectArgument, a.RebuttalType, a.RebuttalArgument},
+			[]string{b.TargetCauseArgument, b.TargetEffectArgument, b.RebuttalType, b.RebuttalArgument},
+		) < 0
+	})
 
 	// 反対意見の変換
 	for _, r := range dg.CounterArgumentRebuttals {
@@ -412,15 +538,69 @@ func (dg *DebateGraph) ToJSON() (string, error) {
 			TargetArgument:   r.TargetNode.Argument,
 		})
 	}
+	sort.Slice(jGraph.CounterArgumentRebuttals, func(i, j int) bool {
+		a, b := jGraph.CounterArgumentRebuttals[i], jGraph.CounterArgumentRebuttals[j]
+		return compareStrings(
+			[]string{a.TargetArgument, a.RebuttalArgument},
+			[]string{b.TargetArgument, b.RebuttalArgument},
+		) < 0
+	})
 
 	// ターンアラウンドの変換
 	for _, r := range dg.TurnArgumentRebuttals {
 		jGraph.TurnArgumentRebuttals = append(jGraph.TurnArgumentRebuttals, &jsonTurnArgumentRebuttal{
+			RebuttalArgument:     r.RebuttalNode.Argument,
+			TargetCauseArguments: r.TargetCauseArguments(),
+		})
+	}
+	// 同じノードによるターンが複数ある場合は、認めているノードの並びで順序を決める
+	sort.SliceStable(jGraph.TurnArgumentRebuttals, func(i, j int) bool {
+		a, b := jGraph.TurnArgumentRebuttals[i], jGraph.TurnArgumentRebuttals[j]
+		return compareStrings(
+			[]string{a.RebuttalArgument, strings.Join(a.TargetCauseArguments, "\x00")},
+			[]string{b.RebuttalArgument, strings.Join(b.TargetCauseArguments, "\x00")},
+		) < 0
+	})
+
+	// 反論関係への反論の変換
+	for _, r := range dg.RelationRebuttals {
+		jGraph.RelationRebuttals = append(jGraph.RelationRebuttals, &jsonRelationRebuttal{
+			TargetRelation:   r.TargetRelation,
 			RebuttalArgument: r.RebuttalNode.Argument,
 		})
 	}
+	sort.Slice(jGraph.RelationRebuttals, func(i, j int) bool {
+		a, b := jGraph.RelationRebuttals[i], jGraph.RelationRebuttals[j]
+		return compareStrings(
+			[]string{a.TargetRelation.Key(), a.RebuttalArgument},
+			[]string{b.TargetRelation.Key(), b.RebuttalArgument},
+		) < 0
+	})
+
+	return jGraph
+}
+
+// compareStrings は2つの文字列スライスを辞書順に比較します。
+func compareStrings(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// ToJSON はDebateGraphを正規化された順序のインデント付きJSON文字列に変換します。
+// 同じ内容のグラフからは常に同じ文字列が得られるため、キャッシュや差分比較に利用できます。
+func (dg *DebateGraph) ToJSON() (string, error) {
+	if dg == nil {
+		return "", fmt.Errorf("cannot convert nil DebateGraph to JSON")
+	}
 
-	jsonData, err := json.MarshalIndent(jGraph, "", "    ")
+	jsonData, err := json.MarshalIndent(dg.toCanonicalJSONGraph(), "", "    ")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal DebateGraph to JSON: %w", err)
 	}
@@ -428,6 +608,61 @@ func (dg *DebateGraph) ToJSON() (string, error) {
 	return string(jsonData), nil
 }
 
+// ToCompactJSON はToJSONと同じ正規化された内容を、インデントなしのJSON文字列で返します。
+// プロンプトに埋め込む際のトークン数を抑えるために使用します。
+func (dg *DebateGraph) ToCompactJSON() (string, error) {
+	if dg == nil {
+		return "", fmt.Errorf("cannot convert nil DebateGraph to JSON")
+	}
+
+	jsonData, err := json.Marshal(dg.toCanonicalJSONGraph())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal DebateGraph to compact JSON: %w", err)
+	}
+
+	return string(jsonData), nil
+}
+
+// Hash は正規化されたJSON表現に対するSHA-256ハッシュを16進文字列で返します。
+// ノードやエッジの追加順序に依存せず、内容が同じグラフは同じハッシュになります。
+func (dg *DebateGraph) Hash() (string, error) {
+	compactJSON, err := dg.ToCompactJSON()
+	if err != nil {
+		return "", fmt.Errorf("failed to hash DebateGraph: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(compactJSON))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Clone はDebateGraphの複製を返します。
+// 正規化されたJSON表現を経由するため、複製したグラフを変更しても元のグラフには影響しません。
+func (dg *DebateGraph) Clone() (*DebateGraph, error) {
+	jsonData, err := dg.ToCompactJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone DebateGraph: %w", err)
+	}
+	return NewDebateGraphFromJSON(jsonData)
+}
+
+// readLocked は graphs の読み取りロックを取得したまま read を呼び出し、その結果を返します。
+// 同じグラフが複数渡された場合は1回だけロックし、複数のグラフは常に作成された順にロックするため、
+// 複数のグラフを読み取る呼び出し同士がデッドロックすることはありません。
+func readLocked[T any](read func() (T, error), graphs ...*DebateGraph) (T, error) {
+	locked := make([]*DebateGraph, 0, len(graphs))
+	for _, graph := range graphs {
+		if !slices.Contains(locked, graph) {
+			locked = append(locked, graph)
+		}
+	}
+	sort.Slice(locked, func(i, j int) bool { return locked[i].id < locked[j].id })
+	for _, graph := range locked {
+		graph.mu.RLock()
+		defer graph.mu.RUnlock()
+	}
+	return read()
+}
+
 // NewDebateGraphFromJSON はJSON文字列からDebateGraphを復元します。(新規追加)
 func NewDebateGraphFromJSON(jsonData string) (*DebateGraph, error) {
 	var jGraph jsonGraph
@@ -435,49 +670,38 @@ func NewDebateGraphFromJSON(jsonData string) (*DebateGraph, error) {
 		return nil, fmt.Errorf("failed to unmarshal JSON to DebateGraph: %w", err)
 	}
 
+	// 作成中のグラフは他のゴルーチンから参照されないため、ロックを取得しない内部の関数で構築する
 	dg := NewDebateGraph()
 
 	// 1. ノードをすべて構築
 	for _, jNode := range jGraph.Nodes {
-		node := NewDebateGraphNode(jNode.Argument, jNode.IsRebuttal)
-		node.Importance = jNode.Importance
-		node.Uniqueness = jNode.Uniqueness
-		node.ImportanceRebuttals = jNode.ImportanceRebuttals
-		node.UniquenessRebuttals = jNode.UniquenessRebuttals
-		if err := dg.AddNode(node); err != nil {
+		node, err := newNodeFromJSONNode(jNode)
+		if err != nil {
+			return nil, err
+		}
+		if err := dg.addNode(node); err != nil {
 			return nil, fmt.Errorf("failed to add node '%s' from JSON: %w", jNode.Argument, err)
 		}
 	}
 
 	// 2. エッジをすべて構築
 	for _, jEdge := range jGraph.Edges {
-		causeNode, causeExists := dg.GetNode(jEdge.Cause)
-		if !causeExists {
-			return nil, fmt.Errorf("cause node '%s' for edge not found in graph", jEdge.Cause)
-		}
-		effectNode, effectExists := dg.GetNode(jEdge.Effect)
-		if !effectExists {
-			return nil, fmt.Errorf("effect node '%s' for edge not found in graph", jEdge.Effect)
+		edge, err := newEdgeFromJSONEdge(dg, jEdge)
+		if err != nil {
+			return nil, err
 		}
-
-		edge := NewDebateGraphEdge(causeNode, effectNode, jEdge.IsRebuttal)
-		edge.Certainty = jEdge.Certainty
-		edge.Uniqueness = jEdge.Uniqueness
-		edge.CertaintyRebuttal = jEdge.CertaintyRebuttal
-		edge.UniquenessRebuttals = jEdge.UniquenessRebuttals
-
-		if err := dg.AddEdge(edge); err != nil {
+		if err := dg.addEdge(edge); err != nil {
 			return nil, fmt.Errorf("failed to add edge '%s -> %s' from JSON: %w", jEdge.Cause, jEdge.Effect, err)
 		}
 	}
 
 	// 3. ノード反論を再構築
 	for _, jRebuttal := range jGraph.NodeRebuttals {
-		targetNode, exists := dg.GetNode(jRebuttal.TargetArgument)
+		targetNode, exists := dg.getNode(jRebuttal.TargetArgument)
 		if !exists {
 			return nil, fmt.Errorf("target node '%s' for node rebuttal not found", jRebuttal.TargetArgument)
 		}
-		rebuttalNode, exists := dg.GetNode(jRebuttal.RebuttalArgument)
+		rebuttalNode, exists := dg.getNode(jRebuttal.RebuttalArgument)
 		if !exists {
 			return nil, fmt.Errorf("rebuttal node '%s' for node rebuttal not found", jRebuttal.RebuttalArgument)
 		}
@@ -492,11 +716,11 @@ func NewDebateGraphFromJSON(jsonData string) (*DebateGraph, error) {
 
 	// 4. エッジ反論を再構築
 	for _, jRebuttal := range jGraph.EdgeRebuttals {
-		targetEdge, exists := dg.GetEdge(jRebuttal.TargetCauseArgument, jRebuttal.TargetEffectArgument)
+		targetEdge, exists := dg.getEdge(jRebuttal.TargetCauseArgument, jRebuttal.TargetEffectArgument)
 		if !exists {
 			return nil, fmt.Errorf("target edge '%s -> %s' for edge rebuttal not found", jRebuttal.TargetCauseArgument, jRebuttal.TargetEffectArgument)
 		}
-		rebuttalNode, exists := dg.GetNode(jRebuttal.RebuttalArgument)
+		rebuttalNode, exists := dg.getNode(jRebuttal.RebuttalArgument)
 		if !exists {
 			return nil, fmt.Errorf("rebuttal node '%s' for edge rebuttal not found", jRebuttal.RebuttalArgument)
 		}
@@ -511,12 +735,12 @@ func NewDebateGraphFromJSON(jsonData string) (*DebateGraph, error) {
 
 	// 5. 反対意見を再構築
 	for _, jRebuttal := range jGraph.CounterArgumentRebuttals {
-		rebuttalNode, exists := dg.GetNode(jRebuttal.RebuttalArgument)
+		rebuttalNode, exists := dg.getNode(jRebuttal.RebuttalArgument)
 		if !exists {
 			return nil, fmt.Errorf("rebuttal node '%s' for counter argument rebuttal not found", jRebuttal.RebuttalArgument)
 		}
 
-		targetNode, exiexists := dg.GetNode(jRebuttal.TargetArgument)
+		targetNode, exiexists := dg.getNode(jRebuttal.TargetArgument)
 		if !exiexists {
 			return nil, fmt.Errorf("target node '%s' for counter argument rebuttal not found", jRebuttal.TargetArgument)
 		}
@@ -529,17 +753,81 @@ func NewDebateGraphFromJSON(jsonData string) (*DebateGraph, error) {
 	}
 
 	// 6. ターンアラウンドを再構築
+	// 認めているノードからのエッジが含まれていない場合は補う
 	for _, jRebuttal := range jGraph.TurnArgumentRebuttals {
-		rebuttalNode, exists := dg.GetNode(jRebuttal.RebuttalArgument)
-		if !exists {
+		if _, exists := dg.getNode(jRebuttal.RebuttalArgument); !exists {
 			return nil, fmt.Errorf("rebuttal node '%s' for turn argument rebuttal not found", jRebuttal.RebuttalArgument)
 		}
+		if _, err := dg.addTurnArgumentRebuttal(jRebuttal.RebuttalArgument, jRebuttal.TargetCauseArguments); err != nil {
+			return nil, fmt.Errorf("invalid turn argument rebuttal '%s': %w", jRebuttal.RebuttalArgument, err)
+		}
+	}
 
-		rebuttal := &TurnArgumentRebuttal{
-			RebuttalNode: rebuttalNode,
+	// 7. 反論関係への反論を再構築
+	// 対象が別の反論関係への反論である場合があるため、対象が揃ったものから順に追加する
+	pending := jGraph.RelationRebuttals
+	for len(pending) > 0 {
+		remaining := make([]*jsonRelationRebuttal, 0)
+		var lastErr error
+		for _, jRebuttal := range pending {
+			rebuttal, err := newRelationRebuttal(dg, jRebuttal.TargetRelation, jRebuttal.RebuttalArgument)
+			if err != nil {
+				remaining = append(remaining, jRebuttal)
+				lastErr = err
+				continue
+			}
+			dg.RelationRebuttals = append(dg.RelationRebuttals, rebuttal)
+		}
+		if len(remaining) == len(pending) {
+			return nil, fmt.Errorf("invalid relation rebuttal: %w", lastErr)
 		}
-		dg.TurnArgumentRebuttals = append(dg.TurnArgumentRebuttals, rebuttal)
+		pending = remaining
 	}
 
 	return dg, nil
 }
+
+// newNodeFromJSONNode はJSONのノードからDebateGraphNodeを作成します。メタデータと大きさを検証します。
+func newNodeFromJSONNode(jNode *jsonNode) (*DebateGraphNode, error) {
+	node := NewDebateGraphNode(jNode.Argument, jNode.IsRebuttal)
+	node.Importance = jNode.Importance
+	node.Uniqueness = jNode.Uniqueness
+	node.ImportanceRebuttals = jNode.ImportanceRebuttals
+	node.UniquenessRebuttals = jNode.UniquenessRebuttals
+	node.Sources = jNode.Sources
+	node.IntroducedIn = jNode.IntroducedIn
+	if err := jNode.NodeMetadata.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid metadata for node '%s': %w", jNode.Argument, err)
+	}
+	node.NodeMetadata = jNode.NodeMetadata
+	if jNode.Magnitude != nil && *jNode.Magnitude < 0 {
+		return nil, fmt.Errorf("magnitude of node '%s' must not be negative, got %v", jNode.Argument, *jNode.Magnitude)
+	}
+	node.Magnitude = jNode.Magnitude
+	return node, nil
+}
+
+// newEdgeFromJSONEdge はJSONのエッジから、dgのノードを結ぶDebateGraphEdgeを作成します。グラフには追加しません。
+// ロックは取得しません。
+func newEdgeFromJSONEdge(dg *DebateGraph, jEdge *jsonEdge) (*DebateGraphEdge, error) {
+	causeNode, causeExists := dg.getNode(jEdge.Cause)
+	if !causeExists {
+		return nil, fmt.Errorf("cause node '%s' for edge not found in graph", jEdge.Cause)
+	}
+	effectNode, effectExists := dg.getNode(jEdge.Effect)
+	if !effectExists {
+		return nil, fmt.Errorf("effect node '%s' for edge not found in graph", jEdge.Effect)
+	}
+
+	edge := NewDebateGraphEdge(causeNode, effectNode, jEdge.IsRebuttal)
+	edge.Certainty = jEdge.Certainty
+	edge.Uniqueness = jEdge.Uniqueness
+	edge.CertaintyRebuttal = jEdge.CertaintyRebuttal
+	edge.UniquenessRebuttals = jEdge.UniquenessRebuttals
+	edge.Sources = jEdge.Sources
+	if jEdge.Probability != nil && (*jEdge.Probability < 0 || *jEdge.Probability > 1) {
+		return nil, fmt.Errorf("probability of edge '%s -> %s' must be between 0 and 1, got %v", jEdge.Cause, jEdge.Effect, *jEdge.Probability)
+	}
+	edge.Probability = jEdge.Probability
+	return edge, nil
+}
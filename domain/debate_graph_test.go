@@ -0,0 +1,382 @@
+package domain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildSampleGraph は追加順序を指定してテスト用のDebateGraphを構築します。
+func buildSampleGraph(t *testing.T, reverse bool) *DebateGraph {
+	t.Helper()
+
+	arguments := []string{"再生可能エネルギーの導入が増加する", "CO2排出量が削減される", "地球温暖化の進行が緩和される", "発電コストが上昇する"}
+	edges := [][2]string{
+		{"再生可能エネルギーの導入が増加する", "CO2排出量が削減される"},
+		{"CO2排出量が削減される", "地球温暖化の進行が緩和される"},
+		{"再生可能エネルギーの導入が増加する", "発電コストが上昇する"},
+	}
+	if reverse {
+		for i, j := 0, len(arguments)-1; i < j; i, j = i+1, j-1 {
+			arguments[i], arguments[j] = arguments[j], arguments[i]
+		}
+		for i, j := 0, len(edges)-1; i < j; i, j = i+1, j-1 {
+			edges[i], edges[j] = edges[j], edges[i]
+		}
+	}
+
+	dg := NewDebateGraph()
+	for _, argument := range arguments {
+		require.NoError(t, dg.AddNode(NewDebateGraphNode(argument, argument == "発電コストが上昇する")))
+	}
+	for _, e := range edges {
+		cause, _ := dg.GetNode(e[0])
+		effect, _ := dg.GetNode(e[1])
+		require.NoError(t, dg.AddEdge(NewDebateGraphEdge(cause, effect, false)))
+	}
+
+	edge, _ := dg.GetEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される")
+	edge.Certainty = append(edge.Certainty, NewEvidence("IPCCの報告書による"))
+	rebuttal, err := NewDebateGraphEdgeRebuttal(dg, "再生可能エネルギーの導入が増加する", "CO2排出量が削減される", "certainty", "発電コストが上昇する")
+	require.NoError(t, err)
+	dg.EdgeRebuttals = append(dg.EdgeRebuttals, rebuttal)
+
+	return dg
+}
+
+func TestDebateGraphCanonicalJSON(t *testing.T) {
+	forward := buildSampleGraph(t, false)
+	backward := buildSampleGraph(t, true)
+
+	forwardJSON, err := forward.ToJSON()
+	require.NoError(t, err)
+	backwardJSON, err := backward.ToJSON()
+	require.NoError(t, err)
+	assert.Equal(t, forwardJSON, backwardJSON, "追加順序が異なっても同じJSONになるべきです。")
+
+	forwardHash, err := forward.Hash()
+	require.NoError(t, err)
+	backwardHash, err := backward.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, forwardHash, backwardHash)
+
+	// JSONから復元したグラフも同じハッシュを持つ
+	restored, err := NewDebateGraphFromJSON(forwardJSON)
+	require.NoError(t, err)
+	restoredHash, err := restored.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, forwardHash, restoredHash)
+
+	// 内容が変われば別のハッシュになる
+	node, _ := restored.GetNode("地球温暖化の進行が緩和される")
+	node.Importance = append(node.Importance, NewEvidence("気候変動は深刻な被害をもたらす"))
+	changedHash, err := restored.Hash()
+	require.NoError(t, err)
+	assert.NotEqual(t, forwardHash, changedHash)
+}
+
+func TestDebateGraphCanonicalJSONWithTurnsOfSameNode(t *testing.T) {
+	build := func(concededOrder []string) string {
+		dg := buildSampleGraph(t, false)
+		require.NoError(t, dg.AddNode(NewDebateGraphNode("電力会社の経営が悪化する", true)))
+		for _, conceded := range concededOrder {
+			_, err := dg.AddTurnArgumentRebuttal("電力会社の経営が悪化する", []string{conceded})
+			require.NoError(t, err)
+		}
+		output, err := dg.ToJSON()
+		require.NoError(t, err)
+		return output
+	}
+
+	// 同じノードによるターンが複数あっても、追加順序に関わらず同じJSONになる
+	forward := build([]string{"再生可能エネルギーの導入が増加する", "発電コストが上昇する", "CO2排出量が削減される"})
+	backward := build([]string{"CO2排出量が削減される", "発電コストが上昇する", "再生可能エネルギーの導入が増加する"})
+	assert.Equal(t, forward, backward)
+}
+
+func TestDebateGraphCompactJSON(t *testing.T) {
+	dg := buildSampleGraph(t, false)
+
+	compactJSON, err := dg.ToCompactJSON()
+	require.NoError(t, err)
+	assert.NotContains(t, compactJSON, "\n")
+
+	indentedJSON, err := dg.ToJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, indentedJSON, compactJSON)
+}
+
+func TestDiffDebateGraphs(t *testing.T) {
+	before := buildSampleGraph(t, false)
+	after := buildSampleGraph(t, true)
+
+	diff, err := DiffDebateGraphs(before, after)
+	require.NoError(t, err)
+	assert.True(t, diff.IsEmpty(), "同じ内容のグラフには差分がないはずです。")
+
+	newNode := NewDebateGraphNode("電気料金が上がる", true)
+	require.NoError(t, after.AddNode(newNode))
+	cost, _ := after.GetNode("発電コストが上昇する")
+	require.NoError(t, after.AddEdge(NewDebateGraphEdge(cost, newNode, true)))
+	require.NoError(t, after.RemoveEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される"))
+	edge, _ := after.GetEdge("再生可能エネルギーの導入が増加する", "CO2排出量が削減される")
+	edge.Certainty = append(edge.Certainty, NewEvidence("火力発電の置き換えが進む"))
+	after.EdgeRebuttals = nil
+
+	diff, err = DiffDebateGraphs(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"電気料金が上がる"}, diff.AddedNodes)
+	assert.Equal(t, []EdgeRef{{Cause: "発電コストが上昇する", Effect: "電気料金が上がる"}}, diff.AddedEdges)
+	assert.Equal(t, []EdgeRef{{Cause: "CO2排出量が削減される", Effect: "地球温暖化の進行が緩和される"}}, diff.RemovedEdges)
+	require.Len(t, diff.ModifiedEdges, 1)
+	assert.Equal(t, []AnnotationDiff{{Field: "certainty", Added: []Evidence{NewEvidence("火力発電の置き換えが進む")}}}, diff.ModifiedEdges[0].Annotations)
+	require.Len(t, diff.RemovedRebuttals, 1)
+	assert.Equal(t, RebuttalKindEdge, diff.RemovedRebuttals[0].Kind)
+}
+
+func TestDiffDebateGraphsProvenanceAndDuplicateRelations(t *testing.T) {
+	before := buildSampleGraph(t, true)
+	after := buildSampleGraph(t, true)
+
+	span := SourceSpan{DocumentID: "doc-1", Start: 0, End: 4, Text: "発電コスト"}
+	node, _ := after.GetNode("発電コストが上昇する")
+	node.Sources = append(node.Sources, span)
+	node.IntroducedIn = "speech-2"
+	edge, _ := after.GetEdge("再生可能エネルギーの導入が増加する", "CO2排出量が削減される")
+	edge.Sources = append(edge.Sources, span)
+	// 同じKeyの反論関係を重複させる
+	after.EdgeRebuttals = append(after.EdgeRebuttals, after.EdgeRebuttals[0])
+
+	diff, err := DiffDebateGraphs(before, after)
+	require.NoError(t, err)
+	require.Len(t, diff.ModifiedNodes, 1)
+	assert.Equal(t, &StringChange{Before: "", After: "speech-2"}, diff.ModifiedNodes[0].IntroducedIn)
+	assert.Equal(t, &SourcesDiff{Added: []SourceSpan{span}}, diff.ModifiedNodes[0].Sources)
+	require.Len(t, diff.ModifiedEdges, 1)
+	assert.Equal(t, &SourcesDiff{Added: []SourceSpan{span}}, diff.ModifiedEdges[0].Sources)
+	require.Len(t, diff.AddedRebuttals, 1)
+	assert.Equal(t, after.EdgeRebuttals[0].Relation(), diff.AddedRebuttals[0])
+	assert.Empty(t, diff.RemovedRebuttals)
+}
+
+func TestMergeDebateGraphs(t *testing.T) {
+	base := buildSampleGraph(t, false)
+	ours := buildSampleGraph(t, false)
+	theirs := buildSampleGraph(t, false)
+
+	// ours: エッジを削除し、ノードにアノテーションを追加
+	require.NoError(t, ours.RemoveEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される"))
+	oursNode, _ := ours.GetNode("CO2排出量が削減される")
+	oursNode.Importance = append(oursNode.Importance, NewEvidence("排出量は気温上昇と比例する"))
+
+	// theirs: oursで削除したエッジにアノテーションを追加し、新しいノードとエッジを追加
+	theirsEdge, _ := theirs.GetEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される")
+	theirsEdge.Certainty = append(theirsEdge.Certainty, NewEvidence("気候モデルによる予測"))
+	newNode := NewDebateGraphNode("異常気象が減る", false)
+	require.NoError(t, theirs.AddNode(newNode))
+	warming, _ := theirs.GetNode("地球温暖化の進行が緩和される")
+	require.NoError(t, theirs.AddEdge(NewDebateGraphEdge(warming, newNode, false)))
+	theirs.EdgeRebuttals = nil
+
+	result, err := MergeDebateGraphs(base, ours, theirs)
+	require.NoError(t, err)
+
+	merged := result.Graph
+	_, exists := merged.GetNode("異常気象が減る")
+	assert.True(t, exists)
+	_, exists = merged.GetEdge("地球温暖化の進行が緩和される", "異常気象が減る")
+	assert.True(t, exists)
+
+	// 削除と変更が衝突したエッジは残され、競合として報告される
+	edge, exists := merged.GetEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される")
+	require.True(t, exists)
+	assert.Equal(t, []string{"IPCCの報告書による", "気候モデルによる予測"}, EvidenceClaims(edge.Certainty))
+	require.Len(t, result.Conflicts, 1)
+	assert.Equal(t, MergeConflictDeleteModify, result.Conflicts[0].Type)
+	assert.Equal(t, "ours", result.Conflicts[0].DeletedIn)
+
+	node, _ := merged.GetNode("CO2排出量が削減される")
+	assert.Equal(t, []string{"排出量は気温上昇と比例する"}, EvidenceClaims(node.Importance))
+	assert.Empty(t, merged.EdgeRebuttals, "theirsで削除された反論関係は削除されるべきです。")
+}
+
+func TestMergeDebateGraphsReportsModifyConflicts(t *testing.T) {
+	base := buildSampleGraph(t, false)
+	ours := buildSampleGraph(t, false)
+	theirs := buildSampleGraph(t, false)
+
+	oursMagnitude, theirsMagnitude := 2.0, 3.0
+	oursNode, _ := ours.GetNode("地球温暖化の進行が緩和される")
+	oursNode.Magnitude = &oursMagnitude
+	oursNode.NodeMetadata = NodeMetadata{Side: SideAffirmativePlan, Stakeholder: "将来世代"}
+	theirsNode, _ := theirs.GetNode("地球温暖化の進行が緩和される")
+	theirsNode.Magnitude = &theirsMagnitude
+	theirsNode.NodeMetadata = NodeMetadata{Side: SideAffirmativePlan, Stakeholder: "沿岸部の住民"}
+
+	oursProbability, theirsProbability := 0.6, 0.9
+	oursEdge, _ := ours.GetEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される")
+	oursEdge.Probability = &oursProbability
+	theirsEdge, _ := theirs.GetEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される")
+	theirsEdge.Probability = &theirsProbability
+
+	result, err := MergeDebateGraphs(base, ours, theirs)
+	require.NoError(t, err)
+
+	// 両方で同じ値に変更された side は競合にならない
+	assert.ElementsMatch(t, []MergeConflict{
+		{Type: MergeConflictModifyModify, ElementKind: "node", Argument: "地球温暖化の進行が緩和される", Field: "magnitude", Ours: "2", Theirs: "3", Resolution: "kept the value in ours"},
+		{Type: MergeConflictModifyModify, ElementKind: "node", Argument: "地球温暖化の進行が緩和される", Field: "metadata.stakeholder", Ours: "将来世代", Theirs: "沿岸部の住民", Resolution: "kept the value in ours"},
+		{Type: MergeConflictModifyModify, ElementKind: "edge", Cause: "CO2排出量が削減される", Effect: "地球温暖化の進行が緩和される", Field: "probability", Ours: "0.6", Theirs: "0.9", Resolution: "kept the value in ours"},
+	}, result.Conflicts)
+
+	node, _ := result.Graph.GetNode("地球温暖化の進行が緩和される")
+	assert.Equal(t, oursMagnitude, *node.Magnitude)
+	assert.Equal(t, "将来世代", node.Stakeholder)
+}
+
+func TestEvidenceJSONBackwardCompatibility(t *testing.T) {
+	// 以前の形式（文字列のアノテーション）と新しい形式（Evidenceオブジェクト）が混在していても読み込める
+	input := `{
+		"nodes": [
+			{ "argument": "CO2排出量が削減される", "is_rebuttal": false, "importance": ["温暖化の主因である"] },
+			{ "argument": "地球温暖化の進行が緩和される", "is_rebuttal": false }
+		],
+		"edges": [
+			{
+				"cause": "CO2排出量が削減される",
+				"effect": "地球温暖化の進行が緩和される",
+				"is_rebuttal": false,
+				"certainty": [
+					"IPCCの報告書による",
+					{ "claim": "気候モデルによる予測", "citation": "AR6 WG1", "url": "https://www.ipcc.ch/report/ar6/wg1/", "confidence": 0.8 }
+				]
+			}
+		]
+	}`
+
+	dg, err := NewDebateGraphFromJSON(input)
+	require.NoError(t, err)
+
+	node, _ := dg.GetNode("CO2排出量が削減される")
+	assert.Equal(t, []Evidence{NewEvidence("温暖化の主因である")}, node.Importance)
+
+	edge, _ := dg.GetEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される")
+	require.Len(t, edge.Certainty, 2)
+	assert.Equal(t, "IPCCの報告書による", edge.Certainty[0].Claim)
+	assert.Equal(t, "AR6 WG1", edge.Certainty[1].Citation)
+	require.NotNil(t, edge.Certainty[1].Confidence)
+	assert.InDelta(t, 0.8, *edge.Certainty[1].Confidence, 1e-9)
+
+	// 書き出しはオブジェクト形式に統一される
+	output, err := dg.ToJSON()
+	require.NoError(t, err)
+	assert.Contains(t, output, `"claim": "IPCCの報告書による"`)
+	restored, err := NewDebateGraphFromJSON(output)
+	require.NoError(t, err)
+	restoredEdge, _ := restored.GetEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される")
+	assert.Equal(t, edge.Certainty[1].Key(), restoredEdge.Certainty[1].Key())
+}
+
+func TestEvidenceConfidenceRange(t *testing.T) {
+	evidence, err := NewEvidenceWithConfidence("気候モデルによる予測", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, *evidence.Confidence)
+
+	for _, confidence := range []float64{math.NaN(), -0.1, 1.5} {
+		_, err := NewEvidenceWithConfidence("気候モデルによる予測", confidence)
+		assert.Error(t, err, confidence)
+	}
+
+	for _, confidence := range []string{"-0.1", "1.5"} {
+		input := `{
+			"nodes": [{ "argument": "CO2排出量が削減される", "is_rebuttal": false, "importance": [{ "claim": "温暖化の主因である", "confidence": ` + confidence + ` }] }],
+			"edges": []
+		}`
+		_, err := NewDebateGraphFromJSON(input)
+		assert.Error(t, err, confidence)
+	}
+
+	dg := buildSampleGraph(t, false)
+	invalid := -1.0
+	err = dg.AddNodeAnnotation("CO2排出量が削減される", NodeAnnotationImportance, Evidence{Claim: "温暖化の主因である", Confidence: &invalid})
+	assert.Error(t, err)
+}
+
+func TestRelationRebuttal(t *testing.T) {
+	dg := buildSampleGraph(t, false)
+	certaintyRebuttal := RebuttalRelation{
+		Kind: RebuttalKindEdge, TargetCauseArgument: "再生可能エネルギーの導入が増加する", TargetEffectArgument: "CO2排出量が削減される",
+		RebuttalType: "certainty", RebuttalArgument: "発電コストが上昇する",
+	}
+
+	// 反論関係への反論と、さらにそれへの反論
+	require.NoError(t, dg.AddNode(NewDebateGraphNode("発電コストは排出量と無関係である", true)))
+	require.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{
+		Kind: RebuttalKindRelation, TargetRelation: &certaintyRebuttal, RebuttalArgument: "発電コストは排出量と無関係である",
+	}))
+	require.NoError(t, dg.AddNode(NewDebateGraphNode("コスト上昇で導入が遅れれば排出量も減らない", true)))
+	require.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{
+		Kind: RebuttalKindRelation, TargetRelation: &RebuttalRelation{
+			Kind: RebuttalKindRelation, TargetRelation: &certaintyRebuttal, RebuttalArgument: "発電コストは排出量と無関係である",
+		},
+		RebuttalArgument: "コスト上昇で導入が遅れれば排出量も減らない",
+	}))
+	assert.Error(t, dg.AddRebuttalRelation(RebuttalRelation{
+		Kind: RebuttalKindRelation, TargetRelation: &RebuttalRelation{Kind: RebuttalKindTurnArgument, RebuttalArgument: "CO2排出量が削減される"},
+		RebuttalArgument: "発電コストは排出量と無関係である",
+	}), "存在しない反論関係は対象にできません。")
+
+	jsonData, err := dg.ToJSON()
+	require.NoError(t, err)
+	restored, err := NewDebateGraphFromJSON(jsonData)
+	require.NoError(t, err)
+	require.Len(t, restored.RelationRebuttals, 2)
+	restoredJSON, err := restored.ToJSON()
+	require.NoError(t, err)
+	assert.Equal(t, jsonData, restoredJSON)
+
+	// 反論関係への反論は、対象の反論による割引を弱める
+	result, err := Adjudicate(restored, DefaultAdjudicationConfig())
+	require.NoError(t, err)
+	discounted := false
+	for _, step := range result.Trace {
+		if step.Operation == "discount" && step.Rebuttal == "発電コストが上昇する" {
+			discounted = true
+			// 効き目 = 1 - 0.5 * (1 - 0.5) = 0.75、係数 = 1 - 0.5 * 0.75
+			assert.InDelta(t, 0.625, step.Factor, 1e-9)
+		}
+	}
+	assert.True(t, discounted)
+}
+
+func TestTurnArgumentTargets(t *testing.T) {
+	dg := buildSampleGraph(t, false)
+	require.NoError(t, dg.AddNode(NewDebateGraphNode("電力価格の上昇で省エネ技術が普及する", true)))
+	require.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{
+		Kind: RebuttalKindTurnArgument, RebuttalArgument: "電力価格の上昇で省エネ技術が普及する",
+		TargetCauseArguments: []string{"発電コストが上昇する"},
+	}))
+
+	// 認めているノードからターンへのエッジが作成される
+	edge, exists := dg.GetEdge("発電コストが上昇する", "電力価格の上昇で省エネ技術が普及する")
+	require.True(t, exists)
+	assert.True(t, edge.IsRebuttal)
+
+	jsonData, err := dg.ToJSON()
+	require.NoError(t, err)
+	assert.Contains(t, jsonData, `"target_cause_arguments"`)
+	restored, err := NewDebateGraphFromJSON(jsonData)
+	require.NoError(t, err)
+	require.Len(t, restored.TurnArgumentRebuttals, 1)
+	assert.Equal(t, []string{"発電コストが上昇する"}, restored.TurnArgumentRebuttals[0].TargetCauseArguments())
+	restoredJSON, err := restored.ToJSON()
+	require.NoError(t, err)
+	assert.Equal(t, jsonData, restoredJSON)
+
+	assert.Error(t, dg.AddRebuttalRelation(RebuttalRelation{
+		Kind: RebuttalKindTurnArgument, RebuttalArgument: "電力価格の上昇で省エネ技術が普及する",
+		TargetCauseArguments: []string{"存在しないノード"},
+	}))
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebateHistory(t *testing.T) {
+	debate, err := NewDebate("日本は再生可能エネルギーの導入を拡大すべきである", []Team{
+		{Name: "A", Side: AdjudicationSideProponent},
+		{Name: "B", Side: AdjudicationSideOpponent},
+	})
+	require.NoError(t, err)
+
+	constructive := buildSampleGraph(t, false)
+	require.NoError(t, debate.AddSpeech(Speech{ID: "1AC", Speaker: "佐藤", Side: AdjudicationSideProponent}, constructive))
+
+	next, err := debate.NextRevision()
+	require.NoError(t, err)
+	require.NoError(t, next.AddNode(NewDebateGraphNode("電力の安定供給が損なわれる", true)))
+	require.NoError(t, debate.AddSpeech(Speech{ID: "1NC", Speaker: "鈴木", Side: AdjudicationSideOpponent}, next))
+
+	// 過去の版は変更されない
+	_, exists := debate.Revisions[0].GetNode("電力の安定供給が損なわれる")
+	assert.False(t, exists)
+
+	node, _ := debate.LatestGraph().GetNode("電力の安定供給が損なわれる")
+	assert.Equal(t, "1NC", node.IntroducedIn)
+	node, _ = debate.LatestGraph().GetNode("CO2排出量が削減される")
+	assert.Equal(t, "1AC", node.IntroducedIn)
+
+	assert.Error(t, debate.AddSpeech(Speech{ID: "1NC", Side: AdjudicationSideOpponent}, NewDebateGraph()))
+	assert.Error(t, debate.AddSpeech(Speech{ID: "2AC", Side: AdjudicationSideProponent}, debate.LatestGraph()))
+
+	jsonData, err := debate.ToJSON()
+	require.NoError(t, err)
+	restored, err := NewDebateFromJSON(jsonData)
+	require.NoError(t, err)
+	restoredJSON, err := restored.ToJSON()
+	require.NoError(t, err)
+	assert.Equal(t, jsonData, restoredJSON)
+
+	steps, err := restored.Replay()
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+	assert.Len(t, steps[0].Diff.AddedNodes, 4)
+	assert.Equal(t, []string{"電力の安定供給が損なわれる"}, steps[1].Diff.AddedNodes)
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Evidence はノードやエッジのアノテーション（重要性・独自性・確実性とその反論）1件分の根拠です。
+// 以前のJSON形式ではアノテーションは単なる文字列だったため、文字列からの読み込みにも対応します。
+type Evidence struct {
+	Claim            string      `json:"claim"`                        // 根拠となる主張の本文
+	SourceDocumentID string      `json:"source_document_id,omitempty"` // 根拠を抽出した文書のID
+	QuotedSpan       string      `json:"quoted_span,omitempty"`        // 文書中の引用箇所
+	Citation         string      `json:"citation,omitempty"`           // 出典の書誌情報
+	URL              string      `json:"url,omitempty"`
+	Author           string      `json:"author,omitempty"`
+	Date             string      `json:"date,omitempty"`
+	Confidence       *float64    `json:"confidence,omitempty"` // 0〜1の確信度。不明な場合はnil
+	Source           *SourceSpan `json:"source,omitempty"`     // QuotedSpan の文書中の位置。検証できなかった場合はnil
+}
+
+// NewEvidence は主張の本文だけを持つEvidenceを作成します。
+func NewEvidence(claim string) Evidence {
+	return Evidence{Claim: claim}
+}
+
+// NewEvidenceWithConfidence は確信度付きのEvidenceを作成します。確信度が0〜1の範囲外の場合はエラーを返します。
+func NewEvidenceWithConfidence(claim string, confidence float64) (Evidence, error) {
+	evidence := Evidence{Claim: claim, Confidence: &confidence}
+	if err := evidence.Validate(); err != nil {
+		return Evidence{}, err
+	}
+	return evidence, nil
+}
+
+// NewEvidenceFromExcerpt はAIモデルが文書の段落から抜き出した引用箇所 excerpt を根拠とするEvidenceを作成します。
+// 引用箇所の文書中の位置は LocateSpan で確認し、Source と SourceDocumentID に設定します。
+// 位置を確認できなかった場合は、Source を持たないEvidenceとともにエラーを返します。
+func NewEvidenceFromExcerpt(document string, paragraphIndex int, paragraph, excerpt, claim string) (Evidence, error) {
+	evidence := Evidence{Claim: claim, QuotedSpan: excerpt}
+	span, err := LocateSpan(document, paragraphIndex, paragraph, excerpt)
+	if err != nil {
+		return evidence, fmt.Errorf("failed to locate excerpt in paragraph %d: %w", paragraphIndex, err)
+	}
+	evidence.Source = span
+	evidence.SourceDocumentID = span.DocumentID
+	return evidence, nil
+}
+
+// Validate はEvidenceの値が有効であることを確認します。確信度は設定されている場合のみ0〜1の範囲であることを確認します。
+func (e Evidence) Validate() error {
+	if e.Confidence != nil && (math.IsNaN(*e.Confidence) || *e.Confidence < 0 || *e.Confidence > 1) {
+		return fmt.Errorf("confidence of evidence '%s' must be between 0 and 1, got %v", e.Claim, *e.Confidence)
+	}
+	return nil
+}
+
+// UnmarshalJSON はオブジェクト形式に加えて、以前の形式である文字列からもEvidenceを読み込みます。
+func (e *Evidence) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '"' {
+		var claim string
+		if err := json.Unmarshal(trimmed, &claim); err != nil {
+			return fmt.Errorf("failed to unmarshal evidence string: %w", err)
+		}
+		*e = NewEvidence(claim)
+		return nil
+	}
+
+	// Evidence 自身のUnmarshalJSONを再帰的に呼ばないよう、メソッドを持たない型を経由する
+	type evidenceObject Evidence
+	var obj evidenceObject
+	if err := json.Unmarshal(trimmed, &obj); err != nil {
+		return fmt.Errorf("failed to unmarshal evidence object: %w", err)
+	}
+	if err := Evidence(obj).Validate(); err != nil {
+		return err
+	}
+	*e = Evidence(obj)
+	return nil
+}
+
+// Key はEvidenceの全フィールドから同一性を判定するための文字列を返します。
+func (e Evidence) Key() string {
+	type evidenceObject Evidence
+	data, err := json.Marshal(evidenceObject(e))
+	if err != nil {
+		// 文字列と数値しか含まないため、通常は到達しない
+		return e.Claim
+	}
+	return string(data)
+}
+
+// EvidenceClaims はEvidenceのリストから主張の本文だけを取り出します。
+func EvidenceClaims(evidences []Evidence) []string {
+	claims := make([]string, 0, len(evidences))
+	for _, e := range evidences {
+		claims = append(claims, e.Claim)
+	}
+	return claims
+}
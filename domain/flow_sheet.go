@@ -0,0 +1,324 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// フローシート上の各スピーチにおける議論の状態
+const (
+	FlowStatusIntroduced = "introduced" // このスピーチで初めて述べられた
+	FlowStatusAttacked   = "attacked"   // 相手側がこのスピーチで反論した
+	FlowStatusDefended   = "defended"   // 主張した側が、反論に対してこのスピーチで再反論した
+	FlowStatusExtended   = "extended"   // 主張した側が、このスピーチで根拠や支持を追加した
+	FlowStatusDropped    = "dropped"    // 応答すべき側が、このスピーチで何も応答しなかった
+)
+
+// フローシートの行の種類
+const (
+	FlowItemKindNode = "node"
+	FlowItemKindEdge = "edge"
+)
+
+// FlowItem はフローシートの1行で、1つのノードまたはエッジのスピーチごとの状態です。
+// Side は最初に述べたスピーチの立場で、Statuses はスピーチと同じ順序に並びます。
+// 何も起きなかったスピーチの状態は空文字列です。
+type FlowItem struct {
+	Kind     string   `json:"kind"`               // FlowItemKindNode または FlowItemKindEdge
+	Argument string   `json:"argument,omitempty"` // Kind が node のとき
+	Cause    string   `json:"cause,omitempty"`    // Kind が edge のとき
+	Effect   string   `json:"effect,omitempty"`   // Kind が edge のとき
+	Side     string   `json:"side"`
+	Statuses []string `json:"statuses"`
+}
+
+// Label はフローシートに表示する行の名前を返します。
+func (item FlowItem) Label() string {
+	if item.Kind == FlowItemKindEdge {
+		return item.Cause + " -> " + item.Effect
+	}
+	return item.Argument
+}
+
+// FlowSheet はディベートの議論をスピーチごとに追跡した表です。
+type FlowSheet struct {
+	Speeches []Speech   `json:"speeches"`
+	Items    []FlowItem `json:"items"`
+}
+
+// flowActivity は1回のスピーチで各行に起きた出来事です。
+type flowActivity struct {
+	introduced map[string]bool
+	attacked   map[string]bool
+	defended   map[string]bool
+	extended   map[string]bool
+}
+
+// BuildFlowSheet はDebateの履歴から、各ノード・エッジのスピーチごとの状態を計算します。
+//
+// 反論関係の対象は attacked、反論ノードや反論関係への反論を行った場合はその反論の対象が defended になります。
+// ターンは、ターンのノードの原因となったノードへの反論として扱います。
+// 主張した側が反論せずにアノテーションや原因を追加した場合は extended です。
+// 主張した側が述べたり守ったりした後の相手側のスピーチ、および相手側が反論した後の主張した側のスピーチで
+// 何も応答がなかった場合は dropped とし、以降はどちらかが再び触れるまで状態を空にします。
+func BuildFlowSheet(debate *Debate) (*FlowSheet, error) {
+	if debate == nil {
+		return nil, fmt.Errorf("cannot build flow sheet from nil Debate")
+	}
+	steps, err := debate.Replay()
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay debate for flow sheet: %w", err)
+	}
+
+	items := make([]*FlowItem, 0)
+	itemByKey := make(map[string]*FlowItem)
+	expected := make(map[string]string) // 次に応答すべき立場
+	speeches := make([]Speech, 0, len(steps))
+
+	for i, step := range steps {
+		speeches = append(speeches, step.Speech)
+		activity := collectFlowActivity(step)
+
+		// 新しく述べられたノードとエッジを行として追加する
+		added := make([]*FlowItem, 0)
+		for _, argument := range step.Diff.AddedNodes {
+			added = append(added, &FlowItem{Kind: FlowItemKindNode, Argument: argument})
+		}
+		for _, ref := range step.Diff.AddedEdges {
+			added = append(added, &FlowItem{Kind: FlowItemKindEdge, Cause: ref.Cause, Effect: ref.Effect})
+		}
+		for _, item := range added {
+			key := flowItemKey(item.Kind, item.Argument, item.Cause, item.Effect)
+			activity.introduced[key] = true
+			// 一度削除された後に再び述べられた場合は、既存の行を使う
+			if _, exists := itemByKey[key]; exists {
+				continue
+			}
+			item.Side = step.Speech.Side
+			item.Statuses = make([]string, len(steps))
+			itemByKey[key] = item
+			items = append(items, item)
+		}
+
+		for _, item := range items {
+			key := flowItemKey(item.Kind, item.Argument, item.Cause, item.Effect)
+			if !flowItemExists(step.After, item) {
+				delete(expected, key)
+				continue
+			}
+			opponent := otherSide(item.Side)
+			isOwner := step.Speech.Side == item.Side
+
+			status := ""
+			switch {
+			case activity.introduced[key]:
+				status = FlowStatusIntroduced
+			case isOwner && activity.defended[key]:
+				status = FlowStatusDefended
+			case !isOwner && activity.attacked[key]:
+				status = FlowStatusAttacked
+			case isOwner && activity.extended[key]:
+				status = FlowStatusExtended
+			case expected[key] == step.Speech.Side:
+				status = FlowStatusDropped
+			}
+			item.Statuses[i] = status
+
+			switch status {
+			case FlowStatusIntroduced, FlowStatusDefended, FlowStatusExtended:
+				expected[key] = opponent
+			case FlowStatusAttacked:
+				expected[key] = item.Side
+			case FlowStatusDropped:
+				delete(expected, key)
+			}
+		}
+	}
+
+	sheet := &FlowSheet{Speeches: speeches, Items: make([]FlowItem, 0, len(items))}
+	for _, item := range items {
+		sheet.Items = append(sheet.Items, *item)
+	}
+	return sheet, nil
+}
+
+// collectFlowActivity はスピーチによる差分から、各行への反論・再反論・拡張を集めます。
+func collectFlowActivity(step DebateReplayStep) flowActivity {
+	activity := flowActivity{
+		introduced: make(map[string]bool),
+		attacked:   make(map[string]bool),
+		defended:   make(map[string]bool),
+		extended:   make(map[string]bool),
+	}
+
+	after := step.After
+	after.mu.RLock()
+	defer after.mu.RUnlock()
+	rebuttalsByNode := make(map[string][]RebuttalRelation)
+	for _, relation := range after.rebuttalRelations() {
+		rebuttalsByNode[relation.RebuttalArgument] = append(rebuttalsByNode[relation.RebuttalArgument], relation)
+	}
+
+	for _, relation := range step.Diff.AddedRebuttals {
+		for _, key := range relationTargetKeys(after, relation) {
+			activity.attacked[key] = true
+		}
+		// 反論関係への反論は、対象の反論を行ったノードへの反論であり、その反論の対象を守ることになる
+		if relation.TargetRelation != nil {
+			activity.attacked[flowItemKey(FlowItemKindNode, relation.TargetRelation.RebuttalArgument, "", "")] = true
+			for _, key := range relationTargetKeys(after, *relation.TargetRelation) {
+				activity.defended[key] = true
+			}
+		}
+		// 反論ノードへの反論は、その反論の対象を守ることになる
+		for _, target := range relationTargetArguments(after, relation) {
+			for _, attacked := range rebuttalsByNode[target] {
+				for _, key := range relationTargetKeys(after, attacked) {
+					activity.defended[key] = true
+				}
+			}
+		}
+	}
+
+	for _, nodeDiff := range step.Diff.ModifiedNodes {
+		activity.extended[flowItemKey(FlowItemKindNode, nodeDiff.Argument, "", "")] = true
+	}
+	for _, edgeDiff := range step.Diff.ModifiedEdges {
+		activity.extended[flowItemKey(FlowItemKindEdge, "", edgeDiff.Cause, edgeDiff.Effect)] = true
+	}
+	for _, ref := range step.Diff.AddedEdges {
+		// 既存のノードに新しい原因が加わった場合は、そのノードへの支持の追加とみなす
+		activity.extended[flowItemKey(FlowItemKindNode, ref.Effect, "", "")] = true
+	}
+	return activity
+}
+
+// relationTargetArguments は反論関係が反論しているノードのArgumentを返します。
+// ターンの場合は認めているノードを返し、不明な場合はターンのノードの原因となったノードを返します。
+// ロックは取得しません。
+func relationTargetArguments(dg *DebateGraph, relation RebuttalRelation) []string {
+	switch relation.Kind {
+	case RebuttalKindNode, RebuttalKindCounterArgument:
+		return []string{relation.TargetArgument}
+	case RebuttalKindTurnArgument:
+		if len(relation.TargetCauseArguments) > 0 {
+			return relation.TargetCauseArguments
+		}
+		node, exists := dg.getNode(relation.RebuttalArgument)
+		if !exists {
+			return nil
+		}
+		arguments := make([]string, 0, len(node.Causes))
+		for _, edge := range node.Causes {
+			arguments = append(arguments, edge.Cause.Argument)
+		}
+		sort.Strings(arguments)
+		return arguments
+	}
+	return nil
+}
+
+// relationTargetKeys は反論関係が反論している行のキーを返します。
+func relationTargetKeys(dg *DebateGraph, relation RebuttalRelation) []string {
+	if relation.Kind == RebuttalKindEdge {
+		return []string{flowItemKey(FlowItemKindEdge, "", relation.TargetCauseArgument, relation.TargetEffectArgument)}
+	}
+	keys := make([]string, 0)
+	for _, argument := range relationTargetArguments(dg, relation) {
+		keys = append(keys, flowItemKey(FlowItemKindNode, argument, "", ""))
+	}
+	return keys
+}
+
+func flowItemKey(kind, argument, cause, effect string) string {
+	if kind == FlowItemKindEdge {
+		return kind + "\x00" + generateEdgeKey(cause, effect)
+	}
+	return kind + "\x00" + argument
+}
+
+func flowItemExists(dg *DebateGraph, item *FlowItem) bool {
+	if item.Kind == FlowItemKindEdge {
+		_, exists := dg.GetEdge(item.Cause, item.Effect)
+		return exists
+	}
+	_, exists := dg.GetNode(item.Argument)
+	return exists
+}
+
+func otherSide(side string) string {
+	if side == AdjudicationSideProponent {
+		return AdjudicationSideOpponent
+	}
+	return AdjudicationSideProponent
+}
+
+// ToCSV はフローシートを、スピーチごとに1列を持つCSVに変換します。
+func (fs *FlowSheet) ToCSV() (string, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	header := []string{"kind", "item", "side"}
+	for _, speech := range fs.Speeches {
+		header = append(header, speech.ID)
+	}
+	if err := writer.Write(header); err != nil {
+		return "", fmt.Errorf("failed to write flow sheet header: %w", err)
+	}
+	for _, item := range fs.Items {
+		record := append([]string{item.Kind, item.Label(), item.Side}, item.Statuses...)
+		if err := writer.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write flow sheet row: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to write flow sheet CSV: %w", err)
+	}
+	return buffer.String(), nil
+}
+
+// ToMarkdown はフローシートを、スピーチごとに1列を持つMarkdownの表に変換します。
+func (fs *FlowSheet) ToMarkdown() string {
+	var builder strings.Builder
+
+	header := []string{"種類", "議論", "立場"}
+	for _, speech := range fs.Speeches {
+		label := speech.ID
+		if speech.Speaker != "" {
+			label = fmt.Sprintf("%s (%s)", speech.ID, speech.Speaker)
+		}
+		header = append(header, label)
+	}
+	writeMarkdownRow(&builder, header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeMarkdownRow(&builder, separator)
+
+	for _, item := range fs.Items {
+		writeMarkdownRow(&builder, append([]string{item.Kind, item.Label(), item.Side}, item.Statuses...))
+	}
+	return builder.String()
+}
+
+func writeMarkdownRow(builder *strings.Builder, cells []string) {
+	builder.WriteString("|")
+	for _, cell := range cells {
+		builder.WriteString(" ")
+		builder.WriteString(escapeMarkdownCell(cell))
+		builder.WriteString(" |")
+	}
+	builder.WriteString("\n")
+}
+
+// escapeMarkdownCell は表のセルを壊さないように、縦棒と改行をエスケープします。
+func escapeMarkdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	text = strings.ReplaceAll(text, "\r\n", " ")
+	return strings.ReplaceAll(text, "\n", " ")
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFlowSheet(t *testing.T) {
+	debate, err := NewDebate("法人税を減税すべきである", nil)
+	require.NoError(t, err)
+
+	constructive := NewDebateGraph()
+	require.NoError(t, constructive.AddNode(NewDebateGraphNode("法人税を減税する", false)))
+	require.NoError(t, constructive.AddNode(NewDebateGraphNode("企業の投資が増える", false)))
+	cause, _ := constructive.GetNode("法人税を減税する")
+	effect, _ := constructive.GetNode("企業の投資が増える")
+	require.NoError(t, constructive.AddEdge(NewDebateGraphEdge(cause, effect, false)))
+	require.NoError(t, debate.AddSpeech(Speech{ID: "1AC", Side: AdjudicationSideProponent}, constructive))
+
+	rebuttal, err := debate.NextRevision()
+	require.NoError(t, err)
+	require.NoError(t, rebuttal.AddNode(NewDebateGraphNode("減税分は内部留保に回る", true)))
+	require.NoError(t, rebuttal.AddRebuttalRelation(RebuttalRelation{
+		Kind: RebuttalKindEdge, TargetCauseArgument: "法人税を減税する", TargetEffectArgument: "企業の投資が増える",
+		RebuttalType: "certainty", RebuttalArgument: "減税分は内部留保に回る",
+	}))
+	require.NoError(t, debate.AddSpeech(Speech{ID: "1NC", Speaker: "鈴木", Side: AdjudicationSideOpponent}, rebuttal))
+
+	defense, err := debate.NextRevision()
+	require.NoError(t, err)
+	require.NoError(t, defense.AddNode(NewDebateGraphNode("内部留保も設備投資に使われる", false)))
+	require.NoError(t, defense.AddRebuttalRelation(RebuttalRelation{
+		Kind: RebuttalKindCounterArgument, TargetArgument: "減税分は内部留保に回る", RebuttalArgument: "内部留保も設備投資に使われる",
+	}))
+	require.NoError(t, debate.AddSpeech(Speech{ID: "2AC", Side: AdjudicationSideProponent}, defense))
+
+	sheet, err := BuildFlowSheet(debate)
+	require.NoError(t, err)
+
+	statuses := make(map[string][]string)
+	for _, item := range sheet.Items {
+		statuses[item.Label()] = item.Statuses
+	}
+	assert.Equal(t, []string{FlowStatusIntroduced, FlowStatusDropped, ""}, statuses["企業の投資が増える"])
+	assert.Equal(t, []string{FlowStatusIntroduced, FlowStatusAttacked, FlowStatusDefended}, statuses["法人税を減税する -> 企業の投資が増える"])
+	assert.Equal(t, []string{"", FlowStatusIntroduced, FlowStatusAttacked}, statuses["減税分は内部留保に回る"])
+
+	csvText, err := sheet.ToCSV()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(csvText, "kind,item,side,1AC,1NC,2AC\n"))
+
+	markdown := sheet.ToMarkdown()
+	assert.Contains(t, markdown, "| 1NC (鈴木) |")
+	assert.Contains(t, markdown, "| edge | 法人税を減税する -> 企業の投資が増える | proponent | introduced | attacked | defended |")
+}
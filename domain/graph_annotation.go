@@ -0,0 +1,154 @@
+package domain
+
+import "fmt"
+
+// ノードのアノテーションの種類。JSONのフィールド名と同じ値を使用します。
+const (
+	NodeAnnotationImportance          = "importance"
+	NodeAnnotationUniqueness          = "uniqueness"
+	NodeAnnotationImportanceRebuttals = "importance_rebuttals"
+	NodeAnnotationUniquenessRebuttals = "uniqueness_rebuttals"
+)
+
+// エッジのアノテーションの種類。JSONのフィールド名と同じ値を使用します。
+const (
+	EdgeAnnotationCertainty           = "certainty"
+	EdgeAnnotationUniqueness          = "uniqueness"
+	EdgeAnnotationCertaintyRebuttal   = "certainty_rebuttal"
+	EdgeAnnotationUniquenessRebuttals = "uniqueness_rebuttals"
+)
+
+// nodeAnnotationList は種類に対応するノードのアノテーションリストを返します。
+func nodeAnnotationList(node *DebateGraphNode, annotationType string) (*[]Evidence, error) {
+	switch annotationType {
+	case NodeAnnotationImportance:
+		return &node.Importance, nil
+	case NodeAnnotationUniqueness:
+		return &node.Uniqueness, nil
+	case NodeAnnotationImportanceRebuttals:
+		return &node.ImportanceRebuttals, nil
+	case NodeAnnotationUniquenessRebuttals:
+		return &node.UniquenessRebuttals, nil
+	}
+	return nil, fmt.Errorf("unknown node annotation type '%s'", annotationType)
+}
+
+// edgeAnnotationList は種類に対応するエッジのアノテーションリストを返します。
+func edgeAnnotationList(edge *DebateGraphEdge, annotationType string) (*[]Evidence, error) {
+	switch annotationType {
+	case EdgeAnnotationCertainty:
+		return &edge.Certainty, nil
+	case EdgeAnnotationUniqueness:
+		return &edge.Uniqueness, nil
+	case EdgeAnnotationCertaintyRebuttal:
+		return &edge.CertaintyRebuttal, nil
+	case EdgeAnnotationUniquenessRebuttals:
+		return &edge.UniquenessRebuttals, nil
+	}
+	return nil, fmt.Errorf("unknown edge annotation type '%s'", annotationType)
+}
+
+// AddNodeAnnotation はノードのアノテーションリストに根拠を追加します。
+// 複数のゴルーチンから同じグラフに対して同時に呼び出せます。
+func (dg *DebateGraph) AddNodeAnnotation(argument string, annotationType string, evidence Evidence) error {
+	if err := evidence.Validate(); err != nil {
+		return err
+	}
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	node, exists := dg.nodeMap[argument]
+	if !exists {
+		return fmt.Errorf("node '%s' not found in debate graph", argument)
+	}
+	list, err := nodeAnnotationList(node, annotationType)
+	if err != nil {
+		return err
+	}
+	*list = append(*list, evidence)
+	return nil
+}
+
+// AddEdgeAnnotation はエッジのアノテーションリストに根拠を追加します。
+// 複数のゴルーチンから同じグラフに対して同時に呼び出せます。
+func (dg *DebateGraph) AddEdgeAnnotation(causeArgument, effectArgument string, annotationType string, evidence Evidence) error {
+	if err := evidence.Validate(); err != nil {
+		return err
+	}
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	edge, exists := dg.edgeMap[generateEdgeKey(causeArgument, effectArgument)]
+	if !exists {
+		return fmt.Errorf("edge '%s -> %s' not found in debate graph", causeArgument, effectArgument)
+	}
+	list, err := edgeAnnotationList(edge, annotationType)
+	if err != nil {
+		return err
+	}
+	*list = append(*list, evidence)
+	return nil
+}
+
+// AnnotateNode はAIモデルが返した文字列 reference に対応するノードを ResolveNode で特定し、evidence を追加します。
+// evidence の引用箇所の位置が分かっている場合は、ノードが述べられている範囲としても追加します。
+// annotationType が空の場合は範囲だけを追加します。
+func (dg *DebateGraph) AnnotateNode(reference string, annotationType string, evidence Evidence) error {
+	node, _, err := dg.ResolveNode(reference)
+	if err != nil {
+		return err
+	}
+	if evidence.Source != nil {
+		if err := dg.AddNodeSource(node.Argument, *evidence.Source); err != nil {
+			return err
+		}
+	}
+	if annotationType == "" {
+		return nil
+	}
+	return dg.AddNodeAnnotation(node.Argument, annotationType, evidence)
+}
+
+// AnnotateEdge はAIモデルが返したCauseとEffectの文字列に対応するエッジを ResolveEdge で特定し、evidence を追加します。
+// evidence の引用箇所の位置が分かっている場合は、エッジの根拠となった範囲としても追加します。
+// annotationType が空の場合は範囲だけを追加します。
+func (dg *DebateGraph) AnnotateEdge(causeReference, effectReference string, annotationType string, evidence Evidence) error {
+	edge, err := dg.ResolveEdge(causeReference, effectReference)
+	if err != nil {
+		return err
+	}
+	cause, effect := edge.Cause.Argument, edge.Effect.Argument
+	if evidence.Source != nil {
+		if err := dg.AddEdgeSource(cause, effect, *evidence.Source); err != nil {
+			return err
+		}
+	}
+	if annotationType == "" {
+		return nil
+	}
+	return dg.AddEdgeAnnotation(cause, effect, annotationType, evidence)
+}
+
+// AddNodeSource はノードが述べられている文書中の範囲を追加します。
+// DebateGraphNode.AddSource と異なり、グラフのロックを取得してから追加します。
+func (dg *DebateGraph) AddNodeSource(argument string, span SourceSpan) error {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	node, exists := dg.nodeMap[argument]
+	if !exists {
+		return fmt.Errorf("node '%s' not found in debate graph", argument)
+	}
+	node.AddSource(span)
+	return nil
+}
+
+// AddEdgeSource はエッジの根拠となった文書中の範囲を追加します。
+// DebateGraphEdge.AddSource と異なり、グラフのロックを取得してから追加します。
+func (dg *DebateGraph) AddEdgeSource(causeArgument, effectArgument string, span SourceSpan) error {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	edge, exists := dg.edgeMap[generateEdgeKey(causeArgument, effectArgument)]
+	if !exists {
+		return fmt.Errorf("edge '%s -> %s' not found in debate graph", causeArgument, effectArgument)
+	}
+	edge.AddSource(span)
+	return nil
+}
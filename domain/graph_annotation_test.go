@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -race で実行すると、グラフへの並行アクセスでデータ競合が起きないことを確認できます。
+func TestConcurrentGraphMutation(t *testing.T) {
+	dg := NewDebateGraph()
+	require.NoError(t, dg.AddNode(NewDebateGraphNode("法人税を減税する", false)))
+	root, _ := dg.GetNode("法人税を減税する")
+
+	const workers = 4
+	const perWorker = 10
+	var wg, readers sync.WaitGroup
+	done := make(chan struct{})
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				argument := fmt.Sprintf("効果%d-%d", w, i)
+				node := NewDebateGraphNode(argument, false)
+				assert.NoError(t, dg.AddNode(node))
+				assert.NoError(t, dg.AddEdge(NewDebateGraphEdge(root, node, false)))
+				assert.NoError(t, dg.AddNodeAnnotation(argument, NodeAnnotationImportance, NewEvidence("重要である")))
+				assert.NoError(t, dg.AddNodeAnnotation("法人税を減税する", NodeAnnotationUniqueness, NewEvidence("現状では実現しない")))
+				assert.NoError(t, dg.AddEdgeAnnotation("法人税を減税する", argument, EdgeAnnotationCertainty, NewEvidence("確実である")))
+				assert.NoError(t, dg.AddNodeSource(argument, SourceSpan{Start: i, End: i + 1}))
+
+				counter := fmt.Sprintf("反論%d-%d", w, i)
+				assert.NoError(t, dg.AddNode(NewDebateGraphNode(counter, true)))
+				assert.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{Kind: RebuttalKindCounterArgument, TargetArgument: argument, RebuttalArgument: counter}))
+
+				turn := fmt.Sprintf("ターン%d-%d", w, i)
+				_, err := dg.ApplyOperation(GraphOperation{Op: OperationAddNode, Argument: turn, IsRebuttal: true})
+				assert.NoError(t, err)
+				assert.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{Kind: RebuttalKindTurnArgument, TargetCauseArguments: []string{argument}, RebuttalArgument: turn}))
+			}
+		}(w)
+
+		// 変更が終わるまで、スナップショットを取らずに元のグラフを読み取り、解析する
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				_, err := dg.ToCompactJSON()
+				assert.NoError(t, err)
+				_, err = dg.TopologicalOrder()
+				assert.NoError(t, err)
+				dg.ImpactNodes()
+				dg.ToLogicGraph()
+				_, err = Adjudicate(dg, DefaultAdjudicationConfig())
+				assert.NoError(t, err)
+				_, err = PropagateBeliefs(dg)
+				assert.NoError(t, err)
+				_, err = AnalyzeWeakestLinks(dg)
+				assert.NoError(t, err)
+				_, err = BuildGraphReport(dg)
+				assert.NoError(t, err)
+				_, err = DiffDebateGraphs(NewDebateGraph(), dg)
+				assert.NoError(t, err)
+				dg.GetAllEdges()
+				dg.RebuttalRelations()
+				NewArgumentResolverForDebateGraph(dg)
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	readers.Wait()
+
+	assert.Len(t, dg.Nodes, 1+3*workers*perWorker)
+	assert.Len(t, dg.GetAllEdges(), 2*workers*perWorker)
+	assert.Len(t, dg.CounterArgumentRebuttals, workers*perWorker)
+	assert.Len(t, dg.TurnArgumentRebuttals, workers*perWorker)
+	assert.Len(t, root.Uniqueness, workers*perWorker)
+
+	edge, exists := dg.GetEdge("法人税を減税する", "効果0-0")
+	require.True(t, exists)
+	assert.Len(t, edge.Certainty, 1)
+
+	err := dg.AddNodeAnnotation("法人税を減税する", "certainty", NewEvidence("不明"))
+	assert.Error(t, err)
+}
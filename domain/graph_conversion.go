@@ -0,0 +1,70 @@
+package domain
+
+import "fmt"
+
+// NewDebateGraphFromLogicGraph はLogicGraphのノードと因果関係をコピーしたDebateGraphを作成します。
+// 作成されるノードとエッジはすべて元の主張 (IsRebuttal = false) で、アノテーションは空です。
+// ノードのメタデータ (側・役割など) はそのまま引き継ぎます。
+func NewDebateGraphFromLogicGraph(logicGraph *LogicGraph) (*DebateGraph, error) {
+	if logicGraph == nil {
+		return nil, fmt.Errorf("cannot convert nil LogicGraph to DebateGraph")
+	}
+
+	debateGraph := NewDebateGraph()
+
+	// 1. LogicGraph からノードを DebateGraph にコピー
+	for _, lgNode := range logicGraph.Nodes {
+		dgNode := NewDebateGraphNode(lgNode.Argument, false)
+		dgNode.NodeMetadata = lgNode.NodeMetadata
+		if err := debateGraph.AddNode(dgNode); err != nil {
+			// LogicGraphが整合性を持っていれば、通常このエラーは発生しないはず
+			return nil, fmt.Errorf("failed to add node '%s' to DebateGraph: %w", lgNode.Argument, err)
+		}
+	}
+
+	// 2. LogicGraph からエッジを DebateGraph にコピー
+	for _, lgEffectNode := range logicGraph.Nodes {
+		for _, lgCauseNode := range lgEffectNode.Causes {
+			if lgCauseNode == nil {
+				return nil, fmt.Errorf("nil cause node found for '%s' in LogicGraph", lgEffectNode.Argument)
+			}
+			dgCauseNode, causeOk := debateGraph.GetNode(lgCauseNode.Argument)
+			if !causeOk {
+				return nil, fmt.Errorf("internal consistency error: cause node '%s' (from LogicGraph) not found in DebateGraph when creating edge", lgCauseNode.Argument)
+			}
+			dgEffectNode, effectOk := debateGraph.GetNode(lgEffectNode.Argument)
+			if !effectOk {
+				return nil, fmt.Errorf("internal consistency error: effect node '%s' (from LogicGraph) not found in DebateGraph when creating edge", lgEffectNode.Argument)
+			}
+
+			dgEdge := NewDebateGraphEdge(dgCauseNode, dgEffectNode, false)
+			if err := debateGraph.AddEdge(dgEdge); err != nil {
+				return nil, fmt.Errorf("failed to add edge '%s -> %s' to DebateGraph: %w", lgCauseNode.Argument, lgEffectNode.Argument, err)
+			}
+		}
+	}
+
+	return debateGraph, nil
+}
+
+// ToLogicGraph はDebateGraphのノードと因果エッジ、ノードのメタデータだけをコピーしたLogicGraphを作成します。
+// アノテーション、IsRebuttalフラグ、反論関係は含まれません。
+func (dg *DebateGraph) ToLogicGraph() *LogicGraph {
+	dg.mu.RLock()
+	defer dg.mu.RUnlock()
+	logicGraph := NewLogicGraph(nil)
+	for _, dgNode := range dg.Nodes {
+		lgNode := NewLogicGraphNode(dgNode.Argument)
+		lgNode.NodeMetadata = dgNode.NodeMetadata
+		logicGraph.AddNode(lgNode)
+	}
+
+	for _, dgEffectNode := range dg.Nodes {
+		lgEffectNode := logicGraph.NodeMap[dgEffectNode.Argument]
+		for _, edge := range dgEffectNode.Causes {
+			lgEffectNode.Causes = append(lgEffectNode.Causes, logicGraph.NodeMap[edge.Cause.Argument])
+		}
+	}
+
+	return logicGraph
+}
@@ -0,0 +1,313 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// GraphDiff は2つのDebateGraphの間の差分を表します。
+// ノードはArgument、エッジは (Cause, Effect) の組、反論関係はRebuttalRelation.Key() で同一性を判定します。
+type GraphDiff struct {
+	AddedNodes       []string           `json:"added_nodes"`
+	RemovedNodes     []string           `json:"removed_nodes"`
+	ModifiedNodes    []NodeDiff         `json:"modified_nodes"`
+	AddedEdges       []EdgeRef          `json:"added_edges"`
+	RemovedEdges     []EdgeRef          `json:"removed_edges"`
+	ModifiedEdges    []EdgeDiff         `json:"modified_edges"`
+	AddedRebuttals   []RebuttalRelation `json:"added_rebuttals"`
+	RemovedRebuttals []RebuttalRelation `json:"removed_rebuttals"`
+}
+
+// EdgeRef はエッジをCauseとEffectのArgumentで参照します。
+type EdgeRef struct {
+	Cause  string `json:"cause"`
+	Effect string `json:"effect"`
+}
+
+// BoolChange は真偽値の変更前後の値を保持します。
+type BoolChange struct {
+	Before bool `json:"before"`
+	After  bool `json:"after"`
+}
+
+// AnnotationDiff は1つのアノテーションリストに追加・削除されたEvidenceを保持します。
+// Field にはJSON形式と同じフィールド名 ("importance", "certainty_rebuttal" など) が入ります。
+// Evidenceはいずれかのフィールドが異なれば別のものとして扱います。
+type AnnotationDiff struct {
+	Field   string     `json:"field"`
+	Added   []Evidence `json:"added,omitempty"`
+	Removed []Evidence `json:"removed,omitempty"`
+}
+
+// FloatChange は数値の変更前後の値を保持します。未設定の値はnilです。
+type FloatChange struct {
+	Before *float64 `json:"before"`
+	After  *float64 `json:"after"`
+}
+
+// MetadataChange はノードのメタデータの変更前後の値を保持します。
+type MetadataChange struct {
+	Before NodeMetadata `json:"before"`
+	After  NodeMetadata `json:"after"`
+}
+
+// StringChange は文字列の変更前後の値を保持します。未設定の値は空文字列です。
+type StringChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// SourcesDiff は出典の範囲のリストに追加・削除されたSourceSpanを保持します。
+type SourcesDiff struct {
+	Added   []SourceSpan `json:"added,omitempty"`
+	Removed []SourceSpan `json:"removed,omitempty"`
+}
+
+// NodeDiff は両方のグラフに存在するノードの変更内容です。
+type NodeDiff struct {
+	Argument     string           `json:"argument"`
+	IsRebuttal   *BoolChange      `json:"is_rebuttal,omitempty"`
+	Metadata     *MetadataChange  `json:"metadata,omitempty"`
+	Magnitude    *FloatChange     `json:"magnitude,omitempty"`
+	IntroducedIn *StringChange    `json:"introduced_in,omitempty"`
+	Sources      *SourcesDiff     `json:"sources,omitempty"`
+	Annotations  []AnnotationDiff `json:"annotations,omitempty"`
+}
+
+// EdgeDiff は両方のグラフに存在するエッジの変更内容です。
+type EdgeDiff struct {
+	Cause       string           `json:"cause"`
+	Effect      string           `json:"effect"`
+	IsRebuttal  *BoolChange      `json:"is_rebuttal,omitempty"`
+	Probability *FloatChange     `json:"probability,omitempty"`
+	Sources     *SourcesDiff     `json:"sources,omitempty"`
+	Annotations []AnnotationDiff `json:"annotations,omitempty"`
+}
+
+// annotationField はアノテーションリストとそのJSONフィールド名の組です。
+type annotationField struct {
+	Name   string
+	Values []Evidence
+}
+
+func nodeAnnotationFields(node *DebateGraphNode) []annotationField {
+	return []annotationField{
+		{Name: NodeAnnotationImportance, Values: node.Importance},
+		{Name: NodeAnnotationUniqueness, Values: node.Uniqueness},
+		{Name: NodeAnnotationImportanceRebuttals, Values: node.ImportanceRebuttals},
+		{Name: NodeAnnotationUniquenessRebuttals, Values: node.UniquenessRebuttals},
+	}
+}
+
+func edgeAnnotationFields(edge *DebateGraphEdge) []annotationField {
+	return []annotationField{
+		{Name: EdgeAnnotationCertainty, Values: edge.Certainty},
+		{Name: EdgeAnnotationUniqueness, Values: edge.Uniqueness},
+		{Name: EdgeAnnotationCertaintyRebuttal, Values: edge.CertaintyRebuttal},
+		{Name: EdgeAnnotationUniquenessRebuttals, Values: edge.UniquenessRebuttals},
+	}
+}
+
+// DiffDebateGraphs はbeforeからafterへの変更内容を計算します。
+// 結果の各リストは正規化された順序で並ぶため、同じ入力からは常に同じ差分が得られます。
+func DiffDebateGraphs(before, after *DebateGraph) (*GraphDiff, error) {
+	if before == nil || after == nil {
+		return nil, fmt.Errorf("cannot diff nil DebateGraph")
+	}
+	return readLocked(func() (*GraphDiff, error) { return diffDebateGraphs(before, after) }, before, after)
+}
+
+// diffDebateGraphs はロックを取得せずに DiffDebateGraphs の差分を計算します。
+func diffDebateGraphs(before, after *DebateGraph) (*GraphDiff, error) {
+	diff := &GraphDiff{
+		AddedNodes:       make([]string, 0),
+		RemovedNodes:     make([]string, 0),
+		ModifiedNodes:    make([]NodeDiff, 0),
+		AddedEdges:       make([]EdgeRef, 0),
+		RemovedEdges:     make([]EdgeRef, 0),
+		ModifiedEdges:    make([]EdgeDiff, 0),
+		AddedRebuttals:   make([]RebuttalRelation, 0),
+		RemovedRebuttals: make([]RebuttalRelation, 0),
+	}
+
+	// 1. ノードの差分
+	for _, beforeNode := range before.Nodes {
+		afterNode, exists := after.getNode(beforeNode.Argument)
+		if !exists {
+			diff.RemovedNodes = append(diff.RemovedNodes, beforeNode.Argument)
+			continue
+		}
+		nodeDiff := NodeDiff{
+			Argument:    beforeNode.Argument,
+			Annotations: diffAnnotationFields(nodeAnnotationFields(beforeNode), nodeAnnotationFields(afterNode)),
+		}
+		if beforeNode.IsRebuttal != afterNode.IsRebuttal {
+			nodeDiff.IsRebuttal = &BoolChange{Before: beforeNode.IsRebuttal, After: afterNode.IsRebuttal}
+		}
+		if beforeNode.NodeMetadata != afterNode.NodeMetadata {
+			nodeDiff.Metadata = &MetadataChange{Before: beforeNode.NodeMetadata, After: afterNode.NodeMetadata}
+		}
+		if !equalFloatPtr(beforeNode.Magnitude, afterNode.Magnitude) {
+			nodeDiff.Magnitude = &FloatChange{Before: beforeNode.Magnitude, After: afterNode.Magnitude}
+		}
+		if beforeNode.IntroducedIn != afterNode.IntroducedIn {
+			nodeDiff.IntroducedIn = &StringChange{Before: beforeNode.IntroducedIn, After: afterNode.IntroducedIn}
+		}
+		nodeDiff.Sources = diffSources(beforeNode.Sources, afterNode.Sources)
+		if nodeDiff.IsRebuttal != nil || nodeDiff.Metadata != nil || nodeDiff.Magnitude != nil ||
+			nodeDiff.IntroducedIn != nil || nodeDiff.Sources != nil || len(nodeDiff.Annotations) > 0 {
+			diff.ModifiedNodes = append(diff.ModifiedNodes, nodeDiff)
+		}
+	}
+	for _, afterNode := range after.Nodes {
+		if _, exists := before.getNode(afterNode.Argument); !exists {
+			diff.AddedNodes = append(diff.AddedNodes, afterNode.Argument)
+		}
+	}
+	sort.Strings(diff.AddedNodes)
+	sort.Strings(diff.RemovedNodes)
+	sort.Slice(diff.ModifiedNodes, func(i, j int) bool {
+		return diff.ModifiedNodes[i].Argument < diff.ModifiedNodes[j].Argument
+	})
+
+	// 2. エッジの差分 (GetAllEdgesはソート済みなので結果もソートされる)
+	for _, beforeEdge := range before.allEdges() {
+		ref := EdgeRef{Cause: beforeEdge.Cause.Argument, Effect: beforeEdge.Effect.Argument}
+		afterEdge, exists := after.getEdge(ref.Cause, ref.Effect)
+		if !exists {
+			diff.RemovedEdges = append(diff.RemovedEdges, ref)
+			continue
+		}
+		edgeDiff := EdgeDiff{
+			Cause:       ref.Cause,
+			Effect:      ref.Effect,
+			Annotations: diffAnnotationFields(edgeAnnotationFields(beforeEdge), edgeAnnotationFields(afterEdge)),
+		}
+		if beforeEdge.IsRebuttal != afterEdge.IsRebuttal {
+			edgeDiff.IsRebuttal = &BoolChange{Before: beforeEdge.IsRebuttal, After: afterEdge.IsRebuttal}
+		}
+		if !equalFloatPtr(beforeEdge.Probability, afterEdge.Probability) {
+			edgeDiff.Probability = &FloatChange{Before: beforeEdge.Probability, After: afterEdge.Probability}
+		}
+		edgeDiff.Sources = diffSources(beforeEdge.Sources, afterEdge.Sources)
+		if edgeDiff.IsRebuttal != nil || edgeDiff.Probability != nil || edgeDiff.Sources != nil || len(edgeDiff.Annotations) > 0 {
+			diff.ModifiedEdges = append(diff.ModifiedEdges, edgeDiff)
+		}
+	}
+	for _, afterEdge := range after.allEdges() {
+		if _, exists := before.getEdge(afterEdge.Cause.Argument, afterEdge.Effect.Argument); !exists {
+			diff.AddedEdges = append(diff.AddedEdges, EdgeRef{Cause: afterEdge.Cause.Argument, Effect: afterEdge.Effect.Argument})
+		}
+	}
+
+	// 3. 反論関係の差分 (RebuttalRelationsはKey順にソート済み)
+	// 同じKeyの反論関係が重複している場合もあるため、多重集合として比較し、個数の増減も差分とする
+	beforeRelations := before.rebuttalRelations()
+	afterRelations := after.rebuttalRelations()
+	diff.RemovedRebuttals = append(diff.RemovedRebuttals, subtractRelations(beforeRelations, afterRelations)...)
+	diff.AddedRebuttals = append(diff.AddedRebuttals, subtractRelations(afterRelations, beforeRelations)...)
+
+	return diff, nil
+}
+
+// diffAnnotationFields は同じ順序で並んだアノテーションリスト同士を比較し、変更のあったものだけを返します。
+func diffAnnotationFields(before, after []annotationField) []AnnotationDiff {
+	diffs := make([]AnnotationDiff, 0)
+	for i := range before {
+		added := subtractEvidence(after[i].Values, before[i].Values)
+		removed := subtractEvidence(before[i].Values, after[i].Values)
+		if len(added) > 0 || len(removed) > 0 {
+			diffs = append(diffs, AnnotationDiff{Field: before[i].Name, Added: added, Removed: removed})
+		}
+	}
+	return diffs
+}
+
+// subtractEvidence はaからbに含まれる要素を多重集合として取り除いた結果を、aの順序のまま返します。
+func subtractEvidence(a, b []Evidence) []Evidence {
+	remaining := make(map[string]int, len(b))
+	for _, e := range b {
+		remaining[e.Key()]++
+	}
+	var result []Evidence
+	for _, e := range a {
+		if remaining[e.Key()] > 0 {
+			remaining[e.Key()]--
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// subtractRelations はaからbに含まれる反論関係を多重集合として取り除いた結果を、aの順序のまま返します。
+func subtractRelations(a, b []RebuttalRelation) []RebuttalRelation {
+	remaining := make(map[string]int, len(b))
+	for _, r := range b {
+		remaining[r.Key()]++
+	}
+	var result []RebuttalRelation
+	for _, r := range a {
+		if remaining[r.Key()] > 0 {
+			remaining[r.Key()]--
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// diffSources は出典の範囲のリストを多重集合として比較し、変更がなければnilを返します。
+func diffSources(before, after []SourceSpan) *SourcesDiff {
+	subtract := func(a, b []SourceSpan) []SourceSpan {
+		remaining := make(map[SourceSpan]int, len(b))
+		for _, s := range b {
+			remaining[s]++
+		}
+		var result []SourceSpan
+		for _, s := range a {
+			if remaining[s] > 0 {
+				remaining[s]--
+				continue
+			}
+			result = append(result, s)
+		}
+		return result
+	}
+	added := subtract(after, before)
+	removed := subtract(before, after)
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+	return &SourcesDiff{Added: added, Removed: removed}
+}
+
+// equalFloatPtr は2つの値がともに未設定か、同じ値であるかを返します。
+func equalFloatPtr(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// IsEmpty は差分が存在しない場合にtrueを返します。
+func (d *GraphDiff) IsEmpty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 && len(d.ModifiedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0 && len(d.ModifiedEdges) == 0 &&
+		len(d.AddedRebuttals) == 0 && len(d.RemovedRebuttals) == 0
+}
+
+// ToJSON はGraphDiffをインデント付きのJSON文字列に変換します。
+func (d *GraphDiff) ToJSON() (string, error) {
+	if d == nil {
+		return "", fmt.Errorf("cannot convert nil GraphDiff to JSON")
+	}
+
+	jsonData, err := json.MarshalIndent(d, "", "    ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal GraphDiff to JSON: %w", err)
+	}
+
+	return string(jsonData), nil
+}
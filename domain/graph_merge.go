@@ -0,0 +1,587 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// マージ時に検出される競合の種類
+const (
+	// 一方で削除された要素が、もう一方では変更されていた
+	MergeConflictDeleteModify = "delete_modify"
+	// マージ後のグラフで参照先のノードやエッジが存在しなくなった
+	MergeConflictDanglingReference = "dangling_reference"
+	// 両方で同じ属性が異なる値に変更された
+	MergeConflictModifyModify = "modify_modify"
+)
+
+// MergeConflict は三方向マージで自動解決できなかった、または解決方針を利用者に知らせるべき箇所です。
+type MergeConflict struct {
+	Type        string            `json:"type"`         // MergeConflict* のいずれか
+	ElementKind string            `json:"element_kind"` // "node", "edge", "rebuttal" のいずれか
+	Argument    string            `json:"argument,omitempty"`
+	Cause       string            `json:"cause,omitempty"`
+	Effect      string            `json:"effect,omitempty"`
+	Relation    *RebuttalRelation `json:"relation,omitempty"`
+	DeletedIn   string            `json:"deleted_in,omitempty"` // "ours" または "theirs"
+	Field       string            `json:"field,omitempty"`      // modify_modify のときの属性名 ("magnitude", "probability", "metadata.side" など)
+	Ours        string            `json:"ours,omitempty"`       // modify_modify のときのoursの値。未設定は空
+	Theirs      string            `json:"theirs,omitempty"`     // modify_modify のときのtheirsの値。未設定は空
+	Resolution  string            `json:"resolution"`           // どのように解決したかの説明
+}
+
+// MergeResult は三方向マージの結果です。
+// Conflicts が空でない場合も Graph は整合性のある状態で返されます。
+type MergeResult struct {
+	Graph     *DebateGraph    `json:"-"`
+	Conflicts []MergeConflict `json:"conflicts"`
+}
+
+// MergeDebateGraphs は共通の祖先baseから別々に編集されたoursとtheirsを三方向マージします。
+//
+//   - ノード・エッジ・反論関係は、どちらか一方で追加されたものは追加し、どちらか一方で削除されたものは削除します。
+//   - アノテーションのリストは要素単位でマージします。両方で同じEvidenceが追加された場合は1つにまとめます。
+//   - 一方で削除された要素がもう一方で変更されていた場合は、変更を失わないよう要素を残して競合として報告します。
+//   - マージ後に参照先が存在しなくなった反論関係は取り除き、競合として報告します。
+func MergeDebateGraphs(base, ours, theirs *DebateGraph) (*MergeResult, error) {
+	if base == nil || ours == nil || theirs == nil {
+		return nil, fmt.Errorf("cannot merge nil DebateGraph")
+	}
+	return readLocked(func() (*MergeResult, error) { return mergeDebateGraphs(base, ours, theirs) }, base, ours, theirs)
+}
+
+// mergeDebateGraphs はロックを取得せずに MergeDebateGraphs のマージを行います。
+func mergeDebateGraphs(base, ours, theirs *DebateGraph) (*MergeResult, error) {
+	merged := NewDebateGraph()
+	result := &MergeResult{Graph: merged, Conflicts: make([]MergeConflict, 0)}
+
+	baseRelations := relationsByKey(base)
+	oursRelations := relationsByKey(ours)
+	theirsRelations := relationsByKey(theirs)
+
+	// 1. ノードのマージ
+	for _, argument := range unionArguments(base, ours, theirs) {
+		baseNode, inBase := base.getNode(argument)
+		oursNode, inOurs := ours.getNode(argument)
+		theirsNode, inTheirs := theirs.getNode(argument)
+
+		var node *DebateGraphNode
+		switch {
+		case inOurs && inTheirs:
+			node = mergeNode(baseNode, oursNode, theirsNode)
+			result.Conflicts = append(result.Conflicts, nodeModifyConflicts(baseNode, oursNode, theirsNode)...)
+		case inOurs && !inBase:
+			node = mergeNode(nil, oursNode, nil)
+		case inTheirs && !inBase:
+			node = mergeNode(nil, nil, theirsNode)
+		case inOurs:
+			// theirsで削除された
+			if isNodeModified(base, ours, baseRelations, oursRelations, argument) {
+				node = mergeNode(baseNode, oursNode, baseNode)
+				result.Conflicts = append(result.Conflicts, MergeConflict{
+					Type: MergeConflictDeleteModify, ElementKind: "node", Argument: argument, DeletedIn: "theirs",
+					Resolution: "kept the node modified in ours",
+				})
+			}
+		case inTheirs:
+			// oursで削除された
+			if isNodeModified(base, theirs, baseRelations, theirsRelations, argument) {
+				node = mergeNode(baseNode, baseNode, theirsNode)
+				result.Conflicts = append(result.Conflicts, MergeConflict{
+					Type: MergeConflictDeleteModify, ElementKind: "node", Argument: argument, DeletedIn: "ours",
+					Resolution: "kept the node modified in theirs",
+				})
+			}
+		}
+
+		if node != nil {
+			if err := merged.AddNode(node); err != nil {
+				return nil, fmt.Errorf("failed to add merged node '%s': %w", argument, err)
+			}
+		}
+	}
+
+	// 2. エッジのマージ
+	for _, ref := range unionEdgeRefs(base, ours, theirs) {
+		baseEdge, inBase := base.getEdge(ref.Cause, ref.Effect)
+		oursEdge, inOurs := ours.getEdge(ref.Cause, ref.Effect)
+		theirsEdge, inTheirs := theirs.getEdge(ref.Cause, ref.Effect)
+
+		var isRebuttal bool
+		var probability *float64
+		var fields [][]Evidence
+		switch {
+		case inOurs && inTheirs:
+			isRebuttal, probability, fields = mergeEdgeAttributes(baseEdge, oursEdge, theirsEdge)
+			if conflict := modifyConflict(edgeProbabilityOrNil(baseEdge), oursEdge.Probability, theirsEdge.Probability); conflict != nil {
+				conflict.ElementKind, conflict.Cause, conflict.Effect, conflict.Field = "edge", ref.Cause, ref.Effect, "probability"
+				result.Conflicts = append(result.Conflicts, *conflict)
+			}
+		case inOurs && !inBase:
+			isRebuttal, probability, fields = mergeEdgeAttributes(nil, oursEdge, nil)
+		case inTheirs && !inBase:
+			isRebuttal, probability, fields = mergeEdgeAttributes(nil, nil, theirsEdge)
+		case inOurs:
+			if !isEdgeModified(baseEdge, oursEdge) {
+				continue
+			}
+			isRebuttal, probability, fields = mergeEdgeAttributes(baseEdge, oursEdge, baseEdge)
+			result.Conflicts = append(result.Conflicts, MergeConflict{
+				Type: MergeConflictDeleteModify, ElementKind: "edge", Cause: ref.Cause, Effect: ref.Effect, DeletedIn: "theirs",
+				Resolution: "kept the edge modified in ours",
+			})
+		case inTheirs:
+			if !isEdgeModified(baseEdge, theirsEdge) {
+				continue
+			}
+			isRebuttal, probability, fields = mergeEdgeAttributes(baseEdge, baseEdge, theirsEdge)
+			result.Conflicts = append(result.Conflicts, MergeConflict{
+				Type: MergeConflictDeleteModify, ElementKind: "edge", Cause: ref.Cause, Effect: ref.Effect, DeletedIn: "ours",
+				Resolution: "kept the edge modified in theirs",
+			})
+		default:
+			continue
+		}
+
+		causeNode, causeExists := merged.GetNode(ref.Cause)
+		effectNode, effectExists := merged.GetNode(ref.Effect)
+		if !causeExists || !effectExists {
+			result.Conflicts = append(result.Conflicts, MergeConflict{
+				Type: MergeConflictDanglingReference, ElementKind: "edge", Cause: ref.Cause, Effect: ref.Effect,
+				Resolution: "dropped the edge because one of its nodes was deleted",
+			})
+			continue
+		}
+		edge := NewDebateGraphEdge(causeNode, effectNode, isRebuttal)
+		edge.Certainty, edge.Uniqueness, edge.CertaintyRebuttal, edge.UniquenessRebuttals = fields[0], fields[1], fields[2], fields[3]
+		edge.Sources = unionSourceSpans(edgeSourcesOrNil(oursEdge), edgeSourcesOrNil(theirsEdge))
+		edge.Probability = probability
+		if err := merged.AddEdge(edge); err != nil {
+			return nil, fmt.Errorf("failed to add merged edge '%s -> %s': %w", ref.Cause, ref.Effect, err)
+		}
+	}
+
+	// 3. 反論関係のマージ
+	for _, key := range unionRelationKeys(base, ours, theirs) {
+		_, inBase := baseRelations[key]
+		oursRelation, inOurs := oursRelations[key]
+		theirsRelation, inTheirs := theirsRelations[key]
+
+		var relation RebuttalRelation
+		switch {
+		case inOurs && inTheirs:
+			relation = oursRelation
+		case inOurs && !inBase:
+			relation = oursRelation
+		case inTheirs && !inBase:
+			relation = theirsRelation
+		default:
+			// どちらか一方（または両方）で削除された
+			continue
+		}
+
+		if err := merged.AddRebuttalRelation(relation); err != nil {
+			result.Conflicts = append(result.Conflicts, MergeConflict{
+				Type: MergeConflictDanglingReference, ElementKind: "rebuttal", Relation: &relation,
+				Resolution: fmt.Sprintf("dropped the rebuttal relation: %v", err),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// mergeNode は3つの版のノードから属性をマージした新しいノードを作成します。
+// 存在しない版にはnilを渡します。
+func mergeNode(base, ours, theirs *DebateGraphNode) *DebateGraphNode {
+	present := ours
+	if present == nil {
+		present = theirs
+	}
+
+	node := NewDebateGraphNode(present.Argument, mergeBool(
+		base != nil && base.IsRebuttal, base != nil,
+		ours != nil && ours.IsRebuttal, ours != nil,
+		theirs != nil && theirs.IsRebuttal, theirs != nil,
+	))
+	fields := mergeAnnotationFields(nodeAnnotationFieldsOrNil(base), nodeAnnotationFieldsOrNil(ours), nodeAnnotationFieldsOrNil(theirs))
+	node.Importance, node.Uniqueness, node.ImportanceRebuttals, node.UniquenessRebuttals = fields[0], fields[1], fields[2], fields[3]
+	node.Magnitude = mergeFloatPtr(nodeMagnitudeOrNil(base), nodeMagnitudeOrNil(ours), nodeMagnitudeOrNil(theirs))
+	node.NodeMetadata = mergeNodeMetadata(nodeMetadataOrNil(base), nodeMetadataOrNil(ours), nodeMetadataOrNil(theirs))
+	if node.NodeMetadata.Validate() != nil {
+		// 項目ごとのマージで役割と影響の向きの組み合わせが不正になった場合は、片方の版のメタデータをそのまま使う
+		node.NodeMetadata = present.NodeMetadata
+	}
+	// 導入したスピーチは最初に記録された値を保持する
+	for _, version := range []*DebateGraphNode{base, ours, theirs} {
+		if version != nil && version.IntroducedIn != "" {
+			node.IntroducedIn = version.IntroducedIn
+			break
+		}
+	}
+	// 文書中の範囲は削除されることがないため、両方の版の和集合をとる
+	node.Sources = unionSourceSpans(nodeSourcesOrNil(ours), nodeSourcesOrNil(theirs))
+	return node
+}
+
+// mergeNodeMetadata はメタデータを項目ごとに三方向マージします。存在しない版にはnilを渡し、空の値として扱います。
+// 両方の版で異なる値に変更された項目はoursの値を優先します。
+func mergeNodeMetadata(base, ours, theirs *NodeMetadata) NodeMetadata {
+	pick := func(field func(*NodeMetadata) string) string {
+		var baseValue string
+		if base != nil {
+			baseValue = field(base)
+		}
+		if ours != nil && field(ours) != baseValue {
+			return field(ours)
+		}
+		if theirs != nil && field(theirs) != baseValue {
+			return field(theirs)
+		}
+		return baseValue
+	}
+	return NodeMetadata{
+		Side:        pick(func(m *NodeMetadata) string { return m.Side }),
+		Role:        pick(func(m *NodeMetadata) string { return m.Role }),
+		Polarity:    pick(func(m *NodeMetadata) string { return m.Polarity }),
+		Stakeholder: pick(func(m *NodeMetadata) string { return m.Stakeholder }),
+	}
+}
+
+// nodeModifyConflicts は両方の版でノードの大きさやメタデータの項目が異なる値に変更された箇所を返します。
+// baseに存在しないノードは、未設定の値から変更されたものとして扱います。
+func nodeModifyConflicts(base, ours, theirs *DebateGraphNode) []MergeConflict {
+	conflicts := make([]MergeConflict, 0)
+	if conflict := modifyConflict(nodeMagnitudeOrNil(base), ours.Magnitude, theirs.Magnitude); conflict != nil {
+		conflict.Field = "magnitude"
+		conflicts = append(conflicts, *conflict)
+	}
+
+	var baseMetadata NodeMetadata
+	if base != nil {
+		baseMetadata = base.NodeMetadata
+	}
+	metadataFields := []struct {
+		name  string
+		value func(NodeMetadata) string
+	}{
+		{"metadata.side", func(m NodeMetadata) string { return m.Side }},
+		{"metadata.role", func(m NodeMetadata) string { return m.Role }},
+		{"metadata.polarity", func(m NodeMetadata) string { return m.Polarity }},
+		{"metadata.stakeholder", func(m NodeMetadata) string { return m.Stakeholder }},
+	}
+	for _, field := range metadataFields {
+		baseValue, oursValue, theirsValue := field.value(baseMetadata), field.value(ours.NodeMetadata), field.value(theirs.NodeMetadata)
+		if oursValue != baseValue && theirsValue != baseValue && oursValue != theirsValue {
+			conflicts = append(conflicts, MergeConflict{
+				Type: MergeConflictModifyModify, Field: field.name, Ours: oursValue, Theirs: theirsValue,
+				Resolution: "kept the value in ours",
+			})
+		}
+	}
+
+	for i := range conflicts {
+		conflicts[i].ElementKind, conflicts[i].Argument = "node", ours.Argument
+	}
+	return conflicts
+}
+
+// modifyConflict は数値が両方の版で異なる値に変更されていれば競合を返します。
+// ElementKind などの対象を表す項目は呼び出し側で設定します。
+func modifyConflict(base, ours, theirs *float64) *MergeConflict {
+	if equalFloatPtr(ours, base) || equalFloatPtr(theirs, base) || equalFloatPtr(ours, theirs) {
+		return nil
+	}
+	return &MergeConflict{
+		Type: MergeConflictModifyModify, Ours: formatFloatPtr(ours), Theirs: formatFloatPtr(theirs),
+		Resolution: "kept the value in ours",
+	}
+}
+
+// formatFloatPtr は競合の報告のために数値を文字列にします。未設定の値は空文字列です。
+func formatFloatPtr(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'g', -1, 64)
+}
+
+func nodeMetadataOrNil(node *DebateGraphNode) *NodeMetadata {
+	if node == nil {
+		return nil
+	}
+	return &node.NodeMetadata
+}
+
+// mergeEdgeAttributes は3つの版のエッジからIsRebuttal、確率、アノテーションをマージします。
+func mergeEdgeAttributes(base, ours, theirs *DebateGraphEdge) (bool, *float64, [][]Evidence) {
+	isRebuttal := mergeBool(
+		base != nil && base.IsRebuttal, base != nil,
+		ours != nil && ours.IsRebuttal, ours != nil,
+		theirs != nil && theirs.IsRebuttal, theirs != nil,
+	)
+	probability := mergeFloatPtr(edgeProbabilityOrNil(base), edgeProbabilityOrNil(ours), edgeProbabilityOrNil(theirs))
+	return isRebuttal, probability, mergeAnnotationFields(edgeAnnotationFieldsOrNil(base), edgeAnnotationFieldsOrNil(ours), edgeAnnotationFieldsOrNil(theirs))
+}
+
+// mergeFloatPtr は未設定 (nil) を含む数値を三方向マージします。baseから変更した側の値を優先し、両方が変更した場合はoursを優先します。
+func mergeFloatPtr(base, ours, theirs *float64) *float64 {
+	if !equalFloatPtr(ours, base) {
+		return ours
+	}
+	if !equalFloatPtr(theirs, base) {
+		return theirs
+	}
+	return base
+}
+
+func nodeMagnitudeOrNil(node *DebateGraphNode) *float64 {
+	if node == nil {
+		return nil
+	}
+	return node.Magnitude
+}
+
+func edgeProbabilityOrNil(edge *DebateGraphEdge) *float64 {
+	if edge == nil {
+		return nil
+	}
+	return edge.Probability
+}
+
+// mergeBool は真偽値を三方向マージします。baseから変更した側の値を優先し、どちらも変更していなければbaseの値を使います。
+func mergeBool(base, inBase, ours, inOurs, theirs, inTheirs bool) bool {
+	if !inBase {
+		if inOurs {
+			return ours
+		}
+		return theirs
+	}
+	if inOurs && ours != base {
+		return ours
+	}
+	if inTheirs && theirs != base {
+		return theirs
+	}
+	return base
+}
+
+func nodeAnnotationFieldsOrNil(node *DebateGraphNode) []annotationField {
+	if node == nil {
+		return nil
+	}
+	return nodeAnnotationFields(node)
+}
+
+func nodeSourcesOrNil(node *DebateGraphNode) []SourceSpan {
+	if node == nil {
+		return nil
+	}
+	return node.Sources
+}
+
+func edgeSourcesOrNil(edge *DebateGraphEdge) []SourceSpan {
+	if edge == nil {
+		return nil
+	}
+	return edge.Sources
+}
+
+func edgeAnnotationFieldsOrNil(edge *DebateGraphEdge) []annotationField {
+	if edge == nil {
+		return nil
+	}
+	return edgeAnnotationFields(edge)
+}
+
+// mergeAnnotationFields は同じ順序で並んだアノテーションリストをフィールドごとにマージします。
+// 存在しない版はnilで渡し、空のリストとして扱います。
+func mergeAnnotationFields(base, ours, theirs []annotationField) [][]Evidence {
+	fieldCount := len(base)
+	if len(ours) > fieldCount {
+		fieldCount = len(ours)
+	}
+	if len(theirs) > fieldCount {
+		fieldCount = len(theirs)
+	}
+
+	valuesAt := func(fields []annotationField, i int) []Evidence {
+		if fields == nil {
+			return nil
+		}
+		return fields[i].Values
+	}
+
+	merged := make([][]Evidence, fieldCount)
+	for i := 0; i < fieldCount; i++ {
+		merged[i] = mergeEvidenceLists(valuesAt(base, i), valuesAt(ours, i), valuesAt(theirs, i))
+	}
+	return merged
+}
+
+// mergeEvidenceLists はEvidenceのリストを多重集合として三方向マージします。
+// baseの要素はどちらかで削除されていれば削除し、追加された要素はours、theirsの順に末尾へ追加します。
+func mergeEvidenceLists(base, ours, theirs []Evidence) []Evidence {
+	removedInOurs := countEvidence(subtractEvidence(base, ours))
+	removedInTheirs := countEvidence(subtractEvidence(base, theirs))
+	removeCount := make(map[string]int)
+	for key, n := range removedInOurs {
+		removeCount[key] = n
+	}
+	for key, n := range removedInTheirs {
+		if n > removeCount[key] {
+			removeCount[key] = n
+		}
+	}
+
+	merged := make([]Evidence, 0, len(base))
+	for _, e := range base {
+		if removeCount[e.Key()] > 0 {
+			removeCount[e.Key()]--
+			continue
+		}
+		merged = append(merged, e)
+	}
+
+	addedInOurs := subtractEvidence(ours, base)
+	addedInTheirs := subtractEvidence(subtractEvidence(theirs, base), addedInOurs)
+	merged = append(merged, addedInOurs...)
+	merged = append(merged, addedInTheirs...)
+	return merged
+}
+
+func countEvidence(values []Evidence) map[string]int {
+	counts := make(map[string]int, len(values))
+	for _, e := range values {
+		counts[e.Key()]++
+	}
+	return counts
+}
+
+// isNodeModified は、sideのグラフでノードがbaseから変更されたかどうかを判定します。
+// ノード自身の属性に加えて、そのノードに接続するエッジや反論関係が追加された場合も変更とみなします。
+func isNodeModified(base, side *DebateGraph, baseRelations, sideRelations map[string]RebuttalRelation, argument string) bool {
+	baseNode, _ := base.getNode(argument)
+	sideNode, _ := side.getNode(argument)
+	if baseNode.IsRebuttal != sideNode.IsRebuttal || baseNode.NodeMetadata != sideNode.NodeMetadata || !equalFloatPtr(baseNode.Magnitude, sideNode.Magnitude) {
+		return true
+	}
+	if len(diffAnnotationFields(nodeAnnotationFields(baseNode), nodeAnnotationFields(sideNode))) > 0 {
+		return true
+	}
+
+	for _, edge := range side.allEdges() {
+		if edge.Cause.Argument != argument && edge.Effect.Argument != argument {
+			continue
+		}
+		baseEdge, exists := base.getEdge(edge.Cause.Argument, edge.Effect.Argument)
+		if !exists || isEdgeModified(baseEdge, edge) {
+			return true
+		}
+	}
+
+	for key, relation := range sideRelations {
+		if _, exists := baseRelations[key]; exists {
+			continue
+		}
+		for _, referenced := range relation.referencedArguments() {
+			if referenced == argument {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isEdgeModified は、エッジの属性がbaseから変更されたかどうかを判定します。
+func isEdgeModified(base, side *DebateGraphEdge) bool {
+	if base.IsRebuttal != side.IsRebuttal || !equalFloatPtr(base.Probability, side.Probability) {
+		return true
+	}
+	return len(diffAnnotationFields(edgeAnnotationFields(base), edgeAnnotationFields(side))) > 0
+}
+
+// referencedArguments は反論関係が参照している全てのノードのArgumentを返します。
+func (r RebuttalRelation) referencedArguments() []string {
+	arguments := []string{r.RebuttalArgument}
+	for _, a := range []string{r.TargetArgument, r.TargetCauseArgument, r.TargetEffectArgument} {
+		if a != "" {
+			arguments = append(arguments, a)
+		}
+	}
+	if r.TargetRelation != nil {
+		arguments = append(arguments, r.TargetRelation.referencedArguments()...)
+	}
+	return arguments
+}
+
+func relationsByKey(dg *DebateGraph) map[string]RebuttalRelation {
+	relations := make(map[string]RebuttalRelation)
+	for _, r := range dg.rebuttalRelations() {
+		relations[r.Key()] = r
+	}
+	return relations
+}
+
+// unionArguments は3つのグラフのノードのArgumentを、base、ours、theirsの出現順に重複なく返します。
+func unionArguments(graphs ...*DebateGraph) []string {
+	seen := make(map[string]bool)
+	arguments := make([]string, 0)
+	for _, dg := range graphs {
+		for _, node := range dg.Nodes {
+			if !seen[node.Argument] {
+				seen[node.Argument] = true
+				arguments = append(arguments, node.Argument)
+			}
+		}
+	}
+	return arguments
+}
+
+// unionEdgeRefs は3つのグラフのエッジを、base、ours、theirsの順に重複なく返します。
+func unionEdgeRefs(graphs ...*DebateGraph) []EdgeRef {
+	seen := make(map[EdgeRef]bool)
+	refs := make([]EdgeRef, 0)
+	for _, dg := range graphs {
+		for _, edge := range dg.allEdges() {
+			ref := EdgeRef{Cause: edge.Cause.Argument, Effect: edge.Effect.Argument}
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+// unionRelationKeys は3つのグラフの反論関係のKeyを、base、ours、theirsの順に重複なく返します。
+// 反論関係への反論は対象より後に追加する必要があるため、入れ子の浅いものから順に並べます。
+func unionRelationKeys(graphs ...*DebateGraph) []string {
+	seen := make(map[string]bool)
+	relations := make([]RebuttalRelation, 0)
+	for _, dg := range graphs {
+		for _, r := range dg.rebuttalRelations() {
+			if !seen[r.Key()] {
+				seen[r.Key()] = true
+				relations = append(relations, r)
+			}
+		}
+	}
+	sort.SliceStable(relations, func(i, j int) bool {
+		return relations[i].depth() < relations[j].depth()
+	})
+	keys := make([]string, 0, len(relations))
+	for _, r := range relations {
+		keys = append(keys, r.Key())
+	}
+	return keys
+}
+
+// depth は反論関係への反論の入れ子の深さを返します。それ以外の反論関係は0です。
+func (r RebuttalRelation) depth() int {
+	if r.TargetRelation == nil {
+		return 0
+	}
+	return r.TargetRelation.depth() + 1
+}
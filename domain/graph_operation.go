@@ -0,0 +1,489 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// グラフへの操作の種類
+const (
+	OperationAddNode          = "add_node"
+	OperationRemoveNode       = "remove_node"
+	OperationRenameNode       = "rename_node"
+	OperationAddEdge          = "add_edge"
+	OperationRemoveEdge       = "remove_edge"
+	OperationAppendAnnotation = "append_annotation"
+	OperationRemoveAnnotation = "remove_annotation"
+	OperationAddRebuttal      = "add_rebuttal"
+	OperationRemoveRebuttal   = "remove_rebuttal"
+)
+
+// GraphOperation はDebateGraphへの1つの変更です。Op によって使う項目が異なります。
+// JSON Patch (RFC 6902) に似ていますが、パスではなくノードのArgumentや反論関係で対象を指定する、
+// このパッケージ独自の操作の語彙であり、RFC 6902 の add / remove / replace などとは互換性がありません。
+//
+//   - add_node: argument と is_rebuttal、または node (DebateGraphのJSONの nodes の要素と同じ形式)
+//   - remove_node: argument。ノードに接続するエッジと、ノードを参照する反論関係も削除します
+//   - rename_node: argument, new_argument。エッジと反論関係の参照も書き換えます
+//   - add_edge: cause, effect と is_rebuttal、または edge (DebateGraphのJSONの edges の要素と同じ形式)
+//   - remove_edge: cause, effect。エッジへの反論も削除します。ターンが認めているノードからターンへのエッジは、
+//     JSONから復元する際に作り直されるため、先に remove_rebuttal でターンを削除する必要があります
+//   - append_annotation: argument または cause と effect, annotation, evidence, 省略可能な index (挿入位置)
+//   - remove_annotation: argument または cause と effect, annotation, index
+//   - add_rebuttal, remove_rebuttal: relation。remove_rebuttal はその反論関係への反論も削除します
+type GraphOperation struct {
+	Op          string            `json:"op"`
+	Argument    string            `json:"argument,omitempty"`
+	NewArgument string            `json:"new_argument,omitempty"`
+	IsRebuttal  bool              `json:"is_rebuttal,omitempty"`
+	Cause       string            `json:"cause,omitempty"`
+	Effect      string            `json:"effect,omitempty"`
+	Node        json.RawMessage   `json:"node,omitempty"`
+	Edge        json.RawMessage   `json:"edge,omitempty"`
+	Annotation  string            `json:"annotation,omitempty"` // NodeAnnotation* または EdgeAnnotation*
+	Evidence    *Evidence         `json:"evidence,omitempty"`
+	Index       *int              `json:"index,omitempty"`
+	Relation    *RebuttalRelation `json:"relation,omitempty"`
+}
+
+// ApplyGraphOperations はグラフの複製に操作を順に適用し、変更後のグラフと、変更を元に戻す操作を返します。
+// 元に戻す操作を変更後のグラフに順に適用すると、元のグラフと同じ内容に戻ります。
+// いずれかの操作が失敗した場合や、全ての操作を適用した結果の因果エッジに循環がある場合はエラーを返し、
+// 元のグラフは変更されません。途中の操作で一時的に循環ができることは許容します。
+func ApplyGraphOperations(dg *DebateGraph, operations []GraphOperation) (*DebateGraph, []GraphOperation, error) {
+	if dg == nil {
+		return nil, nil, fmt.Errorf("cannot apply operations to nil DebateGraph")
+	}
+	result, err := dg.Clone()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inverse := make([]GraphOperation, 0, len(operations))
+	for i, operation := range operations {
+		operationInverse, err := result.ApplyOperation(operation)
+		if err != nil {
+			return nil, nil, fmt.Errorf("operation %d (%s) failed: %w", i, operation.Op, err)
+		}
+		inverse = append(operationInverse, inverse...)
+	}
+	if _, err := result.TopologicalOrder(); err != nil {
+		return nil, nil, fmt.Errorf("operations would leave the graph invalid: %w", err)
+	}
+	return result, inverse, nil
+}
+
+// ApplyOperation はグラフに1つの操作を適用し、それを元に戻す操作を返します。
+// 操作を検証してから変更するため、エラーの場合はグラフを変更しません。
+// 検証と変更は同じロックの中で行うため、他のゴルーチンからの変更と並行して呼び出せます。
+// 因果エッジの循環は検証しないため、必要に応じて TopologicalOrder で確認するか、ApplyGraphOperations を使用してください。
+func (dg *DebateGraph) ApplyOperation(operation GraphOperation) ([]GraphOperation, error) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	return dg.applyOperation(operation)
+}
+
+// applyOperation はロックを取得せずに操作を適用します。
+func (dg *DebateGraph) applyOperation(operation GraphOperation) ([]GraphOperation, error) {
+	switch operation.Op {
+	case OperationAddNode:
+		return dg.applyAddNode(operation)
+	case OperationRemoveNode:
+		return dg.applyRemoveNode(operation.Argument)
+	case OperationRenameNode:
+		return dg.applyRenameNode(operation.Argument, operation.NewArgument)
+	case OperationAddEdge:
+		return dg.applyAddEdge(operation)
+	case OperationRemoveEdge:
+		return dg.applyRemoveEdge(operation.Cause, operation.Effect)
+	case OperationAppendAnnotation:
+		return dg.applyAppendAnnotation(operation)
+	case OperationRemoveAnnotation:
+		return dg.applyRemoveAnnotation(operation)
+	case OperationAddRebuttal:
+		return dg.applyAddRebuttal(operation.Relation)
+	case OperationRemoveRebuttal:
+		return dg.applyRemoveRebuttal(operation.Relation)
+	}
+	return nil, fmt.Errorf("unknown operation '%s'", operation.Op)
+}
+
+func (dg *DebateGraph) applyAddNode(operation GraphOperation) ([]GraphOperation, error) {
+	node := NewDebateGraphNode(operation.Argument, operation.IsRebuttal)
+	if len(operation.Node) > 0 {
+		var jNode jsonNode
+		if err := json.Unmarshal(operation.Node, &jNode); err != nil {
+			return nil, fmt.Errorf("invalid node: %w", err)
+		}
+		var err error
+		if node, err = newNodeFromJSONNode(&jNode); err != nil {
+			return nil, err
+		}
+	}
+	if node.Argument == "" {
+		return nil, fmt.Errorf("argument of new node must not be empty")
+	}
+	if err := dg.addNode(node); err != nil {
+		return nil, err
+	}
+	return []GraphOperation{{Op: OperationRemoveNode, Argument: node.Argument}}, nil
+}
+
+func (dg *DebateGraph) applyRemoveNode(argument string) ([]GraphOperation, error) {
+	node, exists := dg.getNode(argument)
+	if !exists {
+		return nil, fmt.Errorf("node '%s' not found in debate graph", argument)
+	}
+	nodeJSON, err := node.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	removedRelations := dg.dependentRelations(func(relation RebuttalRelation) bool {
+		for _, referenced := range relation.referencedArguments() {
+			if referenced == argument {
+				return true
+			}
+		}
+		for _, conceded := range relation.TargetCauseArguments {
+			if conceded == argument {
+				return true
+			}
+		}
+		return false
+	})
+	removedEdges := make([]*DebateGraphEdge, 0)
+	for _, edge := range dg.allEdges() {
+		if edge.Cause == node || edge.Effect == node {
+			removedEdges = append(removedEdges, edge)
+		}
+	}
+
+	// 元に戻す操作は、ノード、エッジ、反論関係の順に追加する
+	inverse := []GraphOperation{{Op: OperationAddNode, Node: json.RawMessage(nodeJSON)}}
+	for _, edge := range removedEdges {
+		edgeJSON, err := edge.ToJSON()
+		if err != nil {
+			return nil, err
+		}
+		inverse = append(inverse, GraphOperation{Op: OperationAddEdge, Edge: json.RawMessage(edgeJSON)})
+	}
+	inverse = append(inverse, addRebuttalOperations(removedRelations)...)
+
+	dg.removeRebuttalRelations(removedRelations)
+	for _, edge := range removedEdges {
+		if err := dg.removeEdge(edge.Cause.Argument, edge.Effect.Argument); err != nil {
+			return nil, err
+		}
+	}
+	delete(dg.nodeMap, argument)
+	nodes := make([]*DebateGraphNode, 0, len(dg.Nodes))
+	for _, n := range dg.Nodes {
+		if n != node {
+			nodes = append(nodes, n)
+		}
+	}
+	dg.Nodes = nodes
+	return inverse, nil
+}
+
+func (dg *DebateGraph) applyRenameNode(argument, newArgument string) ([]GraphOperation, error) {
+	if newArgument == "" {
+		return nil, fmt.Errorf("new argument for node '%s' must not be empty", argument)
+	}
+	node, exists := dg.getNode(argument)
+	if !exists {
+		return nil, fmt.Errorf("node '%s' not found in debate graph", argument)
+	}
+	if _, exists := dg.getNode(newArgument); exists {
+		return nil, fmt.Errorf("node with argument '%s' already exists in DebateGraph", newArgument)
+	}
+
+	node.Argument = newArgument
+	delete(dg.nodeMap, argument)
+	dg.nodeMap[newArgument] = node
+	// エッジのキーはArgumentから作られるため、全て作り直す
+	edgeMap := make(map[string]*DebateGraphEdge, len(dg.edgeMap))
+	for _, edge := range dg.edgeMap {
+		edgeMap[generateEdgeKey(edge.Cause.Argument, edge.Effect.Argument)] = edge
+	}
+	dg.edgeMap = edgeMap
+	// 反論関係への反論だけは、対象をArgumentの文字列で保持している
+	for _, rebuttal := range dg.RelationRebuttals {
+		rebuttal.TargetRelation = rebuttal.TargetRelation.renameArgument(argument, newArgument)
+	}
+	return []GraphOperation{{Op: OperationRenameNode, Argument: newArgument, NewArgument: argument}}, nil
+}
+
+// renameArgument は反論関係が参照するArgumentを置き換えた複製を返します。
+func (r RebuttalRelation) renameArgument(argument, newArgument string) RebuttalRelation {
+	rename := func(value string) string {
+		if value == argument {
+			return newArgument
+		}
+		return value
+	}
+	renamed := r
+	renamed.TargetArgument = rename(r.TargetArgument)
+	renamed.TargetCauseArgument = rename(r.TargetCauseArgument)
+	renamed.TargetEffectArgument = rename(r.TargetEffectArgument)
+	renamed.RebuttalArgument = rename(r.RebuttalArgument)
+	if len(r.TargetCauseArguments) > 0 {
+		renamed.TargetCauseArguments = make([]string, 0, len(r.TargetCauseArguments))
+		for _, cause := range r.TargetCauseArguments {
+			renamed.TargetCauseArguments = append(renamed.TargetCauseArguments, rename(cause))
+		}
+		sort.Strings(renamed.TargetCauseArguments)
+	}
+	if r.TargetRelation != nil {
+		target := r.TargetRelation.renameArgument(argument, newArgument)
+		renamed.TargetRelation = &target
+	}
+	return renamed
+}
+
+func (dg *DebateGraph) applyAddEdge(operation GraphOperation) ([]GraphOperation, error) {
+	jEdge := &jsonEdge{Cause: operation.Cause, Effect: operation.Effect, IsRebuttal: operation.IsRebuttal}
+	if len(operation.Edge) > 0 {
+		jEdge = &jsonEdge{}
+		if err := json.Unmarshal(operation.Edge, jEdge); err != nil {
+			return nil, fmt.Errorf("invalid edge: %w", err)
+		}
+	}
+	if jEdge.Cause == jEdge.Effect {
+		return nil, fmt.Errorf("edge '%s -> %s' must connect two different nodes", jEdge.Cause, jEdge.Effect)
+	}
+	if _, exists := dg.getEdge(jEdge.Cause, jEdge.Effect); exists {
+		return nil, fmt.Errorf("edge '%s -> %s' already exists in debate graph", jEdge.Cause, jEdge.Effect)
+	}
+	edge, err := newEdgeFromJSONEdge(dg, jEdge)
+	if err != nil {
+		return nil, err
+	}
+	if err := dg.addEdge(edge); err != nil {
+		return nil, err
+	}
+	return []GraphOperation{{Op: OperationRemoveEdge, Cause: jEdge.Cause, Effect: jEdge.Effect}}, nil
+}
+
+func (dg *DebateGraph) applyRemoveEdge(cause, effect string) ([]GraphOperation, error) {
+	edge, exists := dg.getEdge(cause, effect)
+	if !exists {
+		return nil, fmt.Errorf("edge '%s -> %s' not found in debate graph", cause, effect)
+	}
+	for _, turn := range dg.TurnArgumentRebuttals {
+		if turn.RebuttalNode != edge.Effect {
+			continue
+		}
+		for _, conceded := range turn.TargetCauseNodes {
+			if conceded == edge.Cause {
+				return nil, fmt.Errorf("edge '%s -> %s' links a node conceded by turn argument '%s'; remove the turn argument rebuttal first", cause, effect, effect)
+			}
+		}
+	}
+	edgeJSON, err := edge.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	removedRelations := dg.dependentRelations(func(relation RebuttalRelation) bool {
+		return relation.Kind == RebuttalKindEdge && relation.TargetCauseArgument == cause && relation.TargetEffectArgument == effect
+	})
+	inverse := []GraphOperation{{Op: OperationAddEdge, Edge: json.RawMessage(edgeJSON)}}
+	inverse = append(inverse, addRebuttalOperations(removedRelations)...)
+
+	dg.removeRebuttalRelations(removedRelations)
+	if err := dg.removeEdge(cause, effect); err != nil {
+		return nil, err
+	}
+	return inverse, nil
+}
+
+// annotationList は操作の対象のアノテーションのリストを返します。
+// argument が指定されていればノード、そうでなければ cause と effect のエッジが対象です。
+func (dg *DebateGraph) annotationList(operation GraphOperation) (*[]Evidence, error) {
+	if operation.Argument != "" {
+		node, exists := dg.getNode(operation.Argument)
+		if !exists {
+			return nil, fmt.Errorf("node '%s' not found in debate graph", operation.Argument)
+		}
+		return nodeAnnotationList(node, operation.Annotation)
+	}
+	edge, exists := dg.getEdge(operation.Cause, operation.Effect)
+	if !exists {
+		return nil, fmt.Errorf("edge '%s -> %s' not found in debate graph", operation.Cause, operation.Effect)
+	}
+	return edgeAnnotationList(edge, operation.Annotation)
+}
+
+func (dg *DebateGraph) applyAppendAnnotation(operation GraphOperation) ([]GraphOperation, error) {
+	if operation.Evidence == nil || operation.Evidence.Claim == "" {
+		return nil, fmt.Errorf("evidence with a claim is required")
+	}
+	if err := operation.Evidence.Validate(); err != nil {
+		return nil, err
+	}
+	list, err := dg.annotationList(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	index := len(*list)
+	if operation.Index != nil {
+		if *operation.Index < 0 || *operation.Index > len(*list) {
+			return nil, fmt.Errorf("index %d is out of range for %d %s annotations", *operation.Index, len(*list), operation.Annotation)
+		}
+		index = *operation.Index
+	}
+	updated := make([]Evidence, 0, len(*list)+1)
+	updated = append(updated, (*list)[:index]...)
+	updated = append(updated, *operation.Evidence)
+	*list = append(updated, (*list)[index:]...)
+
+	inverse := operation
+	inverse.Op = OperationRemoveAnnotation
+	inverse.Evidence = nil
+	inverse.Index = &index
+	return []GraphOperation{inverse}, nil
+}
+
+func (dg *DebateGraph) applyRemoveAnnotation(operation GraphOperation) ([]GraphOperation, error) {
+	if operation.Index == nil {
+		return nil, fmt.Errorf("index of annotation to remove is required")
+	}
+	list, err := dg.annotationList(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	index := *operation.Index
+	if index < 0 || index >= len(*list) {
+		return nil, fmt.Errorf("index %d is out of range for %d %s annotations", index, len(*list), operation.Annotation)
+	}
+	evidence := (*list)[index]
+	updated := make([]Evidence, 0, len(*list)-1)
+	updated = append(updated, (*list)[:index]...)
+	*list = append(updated, (*list)[index+1:]...)
+
+	inverse := operation
+	inverse.Op = OperationAppendAnnotation
+	inverse.Evidence = &evidence
+	return []GraphOperation{inverse}, nil
+}
+
+func (dg *DebateGraph) applyAddRebuttal(relation *RebuttalRelation) ([]GraphOperation, error) {
+	if relation == nil {
+		return nil, fmt.Errorf("relation is required")
+	}
+	if dg.hasRebuttalRelation(*relation) {
+		return nil, fmt.Errorf("rebuttal relation '%s' already exists in debate graph", relation.Describe())
+	}
+	// ターンは認めているノードからのエッジを作成するため、新しく作られたエッジも元に戻す
+	existingEdges := make(map[string]bool)
+	for _, edge := range dg.allEdges() {
+		existingEdges[generateEdgeKey(edge.Cause.Argument, edge.Effect.Argument)] = true
+	}
+	if err := dg.addRebuttalRelation(*relation); err != nil {
+		return nil, err
+	}
+
+	inverse := []GraphOperation{{Op: OperationRemoveRebuttal, Relation: relation}}
+	for _, edge := range dg.allEdges() {
+		if !existingEdges[generateEdgeKey(edge.Cause.Argument, edge.Effect.Argument)] {
+			inverse = append(inverse, GraphOperation{Op: OperationRemoveEdge, Cause: edge.Cause.Argument, Effect: edge.Effect.Argument})
+		}
+	}
+	return inverse, nil
+}
+
+func (dg *DebateGraph) applyRemoveRebuttal(relation *RebuttalRelation) ([]GraphOperation, error) {
+	if relation == nil {
+		return nil, fmt.Errorf("relation is required")
+	}
+	key := relation.Key()
+	removedRelations := dg.dependentRelations(func(existing RebuttalRelation) bool {
+		return existing.Key() == key
+	})
+	if len(removedRelations) == 0 {
+		return nil, fmt.Errorf("rebuttal relation '%s' not found in debate graph", relation.Describe())
+	}
+	dg.removeRebuttalRelations(removedRelations)
+	return addRebuttalOperations(removedRelations), nil
+}
+
+// dependentRelations は条件に一致する反論関係と、それらへの反論 (入れ子を含む) を、入れ子の浅い順に返します。
+func (dg *DebateGraph) dependentRelations(match func(RebuttalRelation) bool) []RebuttalRelation {
+	relations := dg.rebuttalRelations()
+	removed := make(map[string]bool)
+	result := make([]RebuttalRelation, 0)
+	for changed := true; changed; {
+		changed = false
+		for _, relation := range relations {
+			key := relation.Key()
+			if removed[key] {
+				continue
+			}
+			if match(relation) || (relation.TargetRelation != nil && removed[relation.TargetRelation.Key()]) {
+				removed[key] = true
+				result = append(result, relation)
+				changed = true
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].depth() < result[j].depth() })
+	return result
+}
+
+// removeRebuttalRelations は指定した反論関係をグラフから取り除きます。
+func (dg *DebateGraph) removeRebuttalRelations(relations []RebuttalRelation) {
+	removed := make(map[string]bool, len(relations))
+	for _, relation := range relations {
+		removed[relation.Key()] = true
+	}
+
+	nodeRebuttals := make([]*DebateGraphNodeRebuttal, 0, len(dg.NodeRebuttals))
+	for _, r := range dg.NodeRebuttals {
+		if !removed[r.Relation().Key()] {
+			nodeRebuttals = append(nodeRebuttals, r)
+		}
+	}
+	dg.NodeRebuttals = nodeRebuttals
+	edgeRebuttals := make([]*DebateGraphEdgeRebuttal, 0, len(dg.EdgeRebuttals))
+	for _, r := range dg.EdgeRebuttals {
+		if !removed[r.Relation().Key()] {
+			edgeRebuttals = append(edgeRebuttals, r)
+		}
+	}
+	dg.EdgeRebuttals = edgeRebuttals
+	counterArgumentRebuttals := make([]*CounterArgumentRebuttal, 0, len(dg.CounterArgumentRebuttals))
+	for _, r := range dg.CounterArgumentRebuttals {
+		if !removed[r.Relation().Key()] {
+			counterArgumentRebuttals = append(counterArgumentRebuttals, r)
+		}
+	}
+	dg.CounterArgumentRebuttals = counterArgumentRebuttals
+	turnArgumentRebuttals := make([]*TurnArgumentRebuttal, 0, len(dg.TurnArgumentRebuttals))
+	for _, r := range dg.TurnArgumentRebuttals {
+		if !removed[r.Relation().Key()] {
+			turnArgumentRebuttals = append(turnArgumentRebuttals, r)
+		}
+	}
+	dg.TurnArgumentRebuttals = turnArgumentRebuttals
+	relationRebuttals := make([]*RelationRebuttal, 0, len(dg.RelationRebuttals))
+	for _, r := range dg.RelationRebuttals {
+		if !removed[r.Relation().Key()] {
+			relationRebuttals = append(relationRebuttals, r)
+		}
+	}
+	dg.RelationRebuttals = relationRebuttals
+}
+
+func addRebuttalOperations(relations []RebuttalRelation) []GraphOperation {
+	operations := make([]GraphOperation, 0, len(relations))
+	for _, relation := range relations {
+		relation := relation
+		operations = append(operations, GraphOperation{Op: OperationAddRebuttal, Relation: &relation})
+	}
+	return operations
+}
@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOperationTestGraph(t *testing.T) *DebateGraph {
+	t.Helper()
+	dg := NewDebateGraph()
+	for _, node := range []*DebateGraphNode{
+		NewDebateGraphNode("法人税を減税する", false),
+		NewDebateGraphNode("企業の投資が増える", false),
+		NewDebateGraphNode("減税分は内部留保に回る", true),
+		NewDebateGraphNode("過去の減税では投資が増えた", true),
+	} {
+		require.NoError(t, dg.AddNode(node))
+	}
+	cause, _ := dg.GetNode("法人税を減税する")
+	effect, _ := dg.GetNode("企業の投資が増える")
+	require.NoError(t, dg.AddEdge(NewDebateGraphEdge(cause, effect, false)))
+	require.NoError(t, dg.AddNodeAnnotation("企業の投資が増える", NodeAnnotationImportance, NewEvidence("経済成長につながる")))
+	edgeRebuttal := RebuttalRelation{
+		Kind: RebuttalKindEdge, TargetCauseArgument: "法人税を減税する", TargetEffectArgument: "企業の投資が増える",
+		RebuttalType: "certainty", RebuttalArgument: "減税分は内部留保に回る",
+	}
+	require.NoError(t, dg.AddRebuttalRelation(edgeRebuttal))
+	require.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{
+		Kind: RebuttalKindRelation, TargetRelation: &edgeRebuttal, RebuttalArgument: "過去の減税では投資が増えた",
+	}))
+	return dg
+}
+
+func TestApplyGraphOperationsWithUndo(t *testing.T) {
+	dg := newOperationTestGraph(t)
+	original, err := dg.ToJSON()
+	require.NoError(t, err)
+
+	index := 0
+	operations := []GraphOperation{
+		{Op: OperationRenameNode, Argument: "法人税を減税する", NewArgument: "法人税率を引き下げる"},
+		{Op: OperationAddNode, Argument: "雇用が増える"},
+		{Op: OperationAddEdge, Cause: "企業の投資が増える", Effect: "雇用が増える"},
+		{Op: OperationAppendAnnotation, Argument: "企業の投資が増える", Annotation: NodeAnnotationImportance, Evidence: &Evidence{Claim: "生産性が上がる"}, Index: &index},
+		{Op: OperationAppendAnnotation, Cause: "企業の投資が増える", Effect: "雇用が増える", Annotation: EdgeAnnotationCertainty, Evidence: &Evidence{Claim: "設備の稼働に人手が要る"}},
+		{Op: OperationRemoveNode, Argument: "減税分は内部留保に回る"},
+	}
+	updated, inverse, err := ApplyGraphOperations(dg, operations)
+	require.NoError(t, err)
+
+	_, exists := updated.GetEdge("法人税率を引き下げる", "企業の投資が増える")
+	assert.True(t, exists)
+	_, exists = updated.GetNode("減税分は内部留保に回る")
+	assert.False(t, exists)
+	// 削除したノードへの反論関係と、それへの反論も削除される
+	assert.Empty(t, updated.RebuttalRelations())
+	node, _ := updated.GetNode("企業の投資が増える")
+	require.Len(t, node.Importance, 2)
+	assert.Equal(t, "生産性が上がる", node.Importance[0].Claim)
+
+	// 元のグラフは変更されない
+	unchanged, err := dg.ToJSON()
+	require.NoError(t, err)
+	assert.Equal(t, original, unchanged)
+
+	restored, _, err := ApplyGraphOperations(updated, inverse)
+	require.NoError(t, err)
+	restoredJSON, err := restored.ToJSON()
+	require.NoError(t, err)
+	assert.Equal(t, original, restoredJSON)
+}
+
+func TestApplyGraphOperationsRemoveRebuttalAndEdge(t *testing.T) {
+	dg := newOperationTestGraph(t)
+	original, err := dg.ToJSON()
+	require.NoError(t, err)
+
+	relations := dg.RebuttalRelations()
+	require.Len(t, relations, 2)
+	updated, inverse, err := ApplyGraphOperations(dg, []GraphOperation{
+		{Op: OperationRemoveEdge, Cause: "法人税を減税する", Effect: "企業の投資が増える"},
+		{Op: OperationAddRebuttal, Relation: &RebuttalRelation{
+			Kind: RebuttalKindTurnArgument, TargetCauseArguments: []string{"企業の投資が増える"}, RebuttalArgument: "減税分は内部留保に回る",
+		}},
+	})
+	require.NoError(t, err)
+	assert.Len(t, updated.RebuttalRelations(), 1)
+
+	restored, _, err := ApplyGraphOperations(updated, inverse)
+	require.NoError(t, err)
+	restoredJSON, err := restored.ToJSON()
+	require.NoError(t, err)
+	assert.Equal(t, original, restoredJSON)
+}
+
+func TestApplyGraphOperationsRemoveTurnEdge(t *testing.T) {
+	dg := newOperationTestGraph(t)
+	turn := RebuttalRelation{
+		Kind: RebuttalKindTurnArgument, TargetCauseArguments: []string{"企業の投資が増える"}, RebuttalArgument: "減税分は内部留保に回る",
+	}
+	require.NoError(t, dg.AddRebuttalRelation(turn))
+	original, err := dg.ToJSON()
+	require.NoError(t, err)
+
+	// ターンが認めているノードからのエッジだけを削除しても、JSONから復元すると作り直されてしまう
+	removeEdge := GraphOperation{Op: OperationRemoveEdge, Cause: "企業の投資が増える", Effect: "減税分は内部留保に回る"}
+	_, _, err = ApplyGraphOperations(dg, []GraphOperation{removeEdge})
+	assert.Error(t, err)
+
+	updated, inverse, err := ApplyGraphOperations(dg, []GraphOperation{
+		{Op: OperationRemoveRebuttal, Relation: &turn},
+		removeEdge,
+	})
+	require.NoError(t, err)
+	updatedJSON, err := updated.ToCompactJSON()
+	require.NoError(t, err)
+	roundTripped, err := NewDebateGraphFromJSON(updatedJSON)
+	require.NoError(t, err)
+	_, exists := roundTripped.GetEdge("企業の投資が増える", "減税分は内部留保に回る")
+	assert.False(t, exists)
+	assert.Empty(t, roundTripped.TurnArgumentRebuttals)
+
+	restored, _, err := ApplyGraphOperations(updated, inverse)
+	require.NoError(t, err)
+	restoredJSON, err := restored.ToJSON()
+	require.NoError(t, err)
+	assert.Equal(t, original, restoredJSON)
+}
+
+func TestApplyGraphOperationsValidation(t *testing.T) {
+	dg := newOperationTestGraph(t)
+	outOfRange := 1
+	tests := []struct {
+		name      string
+		operation GraphOperation
+	}{
+		{"unknown operation", GraphOperation{Op: "replace_node"}},
+		{"duplicate node", GraphOperation{Op: OperationAddNode, Argument: "法人税を減税する"}},
+		{"empty node", GraphOperation{Op: OperationAddNode}},
+		{"missing node", GraphOperation{Op: OperationRemoveNode, Argument: "存在しない"}},
+		{"rename to existing", GraphOperation{Op: OperationRenameNode, Argument: "法人税を減税する", NewArgument: "企業の投資が増える"}},
+		{"duplicate edge", GraphOperation{Op: OperationAddEdge, Cause: "法人税を減税する", Effect: "企業の投資が増える"}},
+		{"self loop", GraphOperation{Op: OperationAddEdge, Cause: "法人税を減税する", Effect: "法人税を減税する"}},
+		{"unknown annotation", GraphOperation{Op: OperationAppendAnnotation, Argument: "法人税を減税する", Annotation: "relevance", Evidence: &Evidence{Claim: "x"}}},
+		{"annotation index", GraphOperation{Op: OperationRemoveAnnotation, Argument: "企業の投資が増える", Annotation: NodeAnnotationImportance, Index: &outOfRange}},
+		{"missing relation", GraphOperation{Op: OperationRemoveRebuttal, Relation: &RebuttalRelation{Kind: RebuttalKindCounterArgument, TargetArgument: "法人税を減税する", RebuttalArgument: "減税分は内部留保に回る"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ApplyGraphOperations(dg, []GraphOperation{tt.operation})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestApplyGraphOperationsRejectsCycles(t *testing.T) {
+	dg := newOperationTestGraph(t)
+	original, err := dg.ToJSON()
+	require.NoError(t, err)
+
+	_, _, err = ApplyGraphOperations(dg, []GraphOperation{
+		{Op: OperationAddNode, Argument: "雇用が増える"},
+		{Op: OperationAddEdge, Cause: "企業の投資が増える", Effect: "雇用が増える"},
+		{Op: OperationAddEdge, Cause: "雇用が増える", Effect: "法人税を減税する"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+	unchanged, err := dg.ToJSON()
+	require.NoError(t, err)
+	assert.Equal(t, original, unchanged)
+
+	// 途中で循環ができても、最後に解消されていればよい
+	updated, _, err := ApplyGraphOperations(dg, []GraphOperation{
+		{Op: OperationAddEdge, Cause: "企業の投資が増える", Effect: "法人税を減税する"},
+		{Op: OperationRemoveEdge, Cause: "法人税を減税する", Effect: "企業の投資が増える"},
+	})
+	require.NoError(t, err)
+	_, exists := updated.GetEdge("企業の投資が増える", "法人税を減税する")
+	assert.True(t, exists)
+}
@@ -0,0 +1,298 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ImpactSupport は影響ノードがどれだけの議論に支えられているかを表します。
+// Depth は前提から影響までの最長の因果の連鎖に含まれるエッジの数で、SupportingNodes は影響の祖先となるノードの数です。
+type ImpactSupport struct {
+	Argument        string `json:"argument"`
+	Depth           int    `json:"depth"`
+	SupportingNodes int    `json:"supporting_nodes"`
+}
+
+// GraphReport はDebateGraphの規模と、根拠や応答が不足している箇所をまとめた報告です。
+type GraphReport struct {
+	NodeCount              int                `json:"node_count"`
+	EdgeCount              int                `json:"edge_count"`
+	NodesWithoutImportance []string           `json:"nodes_without_importance"`
+	NodesWithoutUniqueness []string           `json:"nodes_without_uniqueness"`
+	EdgesWithoutCertainty  []EdgeRef          `json:"edges_without_certainty"`
+	UnansweredRebuttals    []RebuttalRelation `json:"unanswered_rebuttals"`
+	LongestChain           []string           `json:"longest_chain"`
+	AverageBranchingFactor float64            `json:"average_branching_factor"`
+	MaxBranchingFactor     int                `json:"max_branching_factor"`
+	RebuttalNodeRatio      float64            `json:"rebuttal_node_ratio"`
+	ImpactSupports         []ImpactSupport    `json:"impact_supports"`
+}
+
+// BuildGraphReport はDebateGraphの網羅性と構造の指標を計算します。
+//
+// アノテーションの不足は元の主張 (IsRebuttal = false) のノードとエッジだけを対象にします。
+// 応答のない反論は、相手側 (opponent) の反論関係のうち、反論ノードへの反論・ターン、反論ノードへ至るエッジへの反論、
+// 反論関係への反論のいずれも受けていないものです。反論関係の立場は relationSides で決めるため、
+// 相手の反論に対する自分たちの再反論は、応答がなくても含みません。
+// 分岐数は結果を1つ以上持つノードの、結果の数の平均と最大です。
+func BuildGraphReport(dg *DebateGraph) (*GraphReport, error) {
+	if dg == nil {
+		return nil, fmt.Errorf("cannot build report for nil DebateGraph")
+	}
+	return readLocked(func() (*GraphReport, error) { return buildGraphReport(dg) }, dg)
+}
+
+// buildGraphReport はロックを取得せずに BuildGraphReport の報告を作成します。
+func buildGraphReport(dg *DebateGraph) (*GraphReport, error) {
+	order, err := dg.topologicalOrder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to order debate graph for report: %w", err)
+	}
+	edges := dg.allEdges()
+
+	report := &GraphReport{
+		NodeCount:              len(order),
+		EdgeCount:              len(edges),
+		NodesWithoutImportance: make([]string, 0),
+		NodesWithoutUniqueness: make([]string, 0),
+		EdgesWithoutCertainty:  make([]EdgeRef, 0),
+		UnansweredRebuttals:    make([]RebuttalRelation, 0),
+		LongestChain:           make([]string, 0),
+		ImpactSupports:         make([]ImpactSupport, 0),
+	}
+
+	rebuttalNodes := 0
+	for _, node := range order {
+		if node.IsRebuttal {
+			rebuttalNodes++
+			continue
+		}
+		if len(node.Importance) == 0 {
+			report.NodesWithoutImportance = append(report.NodesWithoutImportance, node.Argument)
+		}
+		if len(node.Uniqueness) == 0 {
+			report.NodesWithoutUniqueness = append(report.NodesWithoutUniqueness, node.Argument)
+		}
+	}
+	sort.Strings(report.NodesWithoutImportance)
+	sort.Strings(report.NodesWithoutUniqueness)
+	if len(order) > 0 {
+		report.RebuttalNodeRatio = float64(rebuttalNodes) / float64(len(order))
+	}
+
+	outDegree := make(map[*DebateGraphNode]int)
+	for _, edge := range edges {
+		outDegree[edge.Cause]++
+		if !edge.IsRebuttal && len(edge.Certainty) == 0 {
+			report.EdgesWithoutCertainty = append(report.EdgesWithoutCertainty, EdgeRef{Cause: edge.Cause.Argument, Effect: edge.Effect.Argument})
+		}
+	}
+	if len(outDegree) > 0 {
+		for _, degree := range outDegree {
+			report.MaxBranchingFactor = max(report.MaxBranchingFactor, degree)
+		}
+		report.AverageBranchingFactor = float64(len(edges)) / float64(len(outDegree))
+	}
+
+	report.UnansweredRebuttals = unansweredRebuttals(dg)
+
+	// 各ノードで終わる最長の連鎖の長さと、その直前のノードを求める
+	depth := make(map[*DebateGraphNode]int, len(order))
+	previous := make(map[*DebateGraphNode]*DebateGraphNode, len(order))
+	var deepest *DebateGraphNode
+	for _, node := range order {
+		for _, edge := range node.Causes {
+			candidate := depth[edge.Cause] + 1
+			current := previous[node]
+			if candidate > depth[node] || (candidate == depth[node] && current != nil && edge.Cause.Argument < current.Argument) {
+				depth[node] = candidate
+				previous[node] = edge.Cause
+			}
+		}
+		if deepest == nil || depth[node] > depth[deepest] {
+			deepest = node
+		}
+	}
+	for node := deepest; node != nil; node = previous[node] {
+		report.LongestChain = append([]string{node.Argument}, report.LongestChain...)
+	}
+
+	for _, impact := range dg.impactNodes() {
+		report.ImpactSupports = append(report.ImpactSupports, ImpactSupport{
+			Argument:        impact.Argument,
+			Depth:           depth[impact],
+			SupportingNodes: len(ancestorNodes(impact)),
+		})
+	}
+
+	return report, nil
+}
+
+// unansweredRebuttals は、相手側 (opponent) の反論関係のうち、どの反論も受けていないものをKey順に返します。
+func unansweredRebuttals(dg *DebateGraph) []RebuttalRelation {
+	relations := dg.rebuttalRelations()
+	sides := relationSides(dg, relations)
+	answeredArguments := make(map[string]bool)
+	answeredRelations := make(map[string]bool)
+	for _, relation := range relations {
+		for _, argument := range relationTargetArguments(dg, relation) {
+			answeredArguments[argument] = true
+		}
+		if relation.Kind == RebuttalKindEdge {
+			answeredArguments[relation.TargetEffectArgument] = true
+		}
+		if relation.TargetRelation != nil {
+			answeredRelations[relation.TargetRelation.Key()] = true
+		}
+	}
+
+	unanswered := make([]RebuttalRelation, 0)
+	for _, relation := range relations {
+		if sides[relation.Key()] != AdjudicationSideOpponent {
+			continue
+		}
+		if !answeredArguments[relation.RebuttalArgument] && !answeredRelations[relation.Key()] {
+			unanswered = append(unanswered, relation)
+		}
+	}
+	return unanswered
+}
+
+// relationSides は各反論関係を行った立場 (AdjudicationSide*) をKeyごとに返します。
+// 元の主張 (IsRebuttal = false) は proponent のものとし、反論関係は対象の立場の相手側のものとします。
+// そのため元の主張への反論は opponent、それへの再反論は proponent のように、反論の連鎖をたどるごとに立場が入れ替わります。
+// 反論ノードの立場はそのノードが行う反論関係の立場で、反論関係を持たない反論ノードは、結果となるノードの立場を引き継ぎます。
+// 対象の立場が決まらない反論関係は含みません。
+func relationSides(dg *DebateGraph, relations []RebuttalRelation) map[string]string {
+	nodeSides := make(map[string]string)
+	for _, node := range dg.Nodes {
+		if !node.IsRebuttal {
+			nodeSides[node.Argument] = AdjudicationSideProponent
+		}
+	}
+	edges := dg.allEdges()
+
+	sides := make(map[string]string, len(relations))
+	for changed := true; changed; {
+		changed = false
+		for _, relation := range relations {
+			key := relation.Key()
+			if _, known := sides[key]; known {
+				continue
+			}
+			targetSide := relationTargetSide(dg, relation, nodeSides, sides)
+			if targetSide == "" {
+				continue
+			}
+			sides[key] = otherSide(targetSide)
+			nodeSides[relation.RebuttalArgument] = sides[key]
+			changed = true
+		}
+		// 反論ノードを支える原因のノードは、支えている反論ノードと同じ立場になる
+		for _, edge := range edges {
+			effectSide, known := nodeSides[edge.Effect.Argument]
+			if _, causeKnown := nodeSides[edge.Cause.Argument]; known && !causeKnown {
+				nodeSides[edge.Cause.Argument] = effectSide
+				changed = true
+			}
+		}
+	}
+	return sides
+}
+
+// relationTargetSide は反論関係の対象の立場を返します。まだ決まっていない場合は空文字列を返します。
+// エッジは結果のノードの立場、ターンは認めているノードの立場を対象の立場とします。
+func relationTargetSide(dg *DebateGraph, relation RebuttalRelation, nodeSides, relationSides map[string]string) string {
+	switch relation.Kind {
+	case RebuttalKindNode, RebuttalKindCounterArgument:
+		return nodeSides[relation.TargetArgument]
+	case RebuttalKindEdge:
+		return nodeSides[relation.TargetEffectArgument]
+	case RebuttalKindTurnArgument:
+		for _, argument := range relationTargetArguments(dg, relation) {
+			if side, known := nodeSides[argument]; known {
+				return side
+			}
+		}
+	case RebuttalKindRelation:
+		if relation.TargetRelation != nil {
+			return relationSides[relation.TargetRelation.Key()]
+		}
+	}
+	return ""
+}
+
+// ancestorNodes はCausesをたどって到達できる、nodeを除く全てのノードを返します。
+func ancestorNodes(node *DebateGraphNode) map[*DebateGraphNode]bool {
+	visited := make(map[*DebateGraphNode]bool)
+	stack := []*DebateGraphNode{node}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, edge := range current.Causes {
+			if !visited[edge.Cause] {
+				visited[edge.Cause] = true
+				stack = append(stack, edge.Cause)
+			}
+		}
+	}
+	return visited
+}
+
+// ToMarkdown は報告をMarkdownの文書に変換します。
+func (r *GraphReport) ToMarkdown() string {
+	var builder strings.Builder
+
+	builder.WriteString("# グラフの報告\n\n")
+	writeMarkdownRow(&builder, []string{"指標", "値"})
+	writeMarkdownRow(&builder, []string{"---", "---"})
+	writeMarkdownRow(&builder, []string{"ノード数", fmt.Sprintf("%d", r.NodeCount)})
+	writeMarkdownRow(&builder, []string{"エッジ数", fmt.Sprintf("%d", r.EdgeCount)})
+	writeMarkdownRow(&builder, []string{"反論ノードの割合", fmt.Sprintf("%.2f", r.RebuttalNodeRatio)})
+	writeMarkdownRow(&builder, []string{"平均分岐数", fmt.Sprintf("%.2f", r.AverageBranchingFactor)})
+	writeMarkdownRow(&builder, []string{"最大分岐数", fmt.Sprintf("%d", r.MaxBranchingFactor)})
+	writeMarkdownRow(&builder, []string{"最長の因果の連鎖", fmt.Sprintf("%d", max(len(r.LongestChain)-1, 0))})
+
+	builder.WriteString("\n## 最長の因果の連鎖\n\n")
+	if len(r.LongestChain) == 0 {
+		builder.WriteString("なし\n")
+	} else {
+		builder.WriteString(strings.Join(r.LongestChain, " → ") + "\n")
+	}
+
+	builder.WriteString("\n## 影響の支持\n\n")
+	writeMarkdownRow(&builder, []string{"影響", "深さ", "支持するノード数"})
+	writeMarkdownRow(&builder, []string{"---", "---", "---"})
+	for _, support := range r.ImpactSupports {
+		writeMarkdownRow(&builder, []string{support.Argument, fmt.Sprintf("%d", support.Depth), fmt.Sprintf("%d", support.SupportingNodes)})
+	}
+
+	writeMarkdownList(&builder, "重要性の根拠がないノード", r.NodesWithoutImportance)
+	writeMarkdownList(&builder, "固有性の根拠がないノード", r.NodesWithoutUniqueness)
+
+	edges := make([]string, 0, len(r.EdgesWithoutCertainty))
+	for _, edge := range r.EdgesWithoutCertainty {
+		edges = append(edges, edge.Cause+" → "+edge.Effect)
+	}
+	writeMarkdownList(&builder, "確実性の根拠がないエッジ", edges)
+
+	rebuttals := make([]string, 0, len(r.UnansweredRebuttals))
+	for _, relation := range r.UnansweredRebuttals {
+		rebuttals = append(rebuttals, relation.Describe())
+	}
+	writeMarkdownList(&builder, "応答のない反論", rebuttals)
+
+	return builder.String()
+}
+
+func writeMarkdownList(builder *strings.Builder, title string, items []string) {
+	builder.WriteString("\n## " + title + "\n\n")
+	if len(items) == 0 {
+		builder.WriteString("なし\n")
+		return
+	}
+	for _, item := range items {
+		builder.WriteString("- " + escapeMarkdownCell(item) + "\n")
+	}
+}
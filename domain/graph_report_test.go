@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildGraphReport(t *testing.T) {
+	dg := NewDebateGraph()
+	for _, argument := range []string{"法人税を減税する", "企業の投資が増える", "雇用が増える", "税収が減る"} {
+		require.NoError(t, dg.AddNode(NewDebateGraphNode(argument, false)))
+	}
+	for _, argument := range []string{"内部留保に回る", "投資は景気で決まる", "景気は減税では変わらない"} {
+		require.NoError(t, dg.AddNode(NewDebateGraphNode(argument, true)))
+	}
+	addEdge := func(cause, effect string) {
+		causeNode, _ := dg.GetNode(cause)
+		effectNode, _ := dg.GetNode(effect)
+		require.NoError(t, dg.AddEdge(NewDebateGraphEdge(causeNode, effectNode, false)))
+	}
+	addEdge("法人税を減税する", "企業の投資が増える")
+	addEdge("企業の投資が増える", "雇用が増える")
+	addEdge("法人税を減税する", "税収が減る")
+	require.NoError(t, dg.AddEdgeAnnotation("法人税を減税する", "企業の投資が増える", EdgeAnnotationCertainty, NewEvidence("過去の減税で投資が増えた")))
+	require.NoError(t, dg.AddNodeAnnotation("雇用が増える", NodeAnnotationImportance, NewEvidence("失業は深刻である")))
+
+	// 元の主張 ← 相手の反論 ← 自分たちの再反論 ← 相手の再々反論 の連鎖では、
+	// 応答を受けていない相手の再々反論だけが応答のない反論になる
+	require.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{Kind: RebuttalKindCounterArgument, TargetArgument: "企業の投資が増える", RebuttalArgument: "内部留保に回る"}))
+	require.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{Kind: RebuttalKindCounterArgument, TargetArgument: "内部留保に回る", RebuttalArgument: "投資は景気で決まる"}))
+	require.NoError(t, dg.AddRebuttalRelation(RebuttalRelation{Kind: RebuttalKindCounterArgument, TargetArgument: "投資は景気で決まる", RebuttalArgument: "景気は減税では変わらない"}))
+
+	report, err := BuildGraphReport(dg)
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, report.NodeCount)
+	assert.Equal(t, 3, report.EdgeCount)
+	assert.InDelta(t, 3.0/7.0, report.RebuttalNodeRatio, 1e-9)
+	assert.InDelta(t, 1.5, report.AverageBranchingFactor, 1e-9)
+	assert.Equal(t, 2, report.MaxBranchingFactor)
+	assert.Equal(t, []string{"法人税を減税する", "企業の投資が増える", "雇用が増える"}, report.LongestChain)
+	assert.NotContains(t, report.NodesWithoutImportance, "雇用が増える")
+	assert.Len(t, report.NodesWithoutUniqueness, 4)
+	assert.Equal(t, []EdgeRef{{Cause: "企業の投資が増える", Effect: "雇用が増える"}, {Cause: "法人税を減税する", Effect: "税収が減る"}}, report.EdgesWithoutCertainty)
+
+	require.Len(t, report.UnansweredRebuttals, 1)
+	assert.Equal(t, "景気は減税では変わらない", report.UnansweredRebuttals[0].RebuttalArgument)
+
+	// 自分たちの再反論で終わる連鎖には、応答のない反論はない
+	defended, _, err := ApplyGraphOperations(dg, []GraphOperation{{Op: OperationRemoveNode, Argument: "景気は減税では変わらない"}})
+	require.NoError(t, err)
+	defendedReport, err := BuildGraphReport(defended)
+	require.NoError(t, err)
+	assert.Empty(t, defendedReport.UnansweredRebuttals)
+
+	assert.Equal(t, []ImpactSupport{
+		{Argument: "税収が減る", Depth: 1, SupportingNodes: 1},
+		{Argument: "雇用が増える", Depth: 2, SupportingNodes: 2},
+	}, report.ImpactSupports)
+
+	markdown := report.ToMarkdown()
+	assert.Contains(t, markdown, "法人税を減税する → 企業の投資が増える → 雇用が増える")
+	assert.Contains(t, markdown, "## 応答のない反論")
+}
@@ -9,6 +9,7 @@ import (
 type LogicGraphNode struct {
 	Argument string
 	Causes   []*LogicGraphNode
+	NodeMetadata
 }
 
 type LogicGraph struct {
@@ -70,16 +71,21 @@ func ListAllCausalRelationships(graph *LogicGraph) []string {
 	return relationships
 }
 
+// logicGraphJSON は LogicGraph のJSON形式です。
+// nodes: ["Argument1", "Argument2", ...]
+// edges: [["CauseArg1", "EffectArg1"], ["CauseArg2", "EffectArg2"], ...]
+// metadata: {"Argument1": {"side": "status_quo", "role": "impact", ...}, ...} (メタデータを持つノードのみ)
+type logicGraphJSON struct {
+	Nodes    []string                `json:"nodes"`
+	Edges    [][]string              `json:"edges"`
+	Metadata map[string]NodeMetadata `json:"metadata,omitempty"`
+}
+
 // ToJSON は LogicGraph を指定されたカスタム形式のJSON文字列に変換します。
 // nodes: ["Argument1", "Argument2", ...]
 // edges: [["CauseArg1", "EffectArg1"], ["CauseArg2", "EffectArg2"], ...]
 func (lg *LogicGraph) ToJSON() (string, error) {
-	type CustomJSONOutput struct {
-		Nodes []string   `json:"nodes"`
-		Edges [][]string `json:"edges"`
-	}
-
-	outputData := CustomJSONOutput{
+	outputData := logicGraphJSON{
 		Nodes: make([]string, 0),
 		Edges: make([][]string, 0),
 	}
@@ -94,6 +100,12 @@ func (lg *LogicGraph) ToJSON() (string, error) {
 
 	for _, node := range lg.Nodes {
 		outputData.Nodes = append(outputData.Nodes, node.Argument)
+		if !node.NodeMetadata.IsZero() {
+			if outputData.Metadata == nil {
+				outputData.Metadata = make(map[string]NodeMetadata)
+			}
+			outputData.Metadata[node.Argument] = node.NodeMetadata
+		}
 	}
 	sort.Strings(outputData.Nodes)
 
@@ -117,3 +129,59 @@ func (lg *LogicGraph) ToJSON() (string, error) {
 	}
 	return string(jsonData), nil
 }
+
+// NewLogicGraphFromJSON は ToJSON が出力する形式のJSON文字列からLogicGraphを復元します。
+// edgesに未定義のノードが含まれる場合や、ノードが重複している場合はエラーを返します。
+func NewLogicGraphFromJSON(jsonData string) (*LogicGraph, error) {
+	var input logicGraphJSON
+	if err := json.Unmarshal([]byte(jsonData), &input); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON to LogicGraph: %w", err)
+	}
+
+	lg := NewLogicGraph(nil)
+	for _, argument := range input.Nodes {
+		if _, exists := lg.NodeMap[argument]; exists {
+			return nil, fmt.Errorf("duplicate node '%s' in LogicGraph JSON", argument)
+		}
+		lg.AddNode(NewLogicGraphNode(argument))
+	}
+
+	for argument, metadata := range input.Metadata {
+		node, exists := lg.NodeMap[argument]
+		if !exists {
+			return nil, fmt.Errorf("metadata for unknown node '%s' in LogicGraph JSON", argument)
+		}
+		if err := metadata.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid metadata for node '%s': %w", argument, err)
+		}
+		node.NodeMetadata = metadata
+	}
+
+	for _, edge := range input.Edges {
+		if len(edge) != 2 {
+			return nil, fmt.Errorf("edge must be a [cause, effect] pair, got %d elements", len(edge))
+		}
+		causeNode, exists := lg.NodeMap[edge[0]]
+		if !exists {
+			return nil, fmt.Errorf("cause node '%s' for edge not found in graph", edge[0])
+		}
+		effectNode, exists := lg.NodeMap[edge[1]]
+		if !exists {
+			return nil, fmt.Errorf("effect node '%s' for edge not found in graph", edge[1])
+		}
+		if !containsLogicGraphNode(effectNode.Causes, causeNode) {
+			effectNode.Causes = append(effectNode.Causes, causeNode)
+		}
+	}
+
+	return lg, nil
+}
+
+func containsLogicGraphNode(nodes []*LogicGraphNode, target *LogicGraphNode) bool {
+	for _, node := range nodes {
+		if node == target {
+			return true
+		}
+	}
+	return false
+}
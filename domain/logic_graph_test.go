@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogicGraphJSONRoundTrip(t *testing.T) {
+	input := `{
+		"nodes": ["法人税を減税する", "企業の投資が増える", "雇用が増える"],
+		"edges": [["法人税を減税する", "企業の投資が増える"], ["企業の投資が増える", "雇用が増える"]]
+	}`
+
+	lg, err := NewLogicGraphFromJSON(input)
+	require.NoError(t, err)
+	require.Len(t, lg.Nodes, 3)
+	assert.Equal(t, "企業の投資が増える", lg.NodeMap["雇用が増える"].Causes[0].Argument)
+
+	output, err := lg.ToJSON()
+	require.NoError(t, err)
+	restored, err := NewLogicGraphFromJSON(output)
+	require.NoError(t, err)
+	restoredOutput, err := restored.ToJSON()
+	require.NoError(t, err)
+	assert.Equal(t, output, restoredOutput)
+
+	_, err = NewLogicGraphFromJSON(`{"nodes": ["A"], "edges": [["A", "B"]]}`)
+	assert.Error(t, err, "未定義のノードを参照するエッジはエラーになるべきです。")
+}
+
+func TestLogicGraphDebateGraphConversion(t *testing.T) {
+	dg := buildSampleGraph(t, false)
+
+	lg := dg.ToLogicGraph()
+	assert.Len(t, lg.Nodes, len(dg.Nodes))
+	assert.Len(t, ListAllCausalRelationships(lg), len(dg.GetAllEdges()))
+
+	converted, err := NewDebateGraphFromLogicGraph(lg)
+	require.NoError(t, err)
+	for _, edge := range dg.GetAllEdges() {
+		convertedEdge, exists := converted.GetEdge(edge.Cause.Argument, edge.Effect.Argument)
+		require.True(t, exists)
+		assert.Empty(t, convertedEdge.Certainty, "LogicGraphを経由するとアノテーションは失われます。")
+	}
+	assert.Empty(t, converted.EdgeRebuttals)
+}
+
+func TestLogicGraphNodeMetadata(t *testing.T) {
+	input := `{
+		"nodes": ["法人税を減税する", "企業の投資が増える", "雇用が増える"],
+		"edges": [["法人税を減税する", "企業の投資が増える"], ["企業の投資が増える", "雇用が増える"]],
+		"metadata": {
+			"雇用が増える": { "side": "affirmative_plan", "role": "impact", "polarity": "benefit", "stakeholder": "労働者" }
+		}
+	}`
+
+	lg, err := NewLogicGraphFromJSON(input)
+	require.NoError(t, err)
+	lg.InferNodeMetadata()
+	assert.Equal(t, NodeMetadata{Side: SideAffirmativePlan, Role: RolePremise}, lg.NodeMap["法人税を減税する"].NodeMetadata)
+	assert.Equal(t, NodeMetadata{Side: SideAffirmativePlan, Role: RoleIntermediate}, lg.NodeMap["企業の投資が増える"].NodeMetadata)
+
+	// メタデータはDebateGraphとそのJSONを経由しても失われない
+	dg, err := NewDebateGraphFromLogicGraph(lg)
+	require.NoError(t, err)
+	dgJSON, err := dg.ToJSON()
+	require.NoError(t, err)
+	assert.Contains(t, dgJSON, `"stakeholder": "労働者"`)
+	restored, err := NewDebateGraphFromJSON(dgJSON)
+	require.NoError(t, err)
+	impact, _ := restored.GetNode("雇用が増える")
+	assert.Equal(t, NewImpactMetadata(SideAffirmativePlan, PolarityBenefit, "労働者"), impact.NodeMetadata)
+	assert.Equal(t, lg.NodeMap["雇用が増える"].NodeMetadata, restored.ToLogicGraph().NodeMap["雇用が増える"].NodeMetadata)
+
+	_, err = NewLogicGraphFromJSON(`{"nodes": ["A"], "edges": [], "metadata": {"A": {"side": "negative"}}}`)
+	assert.Error(t, err, "未定義の側はエラーになるべきです。")
+}
+
+func TestNodeMetadataPolarityRequiresImpactRole(t *testing.T) {
+	assert.NoError(t, NewImpactMetadata(SideStatusQuo, PolarityHarm, "消費者").Validate())
+	assert.NoError(t, NodeMetadata{Role: RoleIntermediate}.Validate())
+
+	assert.Error(t, NodeMetadata{Role: RoleImpact}.Validate(), "影響には向きが必要です。")
+	assert.Error(t, NodeMetadata{Role: RolePremise, Polarity: PolarityBenefit}.Validate(), "影響以外には向きを設定できません。")
+	assert.Error(t, NodeMetadata{Polarity: PolarityBenefit}.Validate(), "役割が不明なノードには向きを設定できません。")
+
+	_, err := NewLogicGraphFromJSON(`{"nodes": ["A"], "edges": [], "metadata": {"A": {"role": "impact"}}}`)
+	assert.Error(t, err)
+	_, err = NewDebateGraphFromJSON(`{"nodes": [{"argument": "A", "is_rebuttal": false, "role": "premise", "polarity": "harm"}], "edges": []}`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package domain
+
+import "fmt"
+
+// ノードがどちらの世界（現状維持か肯定側のプランか）で起こる主張か
+const (
+	SideStatusQuo       = "status_quo"
+	SideAffirmativePlan = "affirmative_plan"
+)
+
+// 因果関係の中でのノードの役割
+const (
+	RolePremise      = "premise"      // 原因を持たない前提
+	RoleIntermediate = "intermediate" // 前提から影響までをつなぐ途中の主張
+	RoleImpact       = "impact"       // 影響分析で見つかったメリット・デメリット
+)
+
+// 影響の向き
+const (
+	PolarityBenefit = "benefit"
+	PolarityHarm    = "harm"
+)
+
+// NodeMetadata はノードの主張そのものとは別に、議論の構造の中での位置づけを表します。
+// 不明な項目は空文字列にします。
+type NodeMetadata struct {
+	Side        string `json:"side,omitempty"`        // Side* のいずれか
+	Role        string `json:"role,omitempty"`        // Role* のいずれか
+	Polarity    string `json:"polarity,omitempty"`    // Polarity* のいずれか。Role が impact のときのみ有効
+	Stakeholder string `json:"stakeholder,omitempty"` // 影響を受ける主体 (BenefitHarm.Who)
+}
+
+// NewImpactMetadata は影響分析のメリット・デメリットから作られたノードのメタデータを作成します。
+func NewImpactMetadata(side, polarity, stakeholder string) NodeMetadata {
+	return NodeMetadata{Side: side, Role: RoleImpact, Polarity: polarity, Stakeholder: stakeholder}
+}
+
+// IsZero はメタデータが1つも設定されていないかどうかを返します。
+func (m NodeMetadata) IsZero() bool {
+	return m == NodeMetadata{}
+}
+
+// Validate は各項目が定義済みの値または空文字列であることを確認します。
+// 影響の向きは影響にだけ意味があるため、Role が impact のときは Polarity を必須とし、それ以外のときは空であることを確認します。
+func (m NodeMetadata) Validate() error {
+	switch m.Side {
+	case "", SideStatusQuo, SideAffirmativePlan:
+	default:
+		return fmt.Errorf("unknown side '%s'", m.Side)
+	}
+	switch m.Role {
+	case "", RolePremise, RoleIntermediate, RoleImpact:
+	default:
+		return fmt.Errorf("unknown role '%s'", m.Role)
+	}
+	switch m.Polarity {
+	case "", PolarityBenefit, PolarityHarm:
+	default:
+		return fmt.Errorf("unknown polarity '%s'", m.Polarity)
+	}
+	if m.Role == RoleImpact && m.Polarity == "" {
+		return fmt.Errorf("polarity is required for role '%s'", RoleImpact)
+	}
+	if m.Role != RoleImpact && m.Polarity != "" {
+		return fmt.Errorf("polarity '%s' is only allowed for role '%s'", m.Polarity, RoleImpact)
+	}
+	return nil
+}
+
+// InferNodeMetadata は影響分析以外で追加されたノードの役割と側を因果関係から推定します。
+// 既に設定されている項目は変更しません。
+//   - 役割: 原因を持たないノードは premise、それ以外は intermediate
+//   - 側: そのノードが引き起こす先のノードの側がすべて同じであれば、その側
+func (lg *LogicGraph) InferNodeMetadata() {
+	effects := make(map[*LogicGraphNode][]*LogicGraphNode)
+	for _, effect := range lg.Nodes {
+		for _, cause := range effect.Causes {
+			effects[cause] = append(effects[cause], effect)
+		}
+	}
+
+	for _, node := range lg.Nodes {
+		if node.Role != "" {
+			continue
+		}
+		if len(node.Causes) == 0 {
+			node.Role = RolePremise
+		} else {
+			node.Role = RoleIntermediate
+		}
+	}
+
+	// 側は影響から原因の方向へ伝播させるため、変化がなくなるまで繰り返す
+	for changed := true; changed; {
+		changed = false
+		for _, node := range lg.Nodes {
+			if node.Side != "" {
+				continue
+			}
+			side := ""
+			consistent := true
+			for _, effect := range effects[node] {
+				if effect.Side == "" {
+					continue
+				}
+				if side != "" && side != effect.Side {
+					consistent = false
+					break
+				}
+				side = effect.Side
+			}
+			if consistent && side != "" {
+				node.Side = side
+				changed = true
+			}
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SourceSpan はノード・エッジ・アノテーションの根拠となった文書中の範囲です。
+// Start と End は文書先頭からのルーン単位のオフセットで、[Start, End) の範囲を表します。
+type SourceSpan struct {
+	DocumentID     string `json:"document_id"`     // NewDocumentID で計算した文書のID
+	ParagraphIndex int    `json:"paragraph_index"` // 段落分割の結果における段落の番号
+	Start          int    `json:"start"`
+	End            int    `json:"end"`
+	Text           string `json:"text"` // 文書中の実際の文字列
+}
+
+// Key はSourceSpanの同一性を判定するための文字列を返します。
+func (s SourceSpan) Key() string {
+	return fmt.Sprintf("%s:%d:%d", s.DocumentID, s.Start, s.End)
+}
+
+// NewDocumentID は文書の内容から決まるIDを返します。
+// 同じ文書からは常に同じIDが得られるため、グラフと文書を別々に保存しても対応付けられます。
+func NewDocumentID(document string) string {
+	sum := sha256.Sum256([]byte(document))
+	return "doc-" + hex.EncodeToString(sum[:8])
+}
+
+// LocateSpan はAIモデルが返した引用箇所が文書中に実際に存在することを確認し、その範囲を返します。
+// 段落が文書中に見つかればまず段落内を、見つからなければ文書全体を探します。
+// AIモデルは空白や改行を変えて引用することがあるため、空白の違いは無視して照合します。
+func LocateSpan(document string, paragraphIndex int, paragraph string, excerpt string) (*SourceSpan, error) {
+	if strings.TrimSpace(excerpt) == "" {
+		return nil, fmt.Errorf("excerpt is empty")
+	}
+
+	docRunes := []rune(document)
+	start, end, found := -1, -1, false
+	if paragraphStart, paragraphEnd, ok := findIgnoringSpaces(docRunes, 0, len(docRunes), paragraph); ok {
+		start, end, found = findIgnoringSpaces(docRunes, paragraphStart, paragraphEnd, excerpt)
+	}
+	if !found {
+		start, end, found = findIgnoringSpaces(docRunes, 0, len(docRunes), excerpt)
+	}
+	if !found {
+		return nil, fmt.Errorf("excerpt '%s' not found in document", excerpt)
+	}
+
+	return &SourceSpan{
+		DocumentID:     NewDocumentID(document),
+		ParagraphIndex: paragraphIndex,
+		Start:          start,
+		End:            end,
+		Text:           string(docRunes[start:end]),
+	}, nil
+}
+
+// findIgnoringSpaces は doc[from:to] の中から空白を無視して needle を探し、見つかった範囲をdoc上のルーンオフセットで返します。
+func findIgnoringSpaces(doc []rune, from, to int, needle string) (int, int, bool) {
+	var compact strings.Builder
+	positions := make([]int, 0, to-from)
+	for i := from; i < to; i++ {
+		if unicode.IsSpace(doc[i]) {
+			continue
+		}
+		compact.WriteRune(doc[i])
+		positions = append(positions, i)
+	}
+
+	compactNeedle := strings.Join(strings.FieldsFunc(needle, unicode.IsSpace), "")
+	if compactNeedle == "" {
+		return 0, 0, false
+	}
+	haystack := compact.String()
+	byteIndex := strings.Index(haystack, compactNeedle)
+	if byteIndex < 0 {
+		return 0, 0, false
+	}
+	startIndex := utf8.RuneCountInString(haystack[:byteIndex])
+	endIndex := startIndex + utf8.RuneCountInString(compactNeedle) - 1
+	return positions[startIndex], positions[endIndex] + 1, true
+}
+
+// appendSourceSpan は同じ範囲がまだ含まれていない場合だけspanを追加します。
+func appendSourceSpan(spans []SourceSpan, span SourceSpan) []SourceSpan {
+	for _, existing := range spans {
+		if existing.Key() == span.Key() {
+			return spans
+		}
+	}
+	return append(spans, span)
+}
+
+// unionSourceSpans は複数のリストを順に連結し、重複する範囲を取り除きます。
+func unionSourceSpans(lists ...[]SourceSpan) []SourceSpan {
+	merged := make([]SourceSpan, 0)
+	for _, spans := range lists {
+		for _, span := range spans {
+			merged = appendSourceSpan(merged, span)
+		}
+	}
+	return merged
+}
+
+// AddSource はノードが述べられている文書中の範囲を追加します。
+func (n *DebateGraphNode) AddSource(span SourceSpan) {
+	n.Sources = appendSourceSpan(n.Sources, span)
+}
+
+// AddSource はエッジの根拠となった文書中の範囲を追加します。
+func (e *DebateGraphEdge) AddSource(span SourceSpan) {
+	e.Sources = appendSourceSpan(e.Sources, span)
+}
+
+// NodeHighlight はノードに対応する文書中の範囲の一覧です。
+type NodeHighlight struct {
+	Argument string       `json:"argument"`
+	Spans    []SourceSpan `json:"spans"`
+}
+
+// EdgeHighlight はエッジに対応する文書中の範囲の一覧です。
+type EdgeHighlight struct {
+	Cause  string       `json:"cause"`
+	Effect string       `json:"effect"`
+	Spans  []SourceSpan `json:"spans"`
+}
+
+// HighlightSegment は文書を範囲の境界で区切った断片です。
+// 断片を順に連結すると元の文書になり、各断片にはその範囲を含むノードとエッジが付きます。
+type HighlightSegment struct {
+	Start     int       `json:"start"`
+	End       int       `json:"end"`
+	Text      string    `json:"text"`
+	Arguments []string  `json:"arguments,omitempty"`
+	Edges     []EdgeRef `json:"edges,omitempty"`
+}
+
+// HighlightedDocument は文書とDebateGraphの要素の対応関係です。
+type HighlightedDocument struct {
+	DocumentID string             `json:"document_id"`
+	Nodes      []NodeHighlight    `json:"nodes"`
+	Edges      []EdgeHighlight    `json:"edges"`
+	Segments   []HighlightSegment `json:"segments"`
+}
+
+// HighlightDocument はDebateGraphのノード・エッジ・アノテーションが持つ範囲のうち、documentに対応するものを集めます。
+// 別の文書から抽出された範囲や、文書の内容と一致しない範囲は無視します。
+func HighlightDocument(document string, dg *DebateGraph) (*HighlightedDocument, error) {
+	if dg == nil {
+		return nil, fmt.Errorf("cannot highlight document with nil DebateGraph")
+	}
+	return readLocked(func() (*HighlightedDocument, error) { return highlightDocument(document, dg) }, dg)
+}
+
+// highlightDocument はロックを取得せずに HighlightDocument の範囲を集めます。
+func highlightDocument(document string, dg *DebateGraph) (*HighlightedDocument, error) {
+
+	documentID := NewDocumentID(document)
+	docRunes := []rune(document)
+	isValid := func(span SourceSpan) bool {
+		return span.DocumentID == documentID &&
+			0 <= span.Start && span.Start < span.End && span.End <= len(docRunes) &&
+			string(docRunes[span.Start:span.End]) == span.Text
+	}
+	collect := func(sources []SourceSpan, fields []annotationField) []SourceSpan {
+		spans := make([]SourceSpan, 0)
+		for _, span := range sources {
+			if isValid(span) {
+				spans = appendSourceSpan(spans, span)
+			}
+		}
+		for _, field := range fields {
+			for _, evidence := range field.Values {
+				if evidence.Source != nil && isValid(*evidence.Source) {
+					spans = appendSourceSpan(spans, *evidence.Source)
+				}
+			}
+		}
+		sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+		return spans
+	}
+
+	result := &HighlightedDocument{
+		DocumentID: documentID,
+		Nodes:      make([]NodeHighlight, 0),
+		Edges:      make([]EdgeHighlight, 0),
+		Segments:   make([]HighlightSegment, 0),
+	}
+	for _, node := range dg.Nodes {
+		if spans := collect(node.Sources, nodeAnnotationFields(node)); len(spans) > 0 {
+			result.Nodes = append(result.Nodes, NodeHighlight{Argument: node.Argument, Spans: spans})
+		}
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool { return result.Nodes[i].Argument < result.Nodes[j].Argument })
+	for _, edge := range dg.allEdges() {
+		if spans := collect(edge.Sources, edgeAnnotationFields(edge)); len(spans) > 0 {
+			result.Edges = append(result.Edges, EdgeHighlight{Cause: edge.Cause.Argument, Effect: edge.Effect.Argument, Spans: spans})
+		}
+	}
+
+	// 全ての範囲の境界で文書を区切る
+	boundarySet := map[int]bool{0: true, len(docRunes): true}
+	for _, highlight := range result.Nodes {
+		for _, span := range highlight.Spans {
+			boundarySet[span.Start], boundarySet[span.End] = true, true
+		}
+	}
+	for _, highlight := range result.Edges {
+		for _, span := range highlight.Spans {
+			boundarySet[span.Start], boundarySet[span.End] = true, true
+		}
+	}
+	boundaries := make([]int, 0, len(boundarySet))
+	for boundary := range boundarySet {
+		boundaries = append(boundaries, boundary)
+	}
+	sort.Ints(boundaries)
+
+	for i := 0; i+1 < len(boundaries); i++ {
+		start, end := boundaries[i], boundaries[i+1]
+		segment := HighlightSegment{Start: start, End: end, Text: string(docRunes[start:end])}
+		for _, highlight := range result.Nodes {
+			if spansCover(highlight.Spans, start, end) {
+				segment.Arguments = append(segment.Arguments, highlight.Argument)
+			}
+		}
+		for _, highlight := range result.Edges {
+			if spansCover(highlight.Spans, start, end) {
+				segment.Edges = append(segment.Edges, EdgeRef{Cause: highlight.Cause, Effect: highlight.Effect})
+			}
+		}
+		result.Segments = append(result.Segments, segment)
+	}
+
+	return result, nil
+}
+
+// spansCover は [start, end) がspansのいずれかに含まれるかどうかを返します。
+func spansCover(spans []SourceSpan, start, end int) bool {
+	for _, span := range spans {
+		if span.Start <= start && end <= span.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocateSpanAndHighlightDocument(t *testing.T) {
+	document := "再生可能エネルギーを導入すべきです。\n\nCO2排出量が削減されるため、\n地球温暖化の進行が緩和されます。"
+	paragraph := "CO2排出量が削減されるため、地球温暖化の進行が緩和されます。"
+
+	// AIモデルが改行を除いて引用しても、文書中の位置を特定できる
+	span, err := LocateSpan(document, 1, paragraph, "削減されるため、地球温暖化")
+	require.NoError(t, err)
+	assert.Equal(t, NewDocumentID(document), span.DocumentID)
+	assert.Equal(t, "削減されるため、\n地球温暖化", span.Text)
+	assert.Equal(t, span.Text, string([]rune(document)[span.Start:span.End]))
+
+	_, err = LocateSpan(document, 1, paragraph, "原子力発電所を増設する")
+	assert.Error(t, err, "文書中に存在しない引用は検証に失敗するべきです。")
+
+	dg := buildSampleGraph(t, false)
+	causeSpan, err := LocateSpan(document, 1, paragraph, "CO2排出量が削減される")
+	require.NoError(t, err)
+	node, _ := dg.GetNode("CO2排出量が削減される")
+	node.AddSource(*causeSpan)
+	node.AddSource(*causeSpan)
+	assert.Len(t, node.Sources, 1, "同じ範囲は重複して追加されません。")
+
+	edge, _ := dg.GetEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される")
+	edge.Certainty = append(edge.Certainty, Evidence{Claim: "排出削減は温暖化を緩和する", Source: span})
+	// 別の文書から抽出された範囲は無視される
+	edge.AddSource(SourceSpan{DocumentID: NewDocumentID("別の文書"), Start: 0, End: 2, Text: "別の"})
+
+	restored, err := NewDebateGraphFromJSON(mustToJSON(t, dg))
+	require.NoError(t, err)
+	highlighted, err := HighlightDocument(document, restored)
+	require.NoError(t, err)
+
+	require.Len(t, highlighted.Nodes, 1)
+	assert.Equal(t, "CO2排出量が削減される", highlighted.Nodes[0].Argument)
+	require.Len(t, highlighted.Edges, 1)
+	assert.Equal(t, []SourceSpan{*span}, highlighted.Edges[0].Spans)
+
+	text := ""
+	for _, segment := range highlighted.Segments {
+		text += segment.Text
+		if segment.Text == "削減される" {
+			assert.Equal(t, []string{"CO2排出量が削減される"}, segment.Arguments)
+			assert.Len(t, segment.Edges, 1)
+		}
+	}
+	assert.Equal(t, document, text, "断片を連結すると元の文書になります。")
+}
+
+func TestNewEvidenceFromExcerptAndAnnotate(t *testing.T) {
+	document := "再生可能エネルギーを導入すべきです。\n\nCO2排出量が削減されるため、\n地球温暖化の進行が緩和されます。"
+	paragraph := "CO2排出量が削減されるため、地球温暖化の進行が緩和されます。"
+
+	evidence, err := NewEvidenceFromExcerpt(document, 1, paragraph, "削減されるため、地球温暖化", "排出削減は温暖化を緩和する")
+	require.NoError(t, err)
+	require.NotNil(t, evidence.Source)
+	assert.Equal(t, "削減されるため、\n地球温暖化", evidence.Source.Text)
+	assert.Equal(t, NewDocumentID(document), evidence.SourceDocumentID)
+	assert.Equal(t, "削減されるため、地球温暖化", evidence.QuotedSpan)
+
+	missing, err := NewEvidenceFromExcerpt(document, 1, paragraph, "原子力発電所を増設する", "原子力は安定している")
+	assert.Error(t, err, "文書中に存在しない引用は位置を特定できません。")
+	assert.Nil(t, missing.Source)
+	assert.Equal(t, "原子力は安定している", missing.Claim)
+
+	dg := buildSampleGraph(t, false)
+	require.NoError(t, dg.AnnotateEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される", EdgeAnnotationCertainty, evidence))
+	edge, _ := dg.GetEdge("CO2排出量が削減される", "地球温暖化の進行が緩和される")
+	assert.Contains(t, edge.Certainty, evidence)
+	assert.Equal(t, []SourceSpan{*evidence.Source}, edge.Sources)
+
+	// 種類が空の場合は、ノードが述べられている範囲だけを追加する
+	node, _ := dg.GetNode("CO2排出量が削減される")
+	importanceCount := len(node.Importance)
+	require.NoError(t, dg.AnnotateNode("CO2排出量が削減される", "", evidence))
+	assert.Equal(t, []SourceSpan{*evidence.Source}, node.Sources)
+	assert.Len(t, node.Importance, importanceCount)
+
+	assert.Error(t, dg.AnnotateNode("原子力発電所を増設する", NodeAnnotationImportance, missing), "存在しないノードへのアノテーションは失敗するべきです。")
+}
+
+func mustToJSON(t *testing.T, dg *DebateGraph) string {
+	t.Helper()
+	jsonData, err := dg.ToJSON()
+	require.NoError(t, err)
+	return jsonData
+}
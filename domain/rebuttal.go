@@ -1,6 +1,10 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 // ノードに対する反論
 type DebateGraphNodeRebuttal struct {
@@ -22,11 +26,28 @@ type CounterArgumentRebuttal struct {
 }
 
 type TurnArgumentRebuttal struct {
-	RebuttalNode *DebateGraphNode // ターンによりメリット・デメリットを主張するノード
+	RebuttalNode     *DebateGraphNode   // ターンによりメリット・デメリットを主張するノード
+	TargetCauseNodes []*DebateGraphNode // ターンで認めている相手の議論のノード。各ノードからRebuttalNodeへのエッジが引かれる
+}
+
+// 反論関係そのものに対する反論
+// 例えば「その反論は元の主張の前提を取り違えており、反論になっていない」のように、
+// 反論ノードの内容ではなく、反論が対象に当てはまることを否定します。
+// 対象にはRelationRebuttal自身も指定できるため、再反論の連鎖を任意の深さで表現できます。
+type RelationRebuttal struct {
+	TargetRelation RebuttalRelation // どの反論関係に反論するか
+	RebuttalNode   *DebateGraphNode // 反論を行うノード
 }
 
 func NewDebateGraphNodeRebuttal(debateGraph *DebateGraph, targetArgument string, rebuttalType string, argument string) (*DebateGraphNodeRebuttal, error) {
-	targetNode, exists := debateGraph.GetNode(targetArgument)
+	debateGraph.mu.RLock()
+	defer debateGraph.mu.RUnlock()
+	return newDebateGraphNodeRebuttal(debateGraph, targetArgument, rebuttalType, argument)
+}
+
+// newDebateGraphNodeRebuttal はロックを取得せずにノードへの反論を作成します。
+func newDebateGraphNodeRebuttal(debateGraph *DebateGraph, targetArgument string, rebuttalType string, argument string) (*DebateGraphNodeRebuttal, error) {
+	targetNode, exists := debateGraph.getNode(targetArgument)
 	if !exists {
 		return nil, fmt.Errorf("target node '%s' not found in debate graph", targetArgument)
 	}
@@ -35,7 +56,7 @@ func NewDebateGraphNodeRebuttal(debateGraph *DebateGraph, targetArgument string,
 		return nil, fmt.Errorf("invalid rebuttal type '%s' for node rebuttal", rebuttalType)
 	}
 
-	rebuttalNode, exists := debateGraph.GetNode(argument)
+	rebuttalNode, exists := debateGraph.getNode(argument)
 	if !exists {
 		return nil, fmt.Errorf("rebuttal node '%s' not found in debate graph", argument)
 	}
@@ -48,7 +69,14 @@ func NewDebateGraphNodeRebuttal(debateGraph *DebateGraph, targetArgument string,
 }
 
 func NewDebateGraphEdgeRebuttal(debateGraph *DebateGraph, targetCauseArgument string, targetEffectArgument string, rebuttalType string, argument string) (*DebateGraphEdgeRebuttal, error) {
-	targetEdge, exists := debateGraph.GetEdge(targetCauseArgument, targetEffectArgument)
+	debateGraph.mu.RLock()
+	defer debateGraph.mu.RUnlock()
+	return newDebateGraphEdgeRebuttal(debateGraph, targetCauseArgument, targetEffectArgument, rebuttalType, argument)
+}
+
+// newDebateGraphEdgeRebuttal はロックを取得せずにエッジへの反論を作成します。
+func newDebateGraphEdgeRebuttal(debateGraph *DebateGraph, targetCauseArgument string, targetEffectArgument string, rebuttalType string, argument string) (*DebateGraphEdgeRebuttal, error) {
+	targetEdge, exists := debateGraph.getEdge(targetCauseArgument, targetEffectArgument)
 	if !exists {
 		return nil, fmt.Errorf("target edge '%s -> %s' not found in debate graph", targetCauseArgument, targetEffectArgument)
 	}
@@ -57,7 +85,7 @@ func NewDebateGraphEdgeRebuttal(debateGraph *DebateGraph, targetCauseArgument st
 		return nil, fmt.Errorf("invalid rebuttal type '%s' for edge rebuttal", rebuttalType)
 	}
 
-	rebuttalNode, exists := debateGraph.GetNode(argument)
+	rebuttalNode, exists := debateGraph.getNode(argument)
 	if !exists {
 		return nil, fmt.Errorf("rebuttal node '%s' not found in debate graph", argument)
 	}
@@ -70,12 +98,19 @@ func NewDebateGraphEdgeRebuttal(debateGraph *DebateGraph, targetCauseArgument st
 }
 
 func NewCounterArgumentRebuttal(debateGraph *DebateGraph, targetArgument, rebuttalArgument string) (*CounterArgumentRebuttal, error) {
-	rebuttalNode, exists := debateGraph.GetNode(rebuttalArgument)
+	debateGraph.mu.RLock()
+	defer debateGraph.mu.RUnlock()
+	return newCounterArgumentRebuttal(debateGraph, targetArgument, rebuttalArgument)
+}
+
+// newCounterArgumentRebuttal はロックを取得せずにカウンターアーギュメントを作成します。
+func newCounterArgumentRebuttal(debateGraph *DebateGraph, targetArgument, rebuttalArgument string) (*CounterArgumentRebuttal, error) {
+	rebuttalNode, exists := debateGraph.getNode(rebuttalArgument)
 	if !exists {
 		return nil, fmt.Errorf("rebuttal node '%s' not found in debate graph", rebuttalArgument)
 	}
 
-	targetNode, exists := debateGraph.GetNode(targetArgument)
+	targetNode, exists := debateGraph.getNode(targetArgument)
 	if !exists {
 		return nil, fmt.Errorf("target node '%s' not found in debate graph", targetArgument)
 	}
@@ -86,13 +121,296 @@ func NewCounterArgumentRebuttal(debateGraph *DebateGraph, targetArgument, rebutt
 	}, nil
 }
 
-func NewTurnArgumentRebuttal(debateGraph *DebateGraph, argument string) (*TurnArgumentRebuttal, error) {
-	rebuttalNode, exists := debateGraph.GetNode(argument)
+// NewTurnArgumentRebuttal はターンを作成します。
+// targetCauseArguments はターンで認めている相手の議論のノードで、空の場合は認めている範囲が不明なターンになります。
+// 認めているノードからターンのノードへのエッジは作成しないため、必要に応じて AddTurnArgumentRebuttal を使用してください。
+func NewTurnArgumentRebuttal(debateGraph *DebateGraph, argument string, targetCauseArguments []string) (*TurnArgumentRebuttal, error) {
+	debateGraph.mu.RLock()
+	defer debateGraph.mu.RUnlock()
+	return newTurnArgumentRebuttal(debateGraph, argument, targetCauseArguments)
+}
+
+// newTurnArgumentRebuttal はロックを取得せずにターンを作成します。
+func newTurnArgumentRebuttal(debateGraph *DebateGraph, argument string, targetCauseArguments []string) (*TurnArgumentRebuttal, error) {
+	rebuttalNode, exists := debateGraph.getNode(argument)
 	if !exists {
 		return nil, fmt.Errorf("rebuttal node '%s' not found in debate graph", argument)
 	}
 
+	targetCauseNodes := make([]*DebateGraphNode, 0, len(targetCauseArguments))
+	for _, causeArgument := range targetCauseArguments {
+		causeNode, exists := debateGraph.getNode(causeArgument)
+		if !exists {
+			return nil, fmt.Errorf("target cause node '%s' for turn argument '%s' not found in debate graph", causeArgument, argument)
+		}
+		if causeNode == rebuttalNode {
+			return nil, fmt.Errorf("turn argument '%s' cannot concede itself", argument)
+		}
+		targetCauseNodes = append(targetCauseNodes, causeNode)
+	}
+
 	return &TurnArgumentRebuttal{
-		RebuttalNode: rebuttalNode,
+		RebuttalNode:     rebuttalNode,
+		TargetCauseNodes: targetCauseNodes,
 	}, nil
 }
+
+// AddTurnArgumentRebuttal はターンをグラフに追加し、認めている各ノードからターンのノードへの反論エッジを作成します。
+// 既に存在するエッジはそのまま使用します。
+func (dg *DebateGraph) AddTurnArgumentRebuttal(argument string, targetCauseArguments []string) (*TurnArgumentRebuttal, error) {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	return dg.addTurnArgumentRebuttal(argument, targetCauseArguments)
+}
+
+// addTurnArgumentRebuttal はロックを取得せずにターンをグラフに追加します。
+func (dg *DebateGraph) addTurnArgumentRebuttal(argument string, targetCauseArguments []string) (*TurnArgumentRebuttal, error) {
+	rebuttal, err := newTurnArgumentRebuttal(dg, argument, targetCauseArguments)
+	if err != nil {
+		return nil, err
+	}
+	for _, causeNode := range rebuttal.TargetCauseNodes {
+		if _, exists := dg.getEdge(causeNode.Argument, rebuttal.RebuttalNode.Argument); exists {
+			continue
+		}
+		if err := dg.addEdge(NewDebateGraphEdge(causeNode, rebuttal.RebuttalNode, true)); err != nil {
+			return nil, fmt.Errorf("failed to link conceded node '%s' to turn argument '%s': %w", causeNode.Argument, argument, err)
+		}
+	}
+	dg.TurnArgumentRebuttals = append(dg.TurnArgumentRebuttals, rebuttal)
+	return rebuttal, nil
+}
+
+// TargetCauseArguments は認めているノードのArgumentを辞書順で返します。
+func (r *TurnArgumentRebuttal) TargetCauseArguments() []string {
+	arguments := make([]string, 0, len(r.TargetCauseNodes))
+	for _, node := range r.TargetCauseNodes {
+		arguments = append(arguments, node.Argument)
+	}
+	sort.Strings(arguments)
+	return arguments
+}
+
+// NewRelationRebuttal は、グラフ内に存在する反論関係に対する反論を作成します。
+func NewRelationRebuttal(debateGraph *DebateGraph, targetRelation RebuttalRelation, argument string) (*RelationRebuttal, error) {
+	debateGraph.mu.RLock()
+	defer debateGraph.mu.RUnlock()
+	return newRelationRebuttal(debateGraph, targetRelation, argument)
+}
+
+// newRelationRebuttal はロックを取得せずに反論関係への反論を作成します。
+func newRelationRebuttal(debateGraph *DebateGraph, targetRelation RebuttalRelation, argument string) (*RelationRebuttal, error) {
+	if !debateGraph.hasRebuttalRelation(targetRelation) {
+		return nil, fmt.Errorf("target relation '%s' not found in debate graph", targetRelation.Describe())
+	}
+
+	rebuttalNode, exists := debateGraph.getNode(argument)
+	if !exists {
+		return nil, fmt.Errorf("rebuttal node '%s' not found in debate graph", argument)
+	}
+	if rebuttalNode.Argument == targetRelation.RebuttalArgument {
+		return nil, fmt.Errorf("rebuttal node '%s' cannot rebut its own relation", argument)
+	}
+
+	return &RelationRebuttal{
+		TargetRelation: targetRelation,
+		RebuttalNode:   rebuttalNode,
+	}, nil
+}
+
+// 反論関係の種類。rebuttal_analyzer.RebuttalItem.RebuttalKind と同じ値を使用します。
+const (
+	RebuttalKindNode            = "node_rebuttal"
+	RebuttalKindEdge            = "edge_rebuttal"
+	RebuttalKindCounterArgument = "counter_argument"
+	RebuttalKindTurnArgument    = "turn_argument"
+	RebuttalKindRelation        = "relation_rebuttal"
+)
+
+// RebuttalRelation は5種類の反論関係を、ノードのArgument文字列だけで表した値です。
+// ノードへのポインタを含まないため、異なるグラフ間での比較やJSONでの受け渡しに使用します。
+type RebuttalRelation struct {
+	Kind                 string            `json:"kind"`                             // RebuttalKind* のいずれか
+	TargetArgument       string            `json:"target_argument,omitempty"`        // node_rebuttal, counter_argument のときのみ有効
+	TargetCauseArgument  string            `json:"target_cause_argument,omitempty"`  // edge_rebuttal のときのみ有効
+	TargetEffectArgument string            `json:"target_effect_argument,omitempty"` // edge_rebuttal のときのみ有効
+	RebuttalType         string            `json:"rebuttal_type,omitempty"`          // node_rebuttal, edge_rebuttal のときのみ有効
+	TargetRelation       *RebuttalRelation `json:"target_relation,omitempty"`        // relation_rebuttal のときのみ有効
+	TargetCauseArguments []string          `json:"target_cause_arguments,omitempty"` // turn_argument のときのみ有効。辞書順
+	RebuttalArgument     string            `json:"rebuttal_argument"`
+}
+
+// Key は反論関係を一意に識別する文字列を返します。
+// relation_rebuttal の場合は、対象の反論関係のKeyを括弧で囲んで含めます。
+func (r RebuttalRelation) Key() string {
+	parts := []string{r.Kind, r.TargetArgument, r.TargetCauseArgument, r.TargetEffectArgument, r.RebuttalType, r.RebuttalArgument}
+	if len(r.TargetCauseArguments) > 0 {
+		causes := append([]string(nil), r.TargetCauseArguments...)
+		sort.Strings(causes)
+		parts = append(parts, "["+strings.Join(causes, "\x00")+"]")
+	}
+	if r.TargetRelation != nil {
+		parts = append(parts, "("+r.TargetRelation.Key()+")")
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// Describe はログやエラーメッセージのための、反論関係の短い説明を返します。
+func (r RebuttalRelation) Describe() string {
+	switch r.Kind {
+	case RebuttalKindEdge:
+		return fmt.Sprintf("%s '%s' -> ['%s' -> '%s']", r.Kind, r.RebuttalArgument, r.TargetCauseArgument, r.TargetEffectArgument)
+	case RebuttalKindTurnArgument:
+		return fmt.Sprintf("%s '%s'", r.Kind, r.RebuttalArgument)
+	case RebuttalKindRelation:
+		if r.TargetRelation != nil {
+			return fmt.Sprintf("%s '%s' -> [%s]", r.Kind, r.RebuttalArgument, r.TargetRelation.Describe())
+		}
+	}
+	return fmt.Sprintf("%s '%s' -> '%s'", r.Kind, r.RebuttalArgument, r.TargetArgument)
+}
+
+// Relation はノード反論をRebuttalRelationとして返します。
+func (r *DebateGraphNodeRebuttal) Relation() RebuttalRelation {
+	return RebuttalRelation{
+		Kind:             RebuttalKindNode,
+		TargetArgument:   r.TargetNode.Argument,
+		RebuttalType:     r.RebuttalType,
+		RebuttalArgument: r.RebuttalNode.Argument,
+	}
+}
+
+// Relation はエッジ反論をRebuttalRelationとして返します。
+func (r *DebateGraphEdgeRebuttal) Relation() RebuttalRelation {
+	return RebuttalRelation{
+		Kind:                 RebuttalKindEdge,
+		TargetCauseArgument:  r.TargetEdge.Cause.Argument,
+		TargetEffectArgument: r.TargetEdge.Effect.Argument,
+		RebuttalType:         r.RebuttalType,
+		RebuttalArgument:     r.RebuttalNode.Argument,
+	}
+}
+
+// Relation は反対意見をRebuttalRelationとして返します。
+func (r *CounterArgumentRebuttal) Relation() RebuttalRelation {
+	return RebuttalRelation{
+		Kind:             RebuttalKindCounterArgument,
+		TargetArgument:   r.TargetNode.Argument,
+		RebuttalArgument: r.RebuttalNode.Argument,
+	}
+}
+
+// Relation はターンをRebuttalRelationとして返します。
+func (r *TurnArgumentRebuttal) Relation() RebuttalRelation {
+	relation := RebuttalRelation{
+		Kind:             RebuttalKindTurnArgument,
+		RebuttalArgument: r.RebuttalNode.Argument,
+	}
+	if len(r.TargetCauseNodes) > 0 {
+		relation.TargetCauseArguments = r.TargetCauseArguments()
+	}
+	return relation
+}
+
+// Relation は反論関係への反論をRebuttalRelationとして返します。
+func (r *RelationRebuttal) Relation() RebuttalRelation {
+	target := r.TargetRelation
+	return RebuttalRelation{
+		Kind:             RebuttalKindRelation,
+		TargetRelation:   &target,
+		RebuttalArgument: r.RebuttalNode.Argument,
+	}
+}
+
+// RebuttalRelations はグラフ内の全ての反論関係をRebuttalRelationとして、Key順に返します。
+func (dg *DebateGraph) RebuttalRelations() []RebuttalRelation {
+	dg.mu.RLock()
+	defer dg.mu.RUnlock()
+	return dg.rebuttalRelations()
+}
+
+// rebuttalRelations はロックを取得せずに全ての反論関係を返します。
+func (dg *DebateGraph) rebuttalRelations() []RebuttalRelation {
+	relations := make([]RebuttalRelation, 0, len(dg.NodeRebuttals)+len(dg.EdgeRebuttals)+len(dg.CounterArgumentRebuttals)+len(dg.TurnArgumentRebuttals)+len(dg.RelationRebuttals))
+	for _, r := range dg.NodeRebuttals {
+		relations = append(relations, r.Relation())
+	}
+	for _, r := range dg.EdgeRebuttals {
+		relations = append(relations, r.Relation())
+	}
+	for _, r := range dg.CounterArgumentRebuttals {
+		relations = append(relations, r.Relation())
+	}
+	for _, r := range dg.TurnArgumentRebuttals {
+		relations = append(relations, r.Relation())
+	}
+	for _, r := range dg.RelationRebuttals {
+		relations = append(relations, r.Relation())
+	}
+	sort.Slice(relations, func(i, j int) bool {
+		return relations[i].Key() < relations[j].Key()
+	})
+	return relations
+}
+
+// hasRebuttalRelation はグラフに同じKeyの反論関係が存在するかどうかを返します。
+// ロックは取得しません。
+func (dg *DebateGraph) hasRebuttalRelation(relation RebuttalRelation) bool {
+	key := relation.Key()
+	for _, existing := range dg.rebuttalRelations() {
+		if existing.Key() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// AddRebuttalRelation はRebuttalRelationが表す反論関係をグラフに追加します。
+// 対象と反論のノード（エッジ反論の場合はエッジ、反論関係への反論の場合は対象の反論関係）は事前にグラフに追加されている必要があります。
+// ターンの場合は、認めているノードからターンのノードへのエッジも作成します。
+func (dg *DebateGraph) AddRebuttalRelation(relation RebuttalRelation) error {
+	dg.mu.Lock()
+	defer dg.mu.Unlock()
+	return dg.addRebuttalRelation(relation)
+}
+
+// addRebuttalRelation はロックを取得せずに反論関係を追加します。
+// 検証と追加を同じロックの中で行うため、検証後に対象が削除された反論関係が追加されることはありません。
+func (dg *DebateGraph) addRebuttalRelation(relation RebuttalRelation) error {
+	switch relation.Kind {
+	case RebuttalKindNode:
+		rebuttal, err := newDebateGraphNodeRebuttal(dg, relation.TargetArgument, relation.RebuttalType, relation.RebuttalArgument)
+		if err != nil {
+			return err
+		}
+		dg.NodeRebuttals = append(dg.NodeRebuttals, rebuttal)
+	case RebuttalKindEdge:
+		rebuttal, err := newDebateGraphEdgeRebuttal(dg, relation.TargetCauseArgument, relation.TargetEffectArgument, relation.RebuttalType, relation.RebuttalArgument)
+		if err != nil {
+			return err
+		}
+		dg.EdgeRebuttals = append(dg.EdgeRebuttals, rebuttal)
+	case RebuttalKindCounterArgument:
+		rebuttal, err := newCounterArgumentRebuttal(dg, relation.TargetArgument, relation.RebuttalArgument)
+		if err != nil {
+			return err
+		}
+		dg.CounterArgumentRebuttals = append(dg.CounterArgumentRebuttals, rebuttal)
+	case RebuttalKindTurnArgument:
+		if _, err := dg.addTurnArgumentRebuttal(relation.RebuttalArgument, relation.TargetCauseArguments); err != nil {
+			return err
+		}
+	case RebuttalKindRelation:
+		if relation.TargetRelation == nil {
+			return fmt.Errorf("relation rebuttal '%s' has no target relation", relation.RebuttalArgument)
+		}
+		rebuttal, err := newRelationRebuttal(dg, *relation.TargetRelation, relation.RebuttalArgument)
+		if err != nil {
+			return err
+		}
+		dg.RelationRebuttals = append(dg.RelationRebuttals, rebuttal)
+	default:
+		return fmt.Errorf("unknown rebuttal kind '%s'", relation.Kind)
+	}
+	return nil
+}
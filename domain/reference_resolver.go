@@ -0,0 +1,267 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 参照解決の一致方法
+const (
+	MatchTypeExact      = "exact"      // 文字列が完全に一致した
+	MatchTypeNormalized = "normalized" // 空白・句読点・全角半角・大文字小文字の違いを除いて一致した
+)
+
+// DefaultSuggestionThreshold は、解決できなかった参照に似た候補として示す類似度の既定値です。
+const DefaultSuggestionThreshold = 0.7
+
+// maxSuggestions は解決できなかった参照に対して示す候補の最大数です。
+const maxSuggestions = 3
+
+// ResolvedReference はAIモデルが返した文字列を既存のノードに対応付けた結果です。
+type ResolvedReference struct {
+	Reference string `json:"reference"`  // AIモデルが返した文字列
+	Argument  string `json:"argument"`   // 対応付けられた既存ノードのArgument
+	MatchType string `json:"match_type"` // MatchType* のいずれか
+}
+
+// UnresolvedReferenceError は参照先のノードが見つからない、または候補が複数あって特定できない場合のエラーです。
+type UnresolvedReferenceError struct {
+	Reference   string
+	Candidates  []string // 正規化後に一致した複数の候補 (あいまいな場合のみ)
+	Suggestions []string // 見つからない場合の、編集距離が近い候補 (判断材料であり、自動では対応付けない)
+}
+
+func (e *UnresolvedReferenceError) Error() string {
+	if len(e.Candidates) > 1 {
+		return fmt.Sprintf("reference '%s' is ambiguous between %s", e.Reference, strings.Join(quoteAll(e.Candidates), ", "))
+	}
+	if len(e.Suggestions) > 0 {
+		return fmt.Sprintf("reference '%s' does not match any existing node (similar: %s)", e.Reference, strings.Join(quoteAll(e.Suggestions), ", "))
+	}
+	return fmt.Sprintf("reference '%s' does not match any existing node", e.Reference)
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("'%s'", v)
+	}
+	return quoted
+}
+
+// ArgumentResolver はAIモデルが返した主張の文字列を、既存ノードのArgumentに対応付けます。
+// 完全一致、正規化後の一致の順に試します。
+// 編集距離によるあいまい一致は「引き上げる」と「引き下げる」のような逆の主張を同一視してしまうため、
+// 対応付けには使わず、解決できなかった場合の候補の提示にだけ使います。
+type ArgumentResolver struct {
+	SuggestionThreshold float64
+
+	arguments  []string
+	exact      map[string]bool
+	normalized map[string][]string
+}
+
+// NewArgumentResolver は候補となるArgumentの一覧からArgumentResolverを作成します。
+func NewArgumentResolver(arguments []string) *ArgumentResolver {
+	resolver := &ArgumentResolver{
+		SuggestionThreshold: DefaultSuggestionThreshold,
+		arguments:           make([]string, 0, len(arguments)),
+		exact:               make(map[string]bool, len(arguments)),
+		normalized:          make(map[string][]string, len(arguments)),
+	}
+	for _, argument := range arguments {
+		resolver.Add(argument)
+	}
+	return resolver
+}
+
+// NewArgumentResolverForDebateGraph はDebateGraphの全ノードを候補とするArgumentResolverを作成します。
+func NewArgumentResolverForDebateGraph(dg *DebateGraph) *ArgumentResolver {
+	dg.mu.RLock()
+	defer dg.mu.RUnlock()
+	arguments := make([]string, 0, len(dg.Nodes))
+	for _, node := range dg.Nodes {
+		arguments = append(arguments, node.Argument)
+	}
+	return NewArgumentResolver(arguments)
+}
+
+// NewArgumentResolverForLogicGraph はLogicGraphの全ノードを候補とするArgumentResolverを作成します。
+func NewArgumentResolverForLogicGraph(lg *LogicGraph) *ArgumentResolver {
+	arguments := make([]string, 0, len(lg.Nodes))
+	for _, node := range lg.Nodes {
+		arguments = append(arguments, node.Argument)
+	}
+	return NewArgumentResolver(arguments)
+}
+
+// Add は候補となるArgumentを追加します。
+func (r *ArgumentResolver) Add(argument string) {
+	if r.exact[argument] {
+		return
+	}
+	r.arguments = append(r.arguments, argument)
+	r.exact[argument] = true
+	key := NormalizeArgument(argument)
+	r.normalized[key] = append(r.normalized[key], argument)
+}
+
+// Resolve はreferenceに対応する既存のArgumentを返します。
+// 見つからない場合や候補を1つに絞れない場合は *UnresolvedReferenceError を返します。
+func (r *ArgumentResolver) Resolve(reference string) (*ResolvedReference, error) {
+	// 1. 完全一致
+	if r.exact[reference] {
+		return &ResolvedReference{Reference: reference, Argument: reference, MatchType: MatchTypeExact}, nil
+	}
+
+	// 2. 正規化後の一致
+	key := NormalizeArgument(reference)
+	if matches := r.normalized[key]; len(matches) == 1 {
+		return &ResolvedReference{Reference: reference, Argument: matches[0], MatchType: MatchTypeNormalized}, nil
+	} else if len(matches) > 1 {
+		return nil, &UnresolvedReferenceError{Reference: reference, Candidates: matches}
+	}
+
+	return nil, &UnresolvedReferenceError{Reference: reference, Suggestions: r.Suggest(reference)}
+}
+
+// Suggest はreferenceと編集距離が近い候補を、類似度の高い順に最大 maxSuggestions 件返します。
+// 結果は利用者やAIモデルに示すためのもので、ノードの対応付けには使いません。
+func (r *ArgumentResolver) Suggest(reference string) []string {
+	type candidate struct {
+		argument   string
+		similarity float64
+	}
+	key := NormalizeArgument(reference)
+	candidates := make([]candidate, 0)
+	for _, argument := range r.arguments {
+		if score := similarity(key, NormalizeArgument(argument)); score >= r.SuggestionThreshold {
+			candidates = append(candidates, candidate{argument: argument, similarity: score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].similarity > candidates[j].similarity
+	})
+
+	suggestions := make([]string, 0, maxSuggestions)
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].argument)
+	}
+	return suggestions
+}
+
+// ResolveAll は複数の参照をまとめて解決します。
+// 解決できた参照は入力順に、解決できなかった参照はエラーとして返します。
+// 同じArgumentに解決された参照は1つにまとめます。
+func (r *ArgumentResolver) ResolveAll(references []string) ([]ResolvedReference, []error) {
+	resolved := make([]ResolvedReference, 0, len(references))
+	unresolved := make([]error, 0)
+	seen := make(map[string]bool)
+	for _, reference := range references {
+		ref, err := r.Resolve(reference)
+		if err != nil {
+			unresolved = append(unresolved, err)
+			continue
+		}
+		if seen[ref.Argument] {
+			continue
+		}
+		seen[ref.Argument] = true
+		resolved = append(resolved, *ref)
+	}
+	return resolved, unresolved
+}
+
+// NormalizeArgument は表記揺れを吸収するために主張の文字列を正規化します。
+// 全角英数字・記号を半角にし、空白 (全角空白を含む) と句読点を除去し、英字を小文字にします。
+// 文字の除去と置き換えだけを行うため、述語などの内容語が異なる主張が同じ値になることはありません。
+func NormalizeArgument(argument string) string {
+	var builder strings.Builder
+	for _, r := range argument {
+		switch {
+		case r >= '！' && r <= '～':
+			r = r - '！' + '!'
+		case r == '　':
+			r = ' '
+		}
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			continue
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
+
+// similarity は2つの文字列の編集距離に基づく類似度 (0〜1) を返します。
+func similarity(a, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+	longest := len(ar)
+	if len(br) > longest {
+		longest = len(br)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ar, br))/float64(longest)
+}
+
+// levenshtein はルーン単位の編集距離を計算します。
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// ResolveNode はAIモデルが返した文字列に対応するノードを返します。
+func (dg *DebateGraph) ResolveNode(reference string) (*DebateGraphNode, *ResolvedReference, error) {
+	resolved, err := NewArgumentResolverForDebateGraph(dg).Resolve(reference)
+	if err != nil {
+		return nil, nil, err
+	}
+	node, _ := dg.GetNode(resolved.Argument)
+	return node, resolved, nil
+}
+
+// ResolveEdge はAIモデルが返したCauseとEffectの文字列に対応するエッジを返します。
+// 両端のノードが解決できても、その間にエッジが存在しない場合はエラーを返します。
+func (dg *DebateGraph) ResolveEdge(causeReference, effectReference string) (*DebateGraphEdge, error) {
+	resolver := NewArgumentResolverForDebateGraph(dg)
+	cause, err := resolver.Resolve(causeReference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve cause of edge: %w", err)
+	}
+	effect, err := resolver.Resolve(effectReference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve effect of edge: %w", err)
+	}
+	edge, exists := dg.GetEdge(cause.Argument, effect.Argument)
+	if !exists {
+		return nil, fmt.Errorf("edge '%s -> %s' not found in debate graph", cause.Argument, effect.Argument)
+	}
+	return edge, nil
+}
+
+// ResolveNode はAIモデルが返した文字列に対応するノードを返します。
+func (lg *LogicGraph) ResolveNode(reference string) (*LogicGraphNode, *ResolvedReference, error) {
+	resolved, err := NewArgumentResolverForLogicGraph(lg).Resolve(reference)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lg.NodeMap[resolved.Argument], resolved, nil
+}
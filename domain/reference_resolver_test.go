@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgumentResolver(t *testing.T) {
+	resolver := NewArgumentResolver([]string{
+		"AIが店舗情報から自動でWebサイトを生成する",
+		"オーナーは本来の調理・接客業務に集中できる",
+		"新規顧客の来店が増加する",
+		"既存顧客の来店が増加する",
+	})
+
+	resolved, err := resolver.Resolve("新規顧客の来店が増加する")
+	require.NoError(t, err)
+	assert.Equal(t, MatchTypeExact, resolved.MatchType)
+
+	// 全角英字・空白・末尾の句点の違いは正規化で吸収される
+	resolved, err = resolver.Resolve("ＡＩが店舗情報から 自動でＷｅｂサイトを生成する。")
+	require.NoError(t, err)
+	assert.Equal(t, MatchTypeNormalized, resolved.MatchType)
+	assert.Equal(t, "AIが店舗情報から自動でWebサイトを生成する", resolved.Argument)
+
+	// 文中の句読点の違いも正規化で吸収される
+	resolved, err = resolver.Resolve("オーナーは本来の調理、接客業務に集中できる")
+	require.NoError(t, err)
+	assert.Equal(t, MatchTypeNormalized, resolved.MatchType)
+	assert.Equal(t, "オーナーは本来の調理・接客業務に集中できる", resolved.Argument)
+
+	// 軽微な言い換えでも対応付けず、似た候補として示すだけにする
+	_, err = resolver.Resolve("オーナーは本来の調理や接客業務に集中できる")
+	var unresolved *UnresolvedReferenceError
+	require.ErrorAs(t, err, &unresolved)
+	assert.Equal(t, []string{"オーナーは本来の調理・接客業務に集中できる"}, unresolved.Suggestions)
+
+	// 無関係な文字列は解決せず、候補も示さない
+	_, err = resolver.Resolve("電気料金が上がる")
+	require.ErrorAs(t, err, &unresolved)
+	assert.Empty(t, unresolved.Suggestions)
+
+	// 正規化後に複数の候補と一致する場合は解決しない
+	ambiguous := NewArgumentResolver([]string{"新規顧客が増える", "新規顧客が、増える"})
+	_, err = ambiguous.Resolve("新規顧客が増える。")
+	require.ErrorAs(t, err, &unresolved)
+	assert.Len(t, unresolved.Candidates, 2)
+
+	refs, errs := resolver.ResolveAll([]string{"新規顧客の来店が増加する", "新規顧客の来店が増加する。", "電気料金が上がる"})
+	assert.Len(t, refs, 1, "同じノードに解決された参照は1つにまとめられます。")
+	assert.Len(t, errs, 1)
+}
+
+func TestArgumentResolverDoesNotResolveOppositeClaims(t *testing.T) {
+	resolver := NewArgumentResolver([]string{"法人税率を引き上げる", "消費税を廃止する"})
+
+	// 述語が逆の主張は編集距離が近くても別のノードとして扱う
+	for _, reference := range []string{"法人税率を引き下げる", "消費税を維持する"} {
+		_, err := resolver.Resolve(reference)
+		var unresolved *UnresolvedReferenceError
+		require.ErrorAs(t, err, &unresolved, reference)
+		assert.Empty(t, unresolved.Candidates)
+	}
+
+	dg := NewDebateGraph()
+	require.NoError(t, dg.AddNode(NewDebateGraphNode("法人税率を引き上げる", false)))
+	_, _, err := dg.ResolveNode("法人税率を引き下げる")
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TopologicalOrder は因果エッジに沿って、原因が結果より先に来る順序でノードを返します。
+// 同時に並べられるノードはArgumentの辞書順に並べるため、同じグラフからは常に同じ順序が得られます。
+// 反論関係は順序に含めません。因果エッジに循環がある場合はエラーを返します。
+func (dg *DebateGraph) TopologicalOrder() ([]*DebateGraphNode, error) {
+	dg.mu.RLock()
+	defer dg.mu.RUnlock()
+	return dg.topologicalOrder()
+}
+
+// topologicalOrder はロックを取得せずにトポロジカル順序を返します。
+func (dg *DebateGraph) topologicalOrder() ([]*DebateGraphNode, error) {
+	inDegree := make(map[*DebateGraphNode]int, len(dg.Nodes))
+	effects := make(map[*DebateGraphNode][]*DebateGraphNode, len(dg.Nodes))
+	for _, node := range dg.Nodes {
+		inDegree[node] = len(node.Causes)
+	}
+	for _, edge := range dg.allEdges() {
+		effects[edge.Cause] = append(effects[edge.Cause], edge.Effect)
+	}
+
+	ready := make([]*DebateGraphNode, 0)
+	for _, node := range dg.Nodes {
+		if inDegree[node] == 0 {
+			ready = append(ready, node)
+		}
+	}
+
+	order := make([]*DebateGraphNode, 0, len(dg.Nodes))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return ready[i].Argument < ready[j].Argument })
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+		for _, effect := range effects[current] {
+			inDegree[effect]--
+			if inDegree[effect] == 0 {
+				ready = append(ready, effect)
+			}
+		}
+	}
+
+	if len(order) < len(dg.Nodes) {
+		cyclic := make([]string, 0)
+		for _, node := range dg.Nodes {
+			if inDegree[node] > 0 {
+				cyclic = append(cyclic, fmt.Sprintf("'%s'", node.Argument))
+			}
+		}
+		sort.Strings(cyclic)
+		return nil, fmt.Errorf("debate graph contains a causal cycle; nodes on or after the cycle: %s", strings.Join(cyclic, ", "))
+	}
+	return order, nil
+}
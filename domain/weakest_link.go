@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// 切断対象の種類
+const (
+	CutTargetNode = "node"
+	CutTargetEdge = "edge"
+)
+
+// weakestLinkEpsilon は期待値の減少がないとみなす変化量です。
+const weakestLinkEpsilon = 1e-12
+
+// WeakestLink は1つのノードまたはエッジが反論で否定された場合に失われる影響です。
+type WeakestLink struct {
+	Kind              string   `json:"kind"`                // CutTargetNode または CutTargetEdge
+	Argument          string   `json:"argument,omitempty"`  // Kind が node のとき
+	Cause             string   `json:"cause,omitempty"`     // Kind が edge のとき
+	Effect            string   `json:"effect,omitempty"`    // Kind が edge のとき
+	LostImpacts       []string `json:"lost_impacts"`        // 前提からの経路がすべて失われる影響
+	LostMagnitude     float64  `json:"lost_magnitude"`      // LostImpacts の大きさの合計
+	ExpectedValueLoss float64  `json:"expected_value_loss"` // PropagateBeliefs による期待値の合計の減少
+}
+
+// WeakestLinkAnalysis は弱点分析の結果です。
+// Links は失われる影響の大きい順に並ぶため、先頭ほど反論の優先度が高い対象です。
+type WeakestLinkAnalysis struct {
+	TotalMagnitude     float64       `json:"total_magnitude"`
+	TotalExpectedValue float64       `json:"total_expected_value"`
+	Links              []WeakestLink `json:"links"`
+}
+
+// AnalyzeWeakestLinks は、各ノードと各エッジについて、それだけを切断したときに
+// 前提 (原因を持たないノード) からの経路がすべて失われる影響を求めます。
+// 影響は ImpactNodes が返すノードです。1つでも影響を失わせるか、期待値を減らす対象のみを返し、
+// 失われる影響の大きさの合計、期待値の減少、失われる影響の数の順に大きいものから並べます。
+func AnalyzeWeakestLinks(dg *DebateGraph) (*WeakestLinkAnalysis, error) {
+	if dg == nil {
+		return nil, fmt.Errorf("cannot analyze weakest links on nil DebateGraph")
+	}
+	return readLocked(func() (*WeakestLinkAnalysis, error) { return analyzeWeakestLinks(dg) }, dg)
+}
+
+// analyzeWeakestLinks はロックを取得せずに AnalyzeWeakestLinks の解析を行います。
+func analyzeWeakestLinks(dg *DebateGraph) (*WeakestLinkAnalysis, error) {
+	order, err := dg.topologicalOrder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to order debate graph for weakest link analysis: %w", err)
+	}
+	impacts := dg.impactNodes()
+
+	baseline := totalExpectedValue(impacts, propagateProbabilities(order, nil, nil, 0))
+	analysis := &WeakestLinkAnalysis{
+		TotalExpectedValue: baseline,
+		Links:              make([]WeakestLink, 0),
+	}
+	for _, impact := range impacts {
+		analysis.TotalMagnitude += impact.ImpactMagnitude()
+	}
+
+	appendLink := func(link WeakestLink, supported map[*DebateGraphNode]bool, probabilities map[*DebateGraphNode]float64) {
+		link.LostImpacts = make([]string, 0)
+		for _, impact := range impacts {
+			if !supported[impact] {
+				link.LostImpacts = append(link.LostImpacts, impact.Argument)
+				link.LostMagnitude += impact.ImpactMagnitude()
+			}
+		}
+		link.ExpectedValueLoss = baseline - totalExpectedValue(impacts, probabilities)
+		if len(link.LostImpacts) == 0 && link.ExpectedValueLoss <= weakestLinkEpsilon {
+			return
+		}
+		analysis.Links = append(analysis.Links, link)
+	}
+
+	for _, node := range order {
+		appendLink(
+			WeakestLink{Kind: CutTargetNode, Argument: node.Argument},
+			supportedNodes(order, node, nil),
+			propagateProbabilities(order, node, nil, 0),
+		)
+	}
+	for _, edge := range dg.allEdges() {
+		appendLink(
+			WeakestLink{Kind: CutTargetEdge, Cause: edge.Cause.Argument, Effect: edge.Effect.Argument},
+			supportedNodes(order, nil, edge),
+			propagateProbabilities(order, nil, edge, 0),
+		)
+	}
+
+	sort.SliceStable(analysis.Links, func(i, j int) bool {
+		a, b := analysis.Links[i], analysis.Links[j]
+		if a.LostMagnitude != b.LostMagnitude {
+			return a.LostMagnitude > b.LostMagnitude
+		}
+		if a.ExpectedValueLoss != b.ExpectedValueLoss {
+			return a.ExpectedValueLoss > b.ExpectedValueLoss
+		}
+		return len(a.LostImpacts) > len(b.LostImpacts)
+	})
+	return analysis, nil
+}
+
+// supportedNodes は cutNode と cutEdge を取り除いたとき、前提から因果エッジをたどって到達できるノードを返します。
+// 前提は元のグラフで原因を持たないノードです。
+func supportedNodes(order []*DebateGraphNode, cutNode *DebateGraphNode, cutEdge *DebateGraphEdge) map[*DebateGraphNode]bool {
+	supported := make(map[*DebateGraphNode]bool, len(order))
+	for _, node := range order {
+		if node == cutNode {
+			continue
+		}
+		if len(node.Causes) == 0 {
+			supported[node] = true
+			continue
+		}
+		for _, edge := range node.Causes {
+			if edge != cutEdge && supported[edge.Cause] {
+				supported[node] = true
+				break
+			}
+		}
+	}
+	return supported
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeWeakestLinks(t *testing.T) {
+	dg := NewDebateGraph()
+	for _, argument := range []string{"法人税を減税する", "企業の投資が増える", "海外企業が進出する", "雇用が増える", "税収が減る"} {
+		require.NoError(t, dg.AddNode(NewDebateGraphNode(argument, false)))
+	}
+	addEdge := func(cause, effect string) {
+		causeNode, _ := dg.GetNode(cause)
+		effectNode, _ := dg.GetNode(effect)
+		require.NoError(t, dg.AddEdge(NewDebateGraphEdge(causeNode, effectNode, false)))
+	}
+	addEdge("法人税を減税する", "企業の投資が増える")
+	addEdge("法人税を減税する", "海外企業が進出する")
+	addEdge("企業の投資が増える", "雇用が増える")
+	addEdge("海外企業が進出する", "雇用が増える")
+	addEdge("法人税を減税する", "税収が減る")
+
+	analysis, err := AnalyzeWeakestLinks(dg)
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0, analysis.TotalMagnitude, 1e-9)
+
+	// 前提を否定すると全ての影響が失われる
+	require.NotEmpty(t, analysis.Links)
+	assert.Equal(t, CutTargetNode, analysis.Links[0].Kind)
+	assert.Equal(t, "法人税を減税する", analysis.Links[0].Argument)
+	assert.ElementsMatch(t, []string{"雇用が増える", "税収が減る"}, analysis.Links[0].LostImpacts)
+
+	// 2つの経路がある影響は、片方の経路を切っても失われない
+	for _, link := range analysis.Links {
+		if link.Kind == CutTargetEdge && link.Cause == "企業の投資が増える" {
+			t.Fatalf("冗長な経路上のエッジは弱点に含まれるべきではありません: %+v", link)
+		}
+	}
+}
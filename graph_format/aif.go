@@ -0,0 +1,596 @@
+package graph_format
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// AIFのノードの種類
+const (
+	AIFNodeTypeI  = "I"  // 情報ノード (主張)
+	AIFNodeTypeRA = "RA" // 推論の適用 (支持)
+	AIFNodeTypeCA = "CA" // 対立の適用 (攻撃)
+)
+
+// S-node (RA, CA) の text に使うスキーム名
+const (
+	aifSchemeInference          = "Default Inference"
+	aifSchemeConflict           = "Default Conflict"
+	aifSchemeImportanceConflict = "Importance Conflict"
+	aifSchemeUniquenessConflict = "Uniqueness Conflict"
+	aifSchemeCertaintyConflict  = "Certainty Conflict"
+	aifSchemeTurn               = "Turn"
+)
+
+// aifAnnotationScheme はアノテーションの種類と、それを表すS-nodeの対応です。
+// 根拠はI-nodeになり、このS-nodeを経由して対象のI-node (ノード) またはRA-node (エッジ) につながります。
+type aifAnnotationScheme struct {
+	NodeType string // AIFNodeTypeRA (支持) または AIFNodeTypeCA (反論)
+	Text     string
+	Field    string // domain.NodeAnnotation* または domain.EdgeAnnotation*
+}
+
+var aifNodeAnnotationSchemes = []aifAnnotationScheme{
+	{NodeType: AIFNodeTypeRA, Text: "Importance", Field: domain.NodeAnnotationImportance},
+	{NodeType: AIFNodeTypeRA, Text: "Uniqueness", Field: domain.NodeAnnotationUniqueness},
+	{NodeType: AIFNodeTypeCA, Text: "Importance Rebuttal", Field: domain.NodeAnnotationImportanceRebuttals},
+	{NodeType: AIFNodeTypeCA, Text: "Uniqueness Rebuttal", Field: domain.NodeAnnotationUniquenessRebuttals},
+}
+
+var aifEdgeAnnotationSchemes = []aifAnnotationScheme{
+	{NodeType: AIFNodeTypeRA, Text: "Certainty", Field: domain.EdgeAnnotationCertainty},
+	{NodeType: AIFNodeTypeRA, Text: "Uniqueness", Field: domain.EdgeAnnotationUniqueness},
+	{NodeType: AIFNodeTypeCA, Text: "Certainty Rebuttal", Field: domain.EdgeAnnotationCertaintyRebuttal},
+	{NodeType: AIFNodeTypeCA, Text: "Uniqueness Rebuttal", Field: domain.EdgeAnnotationUniquenessRebuttals},
+}
+
+// AIFExtension はAIFで表せないDebateGraphの情報です。他のツールは無視しても構いません。
+type AIFExtension struct {
+	IsRebuttal bool `json:"is_rebuttal,omitempty"`
+	domain.NodeMetadata
+	Magnitude    *float64            `json:"magnitude,omitempty"`
+	IntroducedIn string              `json:"introduced_in,omitempty"`
+	Probability  *float64            `json:"probability,omitempty"` // RA-node (エッジ) のとき
+	Sources      []domain.SourceSpan `json:"sources,omitempty"`
+	Evidence     *domain.Evidence    `json:"evidence,omitempty"` // 根拠のI-nodeのとき
+}
+
+// AIFNode はAIF (AIFdb のJSON形式) のノードです。
+type AIFNode struct {
+	NodeID    string        `json:"nodeID"`
+	Text      string        `json:"text"`
+	Type      string        `json:"type"`
+	Timestamp string        `json:"timestamp,omitempty"`
+	Debate    *AIFExtension `json:"debate,omitempty"`
+}
+
+// AIFEdge はAIFのノード間の有向エッジです。
+type AIFEdge struct {
+	EdgeID string `json:"edgeID"`
+	FromID string `json:"fromID"`
+	ToID   string `json:"toID"`
+}
+
+// AIFDocument はAIFdbのJSON形式の文書です。
+type AIFDocument struct {
+	Nodes     []AIFNode `json:"nodes"`
+	Edges     []AIFEdge `json:"edges"`
+	Locutions []any     `json:"locutions"`
+}
+
+// AIFFormat はDebateGraphとArgument Interchange Format (AIFdbのJSON形式) を相互に変換します。
+//
+//   - ノードはI-node、因果エッジは原因のI-nodeから結果のI-nodeへのRA-node ("Default Inference") です。
+//   - アノテーションの根拠はI-nodeになり、支持はRA-node、反論はCA-nodeを経由して対象につながります。
+//     スキーム名 ("Importance", "Certainty Rebuttal" など) でアノテーションの種類を区別します。
+//   - 反論関係はCA-nodeです。エッジへの反論はRA-nodeに、反論関係への反論は対象のCA-nodeに向かいます。
+//     ターンは "Turn" のCA-nodeで、ターンのノードから認めている各ノードに向かいます。
+//
+// AIFで表せない情報 (反論ノードかどうか、メタデータ、確率など) は各ノードの debate に格納します。
+type AIFFormat struct{}
+
+func (f *AIFFormat) ContentType() string {
+	return "application/json"
+}
+
+// aifBuilder はAIF文書のノードとエッジに連番のIDを振りながら追加します。
+type aifBuilder struct {
+	document AIFDocument
+}
+
+func (b *aifBuilder) addNode(nodeType, text string, extension *AIFExtension) string {
+	id := strconv.Itoa(len(b.document.Nodes) + 1)
+	b.document.Nodes = append(b.document.Nodes, AIFNode{NodeID: id, Text: text, Type: nodeType, Debate: extension})
+	return id
+}
+
+func (b *aifBuilder) addEdge(fromID, toID string) {
+	id := strconv.Itoa(len(b.document.Edges) + 1)
+	b.document.Edges = append(b.document.Edges, AIFEdge{EdgeID: id, FromID: fromID, ToID: toID})
+}
+
+// addAnnotations は根拠のI-nodeとS-nodeを作成し、対象のノードにつなぎます。
+func (b *aifBuilder) addAnnotations(targetID string, schemes []aifAnnotationScheme, lists [][]domain.Evidence) {
+	for i, scheme := range schemes {
+		for _, evidence := range lists[i] {
+			evidence := evidence
+			evidenceID := b.addNode(AIFNodeTypeI, evidence.Claim, &AIFExtension{Evidence: &evidence})
+			schemeID := b.addNode(scheme.NodeType, scheme.Text, nil)
+			b.addEdge(evidenceID, schemeID)
+			b.addEdge(schemeID, targetID)
+		}
+	}
+}
+
+// Export はDebateGraphをAIF JSONに変換します。IDは正規化された順序で振るため、同じグラフからは同じ文書が得られます。
+func (f *AIFFormat) Export(dg *domain.DebateGraph) (string, error) {
+	if dg == nil {
+		return "", fmt.Errorf("cannot export nil DebateGraph to AIF")
+	}
+	dg, err := snapshotGraph(dg)
+	if err != nil {
+		return "", err
+	}
+	b := &aifBuilder{document: AIFDocument{Nodes: make([]AIFNode, 0), Edges: make([]AIFEdge, 0), Locutions: make([]any, 0)}}
+
+	nodes := append([]*domain.DebateGraphNode(nil), dg.Nodes...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Argument < nodes[j].Argument })
+	nodeIDs := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		nodeIDs[node.Argument] = b.addNode(AIFNodeTypeI, node.Argument, &AIFExtension{
+			IsRebuttal:   node.IsRebuttal,
+			NodeMetadata: node.NodeMetadata,
+			Magnitude:    node.Magnitude,
+			IntroducedIn: node.IntroducedIn,
+			Sources:      node.Sources,
+		})
+	}
+
+	edges := dg.GetAllEdges()
+	edgeIDs := make(map[domain.EdgeRef]string, len(edges))
+	for _, edge := range edges {
+		id := b.addNode(AIFNodeTypeRA, aifSchemeInference, &AIFExtension{IsRebuttal: edge.IsRebuttal, Probability: edge.Probability, Sources: edge.Sources})
+		b.addEdge(nodeIDs[edge.Cause.Argument], id)
+		b.addEdge(id, nodeIDs[edge.Effect.Argument])
+		edgeIDs[domain.EdgeRef{Cause: edge.Cause.Argument, Effect: edge.Effect.Argument}] = id
+	}
+
+	for _, node := range nodes {
+		b.addAnnotations(nodeIDs[node.Argument], aifNodeAnnotationSchemes, [][]domain.Evidence{node.Importance, node.Uniqueness, node.ImportanceRebuttals, node.UniquenessRebuttals})
+	}
+	for _, edge := range edges {
+		b.addAnnotations(edgeIDs[domain.EdgeRef{Cause: edge.Cause.Argument, Effect: edge.Effect.Argument}], aifEdgeAnnotationSchemes, [][]domain.Evidence{edge.Certainty, edge.Uniqueness, edge.CertaintyRebuttal, edge.UniquenessRebuttals})
+	}
+
+	// 反論関係への反論が対象のCA-nodeを参照できるよう、入れ子の浅いものから追加する
+	relations := dg.RebuttalRelations()
+	sort.SliceStable(relations, func(i, j int) bool { return relationDepth(relations[i]) < relationDepth(relations[j]) })
+	relationIDs := make(map[string]string, len(relations))
+	for _, relation := range relations {
+		rebuttalID := nodeIDs[relation.RebuttalArgument]
+		switch relation.Kind {
+		case domain.RebuttalKindNode:
+			id := b.addNode(AIFNodeTypeCA, nodeConflictScheme(relation.RebuttalType), nil)
+			b.addEdge(rebuttalID, id)
+			b.addEdge(id, nodeIDs[relation.TargetArgument])
+			relationIDs[relation.Key()] = id
+		case domain.RebuttalKindEdge:
+			id := b.addNode(AIFNodeTypeCA, edgeConflictScheme(relation.RebuttalType), nil)
+			b.addEdge(rebuttalID, id)
+			b.addEdge(id, edgeIDs[domain.EdgeRef{Cause: relation.TargetCauseArgument, Effect: relation.TargetEffectArgument}])
+			relationIDs[relation.Key()] = id
+		case domain.RebuttalKindCounterArgument:
+			id := b.addNode(AIFNodeTypeCA, aifSchemeConflict, nil)
+			b.addEdge(rebuttalID, id)
+			b.addEdge(id, nodeIDs[relation.TargetArgument])
+			relationIDs[relation.Key()] = id
+		case domain.RebuttalKindTurnArgument:
+			id := b.addNode(AIFNodeTypeCA, aifSchemeTurn, nil)
+			b.addEdge(rebuttalID, id)
+			for _, argument := range relation.TargetCauseArguments {
+				b.addEdge(id, nodeIDs[argument])
+			}
+			relationIDs[relation.Key()] = id
+		case domain.RebuttalKindRelation:
+			targetID, exists := relationIDs[relation.TargetRelation.Key()]
+			if !exists {
+				return "", fmt.Errorf("target relation of '%s' was not exported", relation.Describe())
+			}
+			id := b.addNode(AIFNodeTypeCA, aifSchemeConflict, nil)
+			b.addEdge(rebuttalID, id)
+			b.addEdge(id, targetID)
+			relationIDs[relation.Key()] = id
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(b.document, "", "    ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal AIF document: %w", err)
+	}
+	return string(jsonData), nil
+}
+
+func relationDepth(relation domain.RebuttalRelation) int {
+	depth := 0
+	for target := relation.TargetRelation; target != nil; target = target.TargetRelation {
+		depth++
+	}
+	return depth
+}
+
+func nodeConflictScheme(rebuttalType string) string {
+	if rebuttalType == "importance" {
+		return aifSchemeImportanceConflict
+	}
+	return aifSchemeUniquenessConflict
+}
+
+func edgeConflictScheme(rebuttalType string) string {
+	if rebuttalType == "certainty" {
+		return aifSchemeCertaintyConflict
+	}
+	return aifSchemeUniquenessConflict
+}
+
+// aifImport はAIF文書の読み込み中の状態です。
+type aifImport struct {
+	dg        *domain.DebateGraph
+	nodes     map[string]*AIFNode
+	incoming  map[string][]string
+	outgoing  map[string][]string
+	arguments map[string]string                    // 主張のI-nodeのID → Argument
+	edges     map[string][]domain.EdgeRef          // RA-nodeのID → エッジ
+	relations map[string][]domain.RebuttalRelation // CA-nodeのID → 反論関係
+	visiting  map[string]bool
+}
+
+// Import はAIF JSONからDebateGraphを復元します。
+// debate の拡張がない他のツールの文書も読み込めます。その場合、対立のCA-nodeから出ているI-nodeを反論ノードとし、
+// 反論ノードへのエッジを反論エッジとみなします。同じ text のI-nodeは1つのノードにまとめます。
+func (f *AIFFormat) Import(data string) (*domain.DebateGraph, error) {
+	var document AIFDocument
+	if err := json.Unmarshal([]byte(data), &document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal AIF document: %w", err)
+	}
+
+	im := &aifImport{
+		dg:        domain.NewDebateGraph(),
+		nodes:     make(map[string]*AIFNode, len(document.Nodes)),
+		incoming:  make(map[string][]string),
+		outgoing:  make(map[string][]string),
+		arguments: make(map[string]string),
+		edges:     make(map[string][]domain.EdgeRef),
+		relations: make(map[string][]domain.RebuttalRelation),
+		visiting:  make(map[string]bool),
+	}
+	for i := range document.Nodes {
+		node := &document.Nodes[i]
+		if _, exists := im.nodes[node.NodeID]; exists {
+			return nil, fmt.Errorf("duplicate AIF node ID '%s'", node.NodeID)
+		}
+		switch node.Type {
+		case AIFNodeTypeI, AIFNodeTypeRA, AIFNodeTypeCA:
+		default:
+			// L-node や YA-node などの対話に関するノードは無視する
+			continue
+		}
+		im.nodes[node.NodeID] = node
+	}
+	for _, edge := range document.Edges {
+		_, fromExists := im.nodes[edge.FromID]
+		_, toExists := im.nodes[edge.ToID]
+		if !fromExists || !toExists {
+			continue
+		}
+		im.outgoing[edge.FromID] = append(im.outgoing[edge.FromID], edge.ToID)
+		im.incoming[edge.ToID] = append(im.incoming[edge.ToID], edge.FromID)
+	}
+
+	ids := make([]string, 0, len(im.nodes))
+	for id := range im.nodes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return lessNodeID(ids[i], ids[j]) })
+
+	// 1. 主張のI-nodeからノードを作成する
+	for _, id := range ids {
+		node := im.nodes[id]
+		if node.Type != AIFNodeTypeI || im.isEvidence(id) {
+			continue
+		}
+		if err := im.addArgument(node); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. 推論のRA-nodeから因果エッジを作成する
+	for _, id := range ids {
+		node := im.nodes[id]
+		if node.Type != AIFNodeTypeRA || annotationScheme(node) != nil {
+			continue
+		}
+		if err := im.addInference(node); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. アノテーションを追加する
+	for _, id := range ids {
+		node := im.nodes[id]
+		if node.Type == AIFNodeTypeI && im.isEvidence(id) {
+			if err := im.addEvidence(node); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 4. 対立のCA-nodeから反論関係を作成する
+	for _, id := range ids {
+		node := im.nodes[id]
+		if node.Type != AIFNodeTypeCA || annotationScheme(node) != nil {
+			continue
+		}
+		if _, err := im.addConflict(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return im.dg, nil
+}
+
+// lessNodeID は数値のIDを数値として、それ以外を文字列として比較します。
+func lessNodeID(a, b string) bool {
+	numberA, errA := strconv.Atoi(a)
+	numberB, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return numberA < numberB
+	}
+	if (errA == nil) != (errB == nil) {
+		return errA == nil
+	}
+	return a < b
+}
+
+// annotationScheme はS-nodeがアノテーションを表す場合に、その種類の候補を返します。
+// 対象がノードかエッジかで種類が決まるため、一致するスキームを全て返します。
+func annotationScheme(node *AIFNode) []aifAnnotationScheme {
+	var schemes []aifAnnotationScheme
+	for _, list := range [][]aifAnnotationScheme{aifNodeAnnotationSchemes, aifEdgeAnnotationSchemes} {
+		for _, scheme := range list {
+			if scheme.NodeType == node.Type && scheme.Text == node.Text {
+				schemes = append(schemes, scheme)
+			}
+		}
+	}
+	return schemes
+}
+
+// isEvidence はI-nodeがアノテーションの根拠であるかどうかを返します。
+func (im *aifImport) isEvidence(id string) bool {
+	if node := im.nodes[id]; node.Debate != nil && node.Debate.Evidence != nil {
+		return true
+	}
+	for _, to := range im.outgoing[id] {
+		if annotationScheme(im.nodes[to]) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (im *aifImport) addArgument(node *AIFNode) error {
+	if existing, exists := im.dg.GetNode(node.Text); exists {
+		im.arguments[node.NodeID] = existing.Argument
+		return nil
+	}
+
+	isRebuttal := false
+	if node.Debate != nil {
+		isRebuttal = node.Debate.IsRebuttal
+	} else {
+		for _, to := range im.outgoing[node.NodeID] {
+			if target := im.nodes[to]; target.Type == AIFNodeTypeCA && annotationScheme(target) == nil {
+				isRebuttal = true
+			}
+		}
+	}
+
+	graphNode := domain.NewDebateGraphNode(node.Text, isRebuttal)
+	if extension := node.Debate; extension != nil {
+		if err := extension.NodeMetadata.Validate(); err != nil {
+			return fmt.Errorf("invalid metadata for AIF node '%s': %w", node.NodeID, err)
+		}
+		graphNode.NodeMetadata = extension.NodeMetadata
+		graphNode.Magnitude = extension.Magnitude
+		graphNode.IntroducedIn = extension.IntroducedIn
+		if extension.Sources != nil {
+			graphNode.Sources = extension.Sources
+		}
+	}
+	if err := im.dg.AddNode(graphNode); err != nil {
+		return fmt.Errorf("failed to add AIF node '%s': %w", node.NodeID, err)
+	}
+	im.arguments[node.NodeID] = node.Text
+	return nil
+}
+
+// addInference はRA-nodeの各前提から各結論へのエッジを作成します。
+func (im *aifImport) addInference(node *AIFNode) error {
+	premises := make([]string, 0)
+	for _, from := range im.incoming[node.NodeID] {
+		if argument, exists := im.arguments[from]; exists {
+			premises = append(premises, argument)
+		}
+	}
+	conclusions := make([]string, 0)
+	for _, to := range im.outgoing[node.NodeID] {
+		if argument, exists := im.arguments[to]; exists {
+			conclusions = append(conclusions, argument)
+		}
+	}
+	for _, premise := range premises {
+		for _, conclusion := range conclusions {
+			if premise == conclusion {
+				continue
+			}
+			cause, _ := im.dg.GetNode(premise)
+			effect, _ := im.dg.GetNode(conclusion)
+			edge, exists := im.dg.GetEdge(premise, conclusion)
+			if !exists {
+				edge = domain.NewDebateGraphEdge(cause, effect, effect.IsRebuttal)
+				if err := im.dg.AddEdge(edge); err != nil {
+					return fmt.Errorf("failed to add edge for AIF node '%s': %w", node.NodeID, err)
+				}
+			}
+			if extension := node.Debate; extension != nil {
+				edge.IsRebuttal = extension.IsRebuttal
+				if extension.Probability != nil {
+					if *extension.Probability < 0 || *extension.Probability > 1 {
+						return fmt.Errorf("probability of AIF node '%s' must be between 0 and 1, got %v", node.NodeID, *extension.Probability)
+					}
+					edge.Probability = extension.Probability
+				}
+				if extension.Sources != nil {
+					edge.Sources = extension.Sources
+				}
+			}
+			im.edges[node.NodeID] = append(im.edges[node.NodeID], domain.EdgeRef{Cause: premise, Effect: conclusion})
+		}
+	}
+	return nil
+}
+
+// addEvidence は根拠のI-nodeを、S-nodeの先にあるノードまたはエッジのアノテーションとして追加します。
+func (im *aifImport) addEvidence(node *AIFNode) error {
+	evidence := domain.NewEvidence(node.Text)
+	if node.Debate != nil && node.Debate.Evidence != nil {
+		evidence = *node.Debate.Evidence
+	}
+	for _, schemeID := range im.outgoing[node.NodeID] {
+		schemes := annotationScheme(im.nodes[schemeID])
+		for _, targetID := range im.outgoing[schemeID] {
+			target := im.nodes[targetID]
+			for _, scheme := range schemes {
+				var err error
+				switch {
+				case target.Type == AIFNodeTypeI && isNodeAnnotationField(scheme):
+					argument, exists := im.arguments[targetID]
+					if !exists {
+						continue
+					}
+					err = im.dg.AddNodeAnnotation(argument, scheme.Field, evidence)
+				case target.Type == AIFNodeTypeRA && !isNodeAnnotationField(scheme):
+					for _, ref := range im.edges[targetID] {
+						if err = im.dg.AddEdgeAnnotation(ref.Cause, ref.Effect, scheme.Field, evidence); err != nil {
+							break
+						}
+					}
+				default:
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to add annotation from AIF node '%s': %w", node.NodeID, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isNodeAnnotationField(scheme aifAnnotationScheme) bool {
+	for _, nodeScheme := range aifNodeAnnotationSchemes {
+		if nodeScheme == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// addConflict はCA-nodeが表す反論関係を追加して返します。反論関係への反論の場合は、対象のCA-nodeを先に追加します。
+func (im *aifImport) addConflict(id string) ([]domain.RebuttalRelation, error) {
+	if relations, exists := im.relations[id]; exists {
+		return relations, nil
+	}
+	if im.visiting[id] {
+		return nil, fmt.Errorf("AIF conflict node '%s' is part of a cycle of conflicts", id)
+	}
+	im.visiting[id] = true
+	defer delete(im.visiting, id)
+
+	node := im.nodes[id]
+	rebuttals := make([]string, 0)
+	for _, from := range im.incoming[id] {
+		if argument, exists := im.arguments[from]; exists {
+			rebuttals = append(rebuttals, argument)
+		}
+	}
+
+	candidates := make([]domain.RebuttalRelation, 0)
+	if node.Text == aifSchemeTurn {
+		targets := make([]string, 0)
+		for _, to := range im.outgoing[id] {
+			if argument, exists := im.arguments[to]; exists {
+				targets = append(targets, argument)
+			}
+		}
+		sort.Strings(targets)
+		for _, rebuttal := range rebuttals {
+			candidates = append(candidates, domain.RebuttalRelation{Kind: domain.RebuttalKindTurnArgument, TargetCauseArguments: targets, RebuttalArgument: rebuttal})
+		}
+	} else {
+		for _, to := range im.outgoing[id] {
+			target := im.nodes[to]
+			for _, rebuttal := range rebuttals {
+				switch target.Type {
+				case AIFNodeTypeI:
+					argument, exists := im.arguments[to]
+					if !exists {
+						continue
+					}
+					switch node.Text {
+					case aifSchemeImportanceConflict:
+						candidates = append(candidates, domain.RebuttalRelation{Kind: domain.RebuttalKindNode, TargetArgument: argument, RebuttalType: "importance", RebuttalArgument: rebuttal})
+					case aifSchemeUniquenessConflict:
+						candidates = append(candidates, domain.RebuttalRelation{Kind: domain.RebuttalKindNode, TargetArgument: argument, RebuttalType: "uniqueness", RebuttalArgument: rebuttal})
+					default:
+						candidates = append(candidates, domain.RebuttalRelation{Kind: domain.RebuttalKindCounterArgument, TargetArgument: argument, RebuttalArgument: rebuttal})
+					}
+				case AIFNodeTypeRA:
+					rebuttalType := "certainty"
+					if node.Text == aifSchemeUniquenessConflict {
+						rebuttalType = "uniqueness"
+					}
+					for _, ref := range im.edges[to] {
+						candidates = append(candidates, domain.RebuttalRelation{Kind: domain.RebuttalKindEdge, TargetCauseArgument: ref.Cause, TargetEffectArgument: ref.Effect, RebuttalType: rebuttalType, RebuttalArgument: rebuttal})
+					}
+				case AIFNodeTypeCA:
+					if annotationScheme(target) != nil {
+						continue
+					}
+					targetRelations, err := im.addConflict(to)
+					if err != nil {
+						return nil, err
+					}
+					for _, targetRelation := range targetRelations {
+						targetRelation := targetRelation
+						candidates = append(candidates, domain.RebuttalRelation{Kind: domain.RebuttalKindRelation, TargetRelation: &targetRelation, RebuttalArgument: rebuttal})
+					}
+				}
+			}
+		}
+	}
+
+	relations := make([]domain.RebuttalRelation, 0, len(candidates))
+	for _, relation := range candidates {
+		if err := im.dg.AddRebuttalRelation(relation); err != nil {
+			return nil, fmt.Errorf("failed to add rebuttal relation for AIF node '%s': %w", id, err)
+		}
+		relations = append(relations, relation)
+	}
+	im.relations[id] = relations
+	return relations, nil
+}
@@ -0,0 +1,112 @@
+package graph_format
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+func newFormatTestGraph(t *testing.T) *domain.DebateGraph {
+	dg := domain.NewDebateGraph()
+	probability := 0.7
+	magnitude := 2.0
+
+	tax := domain.NewDebateGraphNode("法人税を減税する", false)
+	jobs := domain.NewDebateGraphNode("雇用が増える", false)
+	jobs.NodeMetadata = domain.NewImpactMetadata(domain.SideAffirmativePlan, domain.PolarityBenefit, "労働者")
+	jobs.Magnitude = &magnitude
+	jobs.IntroducedIn = "1AC"
+	savings := domain.NewDebateGraphNode("内部留保に回る", true)
+	investment := domain.NewDebateGraphNode("内部留保も投資に使われる", true)
+	deficit := domain.NewDebateGraphNode("財政赤字が増える", true)
+	for _, node := range []*domain.DebateGraphNode{tax, jobs, savings, investment, deficit} {
+		require.NoError(t, dg.AddNode(node))
+	}
+
+	edge := domain.NewDebateGraphEdge(tax, jobs, false)
+	edge.Probability = &probability
+	require.NoError(t, dg.AddEdge(edge))
+	require.NoError(t, dg.AddEdgeAnnotation(tax.Argument, jobs.Argument, domain.EdgeAnnotationCertainty, domain.Evidence{Claim: "過去の減税で雇用が増えた", Citation: "白書"}))
+	require.NoError(t, dg.AddEdgeAnnotation(tax.Argument, jobs.Argument, domain.EdgeAnnotationCertaintyRebuttal, domain.NewEvidence("景気の影響が大きい")))
+	require.NoError(t, dg.AddNodeAnnotation(jobs.Argument, domain.NodeAnnotationImportance, domain.NewEvidence("失業は生活を壊す")))
+
+	edgeRebuttal := domain.RebuttalRelation{
+		Kind: domain.RebuttalKindEdge, TargetCauseArgument: tax.Argument, TargetEffectArgument: jobs.Argument,
+		RebuttalType: "certainty", RebuttalArgument: savings.Argument,
+	}
+	require.NoError(t, dg.AddRebuttalRelation(edgeRebuttal))
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{Kind: domain.RebuttalKindRelation, TargetRelation: &edgeRebuttal, RebuttalArgument: investment.Argument}))
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{Kind: domain.RebuttalKindNode, TargetArgument: jobs.Argument, RebuttalType: "importance", RebuttalArgument: deficit.Argument}))
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{Kind: domain.RebuttalKindCounterArgument, TargetArgument: tax.Argument, RebuttalArgument: deficit.Argument}))
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{Kind: domain.RebuttalKindTurnArgument, TargetCauseArguments: []string{tax.Argument}, RebuttalArgument: deficit.Argument}))
+	return dg
+}
+
+func TestAIFRoundTrip(t *testing.T) {
+	dg := newFormatTestGraph(t)
+	format := &AIFFormat{}
+
+	exported, err := format.Export(dg)
+	require.NoError(t, err)
+	var document AIFDocument
+	require.NoError(t, json.Unmarshal([]byte(exported), &document))
+	texts := make(map[string]int)
+	for _, node := range document.Nodes {
+		texts[node.Type+":"+node.Text]++
+	}
+	assert.Equal(t, 2, texts["RA:Default Inference"]) // 減税→雇用 と ターンのエッジ
+	assert.Equal(t, 1, texts["RA:Certainty"])
+	assert.Equal(t, 1, texts["CA:Certainty Rebuttal"])
+	assert.Equal(t, 1, texts["CA:Certainty Conflict"])
+	assert.Equal(t, 1, texts["CA:Importance Conflict"])
+	assert.Equal(t, 2, texts["CA:Default Conflict"])
+	assert.Equal(t, 1, texts["CA:Turn"])
+
+	again, err := format.Export(dg)
+	require.NoError(t, err)
+	assert.Equal(t, exported, again)
+
+	imported, err := format.Import(exported)
+	require.NoError(t, err)
+	expectedJSON, err := dg.ToJSON()
+	require.NoError(t, err)
+	importedJSON, err := imported.ToJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, expectedJSON, importedJSON)
+}
+
+func TestAIFImportWithoutExtension(t *testing.T) {
+	data := `{
+		"nodes": [
+			{"nodeID": "a", "text": "A", "type": "I"},
+			{"nodeID": "b", "text": "B", "type": "I"},
+			{"nodeID": "c", "text": "C", "type": "I"},
+			{"nodeID": "l", "text": "Speaker: A", "type": "L"},
+			{"nodeID": "ra", "text": "Default Inference", "type": "RA"},
+			{"nodeID": "ca", "text": "Default Conflict", "type": "CA"}
+		],
+		"edges": [
+			{"edgeID": "1", "fromID": "a", "toID": "ra"},
+			{"edgeID": "2", "fromID": "ra", "toID": "b"},
+			{"edgeID": "3", "fromID": "c", "toID": "ca"},
+			{"edgeID": "4", "fromID": "ca", "toID": "ra"},
+			{"edgeID": "5", "fromID": "l", "toID": "a"}
+		],
+		"locutions": []
+	}`
+
+	dg, err := (&AIFFormat{}).Import(data)
+	require.NoError(t, err)
+	require.Len(t, dg.Nodes, 3)
+	c, _ := dg.GetNode("C")
+	assert.True(t, c.IsRebuttal)
+	_, exists := dg.GetEdge("A", "B")
+	assert.True(t, exists)
+	relations := dg.RebuttalRelations()
+	require.Len(t, relations, 1)
+	assert.Equal(t, domain.RebuttalKindEdge, relations[0].Kind)
+	assert.Equal(t, "certainty", relations[0].RebuttalType)
+}
@@ -0,0 +1,545 @@
+package graph_format
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// argdownStatementData は言明の行末に書くデータで、ノードのうちArgdownで表せない情報です。
+type argdownStatementData struct {
+	Argument   *string `json:"argument,omitempty"` // 本文を1行で書けない場合の正確な主張
+	IsRebuttal *bool   `json:"is_rebuttal,omitempty"`
+	domain.NodeMetadata
+	Magnitude           *float64                  `json:"magnitude,omitempty"`
+	IntroducedIn        string                    `json:"introduced_in,omitempty"`
+	Importance          []domain.Evidence         `json:"importance,omitempty"`
+	Uniqueness          []domain.Evidence         `json:"uniqueness,omitempty"`
+	ImportanceRebuttals []domain.Evidence         `json:"importance_rebuttals,omitempty"`
+	UniquenessRebuttals []domain.Evidence         `json:"uniqueness_rebuttals,omitempty"`
+	Sources             []domain.SourceSpan       `json:"sources,omitempty"`
+	Rebuttals           []domain.RebuttalRelation `json:"rebuttals,omitempty"` // この言明を反論とする反論関係
+}
+
+// argdownInferenceData は論証の行末に書くデータで、推論が表すエッジのうちArgdownで表せない情報です。
+type argdownInferenceData struct {
+	IsRebuttal          *bool               `json:"is_rebuttal,omitempty"`
+	Probability         *float64            `json:"probability,omitempty"`
+	Certainty           []domain.Evidence   `json:"certainty,omitempty"`
+	Uniqueness          []domain.Evidence   `json:"uniqueness,omitempty"`
+	CertaintyRebuttal   []domain.Evidence   `json:"certainty_rebuttal,omitempty"`
+	UniquenessRebuttals []domain.Evidence   `json:"uniqueness_rebuttals,omitempty"`
+	Sources             []domain.SourceSpan `json:"sources,omitempty"`
+}
+
+// ArgdownFormat はDebateGraph・LogicGraphとArgdownを相互に変換します。
+//
+//   - ノードは言明 [タイトル] です。主張がタイトルとして書ける場合はそのままタイトルにします。
+//   - 因果エッジは原因の言明の下の支持 (+>) です。アノテーションなどのデータを持つエッジと、
+//     エッジへの反論の対象になるエッジは、前提と結論の構造を持つ論証 <eN> として書きます。
+//   - 反論関係は反論の言明の下の攻撃 (->) で、エッジへの反論は論証へのアンダーカット (_>) です。
+//     反論関係の種類は言明のデータの rebuttals に書き、データがある言明では関係の行より優先します。
+//
+// 手書きの文書では、データのない攻撃は反対意見、論証へのアンダーカットは確実性へのエッジ反論として読み込み、
+// 攻撃やアンダーカットを行う言明を反論ノードとします。データはJSONオブジェクトの形式だけに対応します。
+type ArgdownFormat struct{}
+
+func (f *ArgdownFormat) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+// Export はDebateGraphをArgdownに変換します。言明と論証は正規化された順序で書きます。
+func (f *ArgdownFormat) Export(dg *domain.DebateGraph) (string, error) {
+	if dg == nil {
+		return "", fmt.Errorf("cannot export nil DebateGraph to Argdown")
+	}
+	dg, err := snapshotGraph(dg)
+	if err != nil {
+		return "", err
+	}
+
+	nodes := append([]*domain.DebateGraphNode(nil), dg.Nodes...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Argument < nodes[j].Argument })
+	titles := argdownStatementTitles(nodes)
+
+	relationsByRebuttal := make(map[string][]domain.RebuttalRelation)
+	undercutEdges := make(map[domain.EdgeRef]bool)
+	for _, relation := range dg.RebuttalRelations() {
+		relationsByRebuttal[relation.RebuttalArgument] = append(relationsByRebuttal[relation.RebuttalArgument], relation)
+		if relation.Kind == domain.RebuttalKindEdge {
+			undercutEdges[domain.EdgeRef{Cause: relation.TargetCauseArgument, Effect: relation.TargetEffectArgument}] = true
+		}
+	}
+
+	edges := dg.GetAllEdges()
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Cause.Argument != edges[j].Cause.Argument {
+			return edges[i].Cause.Argument < edges[j].Cause.Argument
+		}
+		return edges[i].Effect.Argument < edges[j].Effect.Argument
+	})
+	inferenceTitles := make(map[domain.EdgeRef]string)
+	plainEffects := make(map[string][]string)
+	for _, edge := range edges {
+		ref := domain.EdgeRef{Cause: edge.Cause.Argument, Effect: edge.Effect.Argument}
+		if undercutEdges[ref] || edge.IsRebuttal != edge.Effect.IsRebuttal || argdownEdgeData(edge) != nil {
+			inferenceTitles[ref] = "e" + strconv.Itoa(len(inferenceTitles)+1)
+			continue
+		}
+		plainEffects[ref.Cause] = append(plainEffects[ref.Cause], ref.Effect)
+	}
+
+	var builder strings.Builder
+	for _, node := range nodes {
+		relations := relationsByRebuttal[node.Argument]
+		data := argdownNodeData(node, relations)
+		builder.WriteString("[" + titles[node.Argument] + "]")
+		if data != nil || titles[node.Argument] != node.Argument {
+			text := node.Argument
+			if argdownNeedsExactArgument(text) {
+				text = strings.Join(strings.Fields(text), " ")
+				if data == nil {
+					data = &argdownStatementData{}
+				}
+				data.Argument = &node.Argument
+			}
+			builder.WriteString(": " + text)
+			if data != nil {
+				if err := writeArgdownData(&builder, data); err != nil {
+					return "", err
+				}
+			}
+		}
+		builder.WriteString("\n")
+
+		for _, effect := range plainEffects[node.Argument] {
+			builder.WriteString("  +> [" + titles[effect] + "]\n")
+		}
+		for _, relation := range relations {
+			switch relation.Kind {
+			case domain.RebuttalKindNode, domain.RebuttalKindCounterArgument:
+				builder.WriteString("  -> [" + titles[relation.TargetArgument] + "]\n")
+			case domain.RebuttalKindEdge:
+				builder.WriteString("  _> <" + inferenceTitles[domain.EdgeRef{Cause: relation.TargetCauseArgument, Effect: relation.TargetEffectArgument}] + ">\n")
+			case domain.RebuttalKindRelation:
+				builder.WriteString("  -> [" + titles[relation.TargetRelation.RebuttalArgument] + "]\n")
+			}
+		}
+	}
+
+	for _, edge := range edges {
+		ref := domain.EdgeRef{Cause: edge.Cause.Argument, Effect: edge.Effect.Argument}
+		title, exists := inferenceTitles[ref]
+		if !exists {
+			continue
+		}
+		builder.WriteString("\n<" + title + ">")
+		if data := argdownEdgeData(edge); data != nil || edge.IsRebuttal != edge.Effect.IsRebuttal {
+			if data == nil {
+				data = &argdownInferenceData{}
+			}
+			// 読み込み時は結果が反論ノードのエッジを反論エッジとみなすため、それと異なる場合だけ書く
+			if edge.IsRebuttal != edge.Effect.IsRebuttal {
+				isRebuttal := edge.IsRebuttal
+				data.IsRebuttal = &isRebuttal
+			}
+			builder.WriteString(":")
+			if err := writeArgdownData(&builder, data); err != nil {
+				return "", err
+			}
+		}
+		builder.WriteString("\n\n(1) [" + titles[ref.Cause] + "]\n----\n(2) [" + titles[ref.Effect] + "]\n")
+	}
+	return builder.String(), nil
+}
+
+// argdownStatementTitles は各ノードの言明のタイトルを決めます。
+// 主張をそのまま書けない場合は、他のタイトルと重ならない sN を使います。
+func argdownStatementTitles(nodes []*domain.DebateGraphNode) map[string]string {
+	titles := make(map[string]string, len(nodes))
+	used := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if argdownIsSafeTitle(node.Argument) {
+			titles[node.Argument] = node.Argument
+			used[node.Argument] = true
+		}
+	}
+	next := 1
+	for _, node := range nodes {
+		if _, exists := titles[node.Argument]; exists {
+			continue
+		}
+		for used["s"+strconv.Itoa(next)] {
+			next++
+		}
+		title := "s" + strconv.Itoa(next)
+		titles[node.Argument] = title
+		used[title] = true
+	}
+	return titles
+}
+
+func argdownIsSafeTitle(argument string) bool {
+	return argument != "" && argument == strings.TrimSpace(argument) && !strings.ContainsAny(argument, "[]{}\r\n")
+}
+
+// argdownNeedsExactArgument は主張を本文として書くと読み込み時に同じ文字列に戻らないかどうかを返します。
+func argdownNeedsExactArgument(argument string) bool {
+	return argument != strings.TrimSpace(argument) || strings.ContainsAny(argument, "{}\r\n\t")
+}
+
+func argdownNodeData(node *domain.DebateGraphNode, relations []domain.RebuttalRelation) *argdownStatementData {
+	data := &argdownStatementData{
+		NodeMetadata:        node.NodeMetadata,
+		Magnitude:           node.Magnitude,
+		IntroducedIn:        node.IntroducedIn,
+		Importance:          node.Importance,
+		Uniqueness:          node.Uniqueness,
+		ImportanceRebuttals: node.ImportanceRebuttals,
+		UniquenessRebuttals: node.UniquenessRebuttals,
+		Sources:             node.Sources,
+		Rebuttals:           relations,
+	}
+	// 読み込み時は反論関係を持つノードを反論ノードとみなすため、それと異なる場合だけ書く
+	if node.IsRebuttal != (len(relations) > 0) {
+		isRebuttal := node.IsRebuttal
+		data.IsRebuttal = &isRebuttal
+	}
+	if data.IsRebuttal == nil && data.NodeMetadata.IsZero() && data.Magnitude == nil && data.IntroducedIn == "" &&
+		len(data.Importance)+len(data.Uniqueness)+len(data.ImportanceRebuttals)+len(data.UniquenessRebuttals)+len(data.Sources)+len(data.Rebuttals) == 0 {
+		return nil
+	}
+	return data
+}
+
+func argdownEdgeData(edge *domain.DebateGraphEdge) *argdownInferenceData {
+	if edge.Probability == nil && len(edge.Certainty)+len(edge.Uniqueness)+len(edge.CertaintyRebuttal)+len(edge.UniquenessRebuttals)+len(edge.Sources) == 0 {
+		return nil
+	}
+	return &argdownInferenceData{
+		Probability:         edge.Probability,
+		Certainty:           edge.Certainty,
+		Uniqueness:          edge.Uniqueness,
+		CertaintyRebuttal:   edge.CertaintyRebuttal,
+		UniquenessRebuttals: edge.UniquenessRebuttals,
+		Sources:             edge.Sources,
+	}
+}
+
+func writeArgdownData(builder *strings.Builder, data any) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Argdown data: %w", err)
+	}
+	builder.WriteString(" ")
+	builder.Write(jsonData)
+	return nil
+}
+
+// argdownImport はArgdownの文書からDebateGraphを組み立てる途中の状態です。
+type argdownImport struct {
+	document       *argdownDocument
+	dg             *domain.DebateGraph
+	statementData  map[string]*argdownStatementData
+	argumentData   map[string]*argdownInferenceData
+	seenRelations  map[string]bool
+	typedRebuttals map[string]bool // データで反論関係を指定した言明のタイトル
+}
+
+// Import はArgdownからDebateGraphを復元します。
+// 同じ主張を持つ言明は1つのノードにまとめ、前提と結論の構造を持たない論証はその本文 (なければタイトル) のノードにします。
+func (f *ArgdownFormat) Import(data string) (*domain.DebateGraph, error) {
+	document, err := parseArgdown(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Argdown: %w", err)
+	}
+
+	im := &argdownImport{
+		document:       document,
+		dg:             domain.NewDebateGraph(),
+		statementData:  make(map[string]*argdownStatementData),
+		argumentData:   make(map[string]*argdownInferenceData),
+		seenRelations:  make(map[string]bool),
+		typedRebuttals: make(map[string]bool),
+	}
+	for _, title := range document.StatementOrder {
+		data := &argdownStatementData{}
+		if raw := document.Statements[title].Data; raw != nil {
+			if err := json.Unmarshal(raw, data); err != nil {
+				return nil, fmt.Errorf("invalid data for statement [%s]: %w", title, err)
+			}
+		}
+		im.statementData[title] = data
+		if len(data.Rebuttals) > 0 {
+			im.typedRebuttals[title] = true
+		}
+	}
+	for _, title := range document.ArgumentOrder {
+		data := &argdownInferenceData{}
+		if raw := document.Arguments[title].Data; raw != nil {
+			if err := json.Unmarshal(raw, data); err != nil {
+				return nil, fmt.Errorf("invalid data for argument <%s>: %w", title, err)
+			}
+		}
+		im.argumentData[title] = data
+	}
+
+	if err := im.addNodes(); err != nil {
+		return nil, err
+	}
+	if err := im.addEdges(); err != nil {
+		return nil, err
+	}
+	if err := im.addRebuttals(); err != nil {
+		return nil, err
+	}
+	return im.dg, nil
+}
+
+// statementArgument は言明が表すノードの主張を返します。
+func (im *argdownImport) statementArgument(title string) string {
+	if data := im.statementData[title]; data.Argument != nil {
+		return *data.Argument
+	}
+	if text := im.document.Statements[title].Text; text != "" {
+		return text
+	}
+	return title
+}
+
+// resolve は参照が表すノードの主張を返します。前提と結論の構造を持つ論証は、最後の結論を表します。
+func (im *argdownImport) resolve(ref argdownRef) string {
+	if !ref.IsArgument {
+		return im.statementArgument(ref.Title)
+	}
+	argument := im.document.Arguments[ref.Title]
+	if len(argument.Inferences) > 0 {
+		return im.statementArgument(argument.Inferences[len(argument.Inferences)-1].Conclusion)
+	}
+	if argument.Text != "" {
+		return argument.Text
+	}
+	return argument.Title
+}
+
+func (im *argdownImport) addNodes() error {
+	attackers := make(map[string]bool)
+	for _, relation := range im.document.Relations {
+		if relation.Kind != argdownRelationSupport {
+			attackers[im.resolve(relation.From)] = true
+		}
+	}
+
+	for _, title := range im.document.StatementOrder {
+		argument := im.statementArgument(title)
+		data := im.statementData[title]
+		isRebuttal := attackers[argument] || len(data.Rebuttals) > 0
+		if data.IsRebuttal != nil {
+			isRebuttal = *data.IsRebuttal
+		}
+		node, exists := im.dg.GetNode(argument)
+		if !exists {
+			node = domain.NewDebateGraphNode(argument, isRebuttal)
+			if err := im.dg.AddNode(node); err != nil {
+				return fmt.Errorf("failed to add statement [%s]: %w", title, err)
+			}
+		} else if data.IsRebuttal != nil {
+			node.IsRebuttal = isRebuttal
+		}
+
+		if err := data.NodeMetadata.Validate(); err != nil {
+			return fmt.Errorf("invalid metadata for statement [%s]: %w", title, err)
+		}
+		if !data.NodeMetadata.IsZero() {
+			node.NodeMetadata = data.NodeMetadata
+		}
+		if data.Magnitude != nil {
+			if *data.Magnitude < 0 {
+				return fmt.Errorf("magnitude of statement [%s] must not be negative, got %v", title, *data.Magnitude)
+			}
+			node.Magnitude = data.Magnitude
+		}
+		if data.IntroducedIn != "" {
+			node.IntroducedIn = data.IntroducedIn
+		}
+		if data.Sources != nil {
+			node.Sources = data.Sources
+		}
+		annotations := map[string][]domain.Evidence{
+			domain.NodeAnnotationImportance:          data.Importance,
+			domain.NodeAnnotationUniqueness:          data.Uniqueness,
+			domain.NodeAnnotationImportanceRebuttals: data.ImportanceRebuttals,
+			domain.NodeAnnotationUniquenessRebuttals: data.UniquenessRebuttals,
+		}
+		for _, annotationType := range []string{domain.NodeAnnotationImportance, domain.NodeAnnotationUniqueness, domain.NodeAnnotationImportanceRebuttals, domain.NodeAnnotationUniquenessRebuttals} {
+			for _, evidence := range annotations[annotationType] {
+				if err := im.dg.AddNodeAnnotation(argument, annotationType, evidence); err != nil {
+					return fmt.Errorf("failed to add annotation to statement [%s]: %w", title, err)
+				}
+			}
+		}
+	}
+
+	for _, title := range im.document.ArgumentOrder {
+		if len(im.document.Arguments[title].Inferences) > 0 {
+			continue
+		}
+		argument := im.resolve(argdownRef{IsArgument: true, Title: title})
+		if _, exists := im.dg.GetNode(argument); exists {
+			continue
+		}
+		if err := im.dg.AddNode(domain.NewDebateGraphNode(argument, attackers[argument])); err != nil {
+			return fmt.Errorf("failed to add argument <%s>: %w", title, err)
+		}
+	}
+	return nil
+}
+
+// addEdge はエッジを返します。存在しない場合は、結果が反論ノードであれば反論エッジとして作成します。
+func (im *argdownImport) addEdge(cause, effect string) (*domain.DebateGraphEdge, error) {
+	if edge, exists := im.dg.GetEdge(cause, effect); exists {
+		return edge, nil
+	}
+	causeNode, _ := im.dg.GetNode(cause)
+	effectNode, _ := im.dg.GetNode(effect)
+	edge := domain.NewDebateGraphEdge(causeNode, effectNode, effectNode.IsRebuttal)
+	if err := im.dg.AddEdge(edge); err != nil {
+		return nil, err
+	}
+	return edge, nil
+}
+
+func (im *argdownImport) addEdges() error {
+	for _, title := range im.document.ArgumentOrder {
+		data := im.argumentData[title]
+		if data.Probability != nil && (*data.Probability < 0 || *data.Probability > 1) {
+			return fmt.Errorf("probability of argument <%s> must be between 0 and 1, got %v", title, *data.Probability)
+		}
+		for _, inference := range im.document.Arguments[title].Inferences {
+			conclusion := im.statementArgument(inference.Conclusion)
+			for _, premise := range inference.Premises {
+				cause := im.statementArgument(premise)
+				if cause == conclusion {
+					continue
+				}
+				edge, err := im.addEdge(cause, conclusion)
+				if err != nil {
+					return fmt.Errorf("failed to add inference of argument <%s>: %w", title, err)
+				}
+				if data.IsRebuttal != nil {
+					edge.IsRebuttal = *data.IsRebuttal
+				}
+				if data.Probability != nil {
+					edge.Probability = data.Probability
+				}
+				if data.Sources != nil {
+					edge.Sources = data.Sources
+				}
+				annotations := map[string][]domain.Evidence{
+					domain.EdgeAnnotationCertainty:           data.Certainty,
+					domain.EdgeAnnotationUniqueness:          data.Uniqueness,
+					domain.EdgeAnnotationCertaintyRebuttal:   data.CertaintyRebuttal,
+					domain.EdgeAnnotationUniquenessRebuttals: data.UniquenessRebuttals,
+				}
+				for _, annotationType := range []string{domain.EdgeAnnotationCertainty, domain.EdgeAnnotationUniqueness, domain.EdgeAnnotationCertaintyRebuttal, domain.EdgeAnnotationUniquenessRebuttals} {
+					for _, evidence := range annotations[annotationType] {
+						if err := im.dg.AddEdgeAnnotation(cause, conclusion, annotationType, evidence); err != nil {
+							return fmt.Errorf("failed to add annotation to argument <%s>: %w", title, err)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for _, relation := range im.document.Relations {
+		if relation.Kind != argdownRelationSupport {
+			continue
+		}
+		cause, effect := im.resolve(relation.From), im.resolve(relation.To)
+		if cause == effect {
+			continue
+		}
+		if _, err := im.addEdge(cause, effect); err != nil {
+			return fmt.Errorf("line %d: failed to add support: %w", relation.Line, err)
+		}
+	}
+	return nil
+}
+
+func (im *argdownImport) addRelation(relation domain.RebuttalRelation) error {
+	key := relation.Key()
+	if im.seenRelations[key] {
+		return nil
+	}
+	if err := im.dg.AddRebuttalRelation(relation); err != nil {
+		return err
+	}
+	im.seenRelations[key] = true
+	return nil
+}
+
+func (im *argdownImport) addRebuttals() error {
+	// データで指定された反論関係は、反論関係への反論が対象を参照できるよう入れ子の浅いものから追加する
+	typed := make([]domain.RebuttalRelation, 0)
+	for _, title := range im.document.StatementOrder {
+		for _, relation := range im.statementData[title].Rebuttals {
+			relation.RebuttalArgument = im.statementArgument(title)
+			typed = append(typed, relation)
+		}
+	}
+	sort.SliceStable(typed, func(i, j int) bool { return relationDepth(typed[i]) < relationDepth(typed[j]) })
+	for _, relation := range typed {
+		if err := im.addRelation(relation); err != nil {
+			return fmt.Errorf("failed to add rebuttal relation '%s': %w", relation.Describe(), err)
+		}
+	}
+
+	for _, relation := range im.document.Relations {
+		if relation.Kind == argdownRelationSupport || (!relation.From.IsArgument && im.typedRebuttals[relation.From.Title]) {
+			continue
+		}
+		rebuttal := im.resolve(relation.From)
+		candidates := make([]domain.RebuttalRelation, 0)
+		target := im.document.Arguments[relation.To.Title]
+		if relation.Kind == argdownRelationUndercut && relation.To.IsArgument && len(target.Inferences) > 0 {
+			inference := target.Inferences[len(target.Inferences)-1]
+			for _, premise := range inference.Premises {
+				candidates = append(candidates, domain.RebuttalRelation{
+					Kind: domain.RebuttalKindEdge, TargetCauseArgument: im.statementArgument(premise), TargetEffectArgument: im.statementArgument(inference.Conclusion),
+					RebuttalType: "certainty", RebuttalArgument: rebuttal,
+				})
+			}
+		} else {
+			candidates = append(candidates, domain.RebuttalRelation{Kind: domain.RebuttalKindCounterArgument, TargetArgument: im.resolve(relation.To), RebuttalArgument: rebuttal})
+		}
+		for _, candidate := range candidates {
+			if err := im.addRelation(candidate); err != nil {
+				return fmt.Errorf("line %d: failed to add rebuttal relation: %w", relation.Line, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ExportLogicGraph はLogicGraphをArgdownに変換します。
+func (f *ArgdownFormat) ExportLogicGraph(lg *domain.LogicGraph) (string, error) {
+	dg, err := domain.NewDebateGraphFromLogicGraph(lg)
+	if err != nil {
+		return "", err
+	}
+	return f.Export(dg)
+}
+
+// ImportLogicGraph はArgdownからLogicGraphを復元します。攻撃・アンダーカットとデータのアノテーションは無視します。
+func (f *ArgdownFormat) ImportLogicGraph(data string) (*domain.LogicGraph, error) {
+	dg, err := f.Import(data)
+	if err != nil {
+		return nil, err
+	}
+	return dg.ToLogicGraph(), nil
+}
@@ -0,0 +1,262 @@
+package graph_format
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Argdownの関係の種類
+const (
+	argdownRelationSupport  = "support"
+	argdownRelationAttack   = "attack"
+	argdownRelationUndercut = "undercut"
+)
+
+// argdownRef は言明 ([タイトル]) または論証 (<タイトル>) への参照です。
+type argdownRef struct {
+	IsArgument bool
+	Title      string
+}
+
+// argdownStatement は同じタイトルの言明の定義をまとめたものです。
+type argdownStatement struct {
+	Title string
+	Text  string
+	Data  json.RawMessage
+}
+
+// argdownInference は前提と結論の構造 (PCS) の中の1回の推論です。
+type argdownInference struct {
+	Premises   []string // 言明のタイトル
+	Conclusion string
+}
+
+// argdownArgument は論証の定義と、その前提と結論の構造です。
+type argdownArgument struct {
+	Title      string
+	Text       string
+	Data       json.RawMessage
+	Inferences []argdownInference
+}
+
+// argdownRelation は From から To への支持・攻撃・アンダーカットです。
+type argdownRelation struct {
+	Kind string
+	From argdownRef
+	To   argdownRef
+	Line int
+}
+
+// argdownDocument はArgdownの文書を解析した結果です。定義された順序を保ちます。
+type argdownDocument struct {
+	Statements     map[string]*argdownStatement
+	StatementOrder []string
+	Arguments      map[string]*argdownArgument
+	ArgumentOrder  []string
+	Relations      []argdownRelation
+}
+
+var (
+	argdownBlockCommentPattern = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	argdownPCSPattern          = regexp.MustCompile(`^\((\d+)\)\s*(.*)$`)
+	argdownRelationPattern     = regexp.MustCompile(`^(<\+|<-|<_|\+>|->|_>|><|\+|-|_)\s+(.*)$`)
+	argdownElementPattern      = regexp.MustCompile(`^(\[([^\]]+)\]|<([^>]+)>)\s*(?::\s*(.*))?$`)
+)
+
+// argdownStackEntry は関係の親子関係をインデントからたどるためのスタックの要素です。
+type argdownStackEntry struct {
+	indent int
+	ref    argdownRef
+}
+
+// parseArgdown はArgdownのうち、言明・論証の定義と参照、支持・攻撃・アンダーカットの関係、
+// 前提と結論の構造、見出し、コメント、行末のJSON形式のデータを解析します。
+// 関係は親の行よりも深くインデントした行に書き、+ - _ (および <+ <- <_) は子から親へ、
+// +> -> _> は親から子への関係です。
+func parseArgdown(data string) (*argdownDocument, error) {
+	document := &argdownDocument{
+		Statements: make(map[string]*argdownStatement),
+		Arguments:  make(map[string]*argdownArgument),
+	}
+
+	// 行番号を保つため、ブロックコメントは改行だけを残して取り除く
+	data = argdownBlockCommentPattern.ReplaceAllStringFunc(data, func(comment string) string {
+		return strings.Repeat("\n", strings.Count(comment, "\n"))
+	})
+
+	var stack []argdownStackEntry
+	var pcs *argdownArgument // 前提と結論の構造を読み込み中の論証
+	var premises []string
+	concluding := false
+
+	for i, rawLine := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		lineNumber := i + 1
+		indent := argdownIndent(rawLine)
+		line := strings.TrimSpace(rawLine)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if indent == 0 && (line == "#" || strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "##")) {
+			stack = nil
+			pcs = nil
+			continue
+		}
+
+		if match := argdownPCSPattern.FindStringSubmatch(line); match != nil {
+			if pcs == nil {
+				return nil, fmt.Errorf("line %d: premise-conclusion structure must follow an argument", lineNumber)
+			}
+			ref, err := document.addElement(match[2], false)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			if ref.IsArgument {
+				return nil, fmt.Errorf("line %d: premise-conclusion structure must contain statements", lineNumber)
+			}
+			if concluding {
+				pcs.Inferences = append(pcs.Inferences, argdownInference{Premises: premises, Conclusion: ref.Title})
+				premises = []string{ref.Title}
+				concluding = false
+			} else {
+				premises = append(premises, ref.Title)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "--") {
+			if pcs == nil || len(premises) == 0 {
+				return nil, fmt.Errorf("line %d: inference separator must follow premises", lineNumber)
+			}
+			concluding = true
+			continue
+		}
+
+		if match := argdownRelationPattern.FindStringSubmatch(line); match != nil {
+			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: relation '%s' has no parent element", lineNumber, match[1])
+			}
+			ref, err := document.addElement(match[2], true)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			document.addRelation(match[1], stack[len(stack)-1].ref, ref, lineNumber)
+			stack = append(stack, argdownStackEntry{indent: indent, ref: ref})
+			continue
+		}
+
+		ref, err := document.addElement(line, indent == 0)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		stack = []argdownStackEntry{{indent: indent, ref: ref}}
+		pcs = nil
+		if ref.IsArgument {
+			pcs = document.Arguments[ref.Title]
+			premises = nil
+			concluding = false
+		}
+	}
+	if concluding {
+		return nil, fmt.Errorf("inference separator at end of document has no conclusion")
+	}
+	return document, nil
+}
+
+func argdownIndent(line string) int {
+	indent := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			indent++
+		case '\t':
+			indent += 4
+		default:
+			return indent
+		}
+	}
+	return indent
+}
+
+func (d *argdownDocument) addRelation(operator string, parent, child argdownRef, line int) {
+	switch operator {
+	case "+", "<+":
+		d.Relations = append(d.Relations, argdownRelation{Kind: argdownRelationSupport, From: child, To: parent, Line: line})
+	case "-", "<-", "><":
+		d.Relations = append(d.Relations, argdownRelation{Kind: argdownRelationAttack, From: child, To: parent, Line: line})
+	case "_", "<_":
+		d.Relations = append(d.Relations, argdownRelation{Kind: argdownRelationUndercut, From: child, To: parent, Line: line})
+	case "+>":
+		d.Relations = append(d.Relations, argdownRelation{Kind: argdownRelationSupport, From: parent, To: child, Line: line})
+	case "->":
+		d.Relations = append(d.Relations, argdownRelation{Kind: argdownRelationAttack, From: parent, To: child, Line: line})
+	case "_>":
+		d.Relations = append(d.Relations, argdownRelation{Kind: argdownRelationUndercut, From: parent, To: child, Line: line})
+	}
+}
+
+// addElement は言明または論証の定義・参照を解析して登録します。
+// タイトルのない文は、その文自体をタイトルとする言明です。
+func (d *argdownDocument) addElement(text string, allowArgument bool) (argdownRef, error) {
+	text, data := splitArgdownData(text)
+	title, body, isArgument := text, text, false
+	if match := argdownElementPattern.FindStringSubmatch(text); match != nil {
+		isArgument = match[3] != ""
+		title = strings.TrimSpace(match[2] + match[3])
+		body = strings.TrimSpace(match[4])
+	}
+	if title == "" {
+		return argdownRef{}, fmt.Errorf("element must have a title or text")
+	}
+
+	if isArgument {
+		if !allowArgument {
+			return argdownRef{}, fmt.Errorf("argument <%s> is not allowed here", title)
+		}
+		argument, exists := d.Arguments[title]
+		if !exists {
+			argument = &argdownArgument{Title: title}
+			d.Arguments[title] = argument
+			d.ArgumentOrder = append(d.ArgumentOrder, title)
+		}
+		if body != "" {
+			argument.Text = body
+		}
+		if data != nil {
+			argument.Data = data
+		}
+		return argdownRef{IsArgument: true, Title: title}, nil
+	}
+
+	statement, exists := d.Statements[title]
+	if !exists {
+		statement = &argdownStatement{Title: title}
+		d.Statements[title] = statement
+		d.StatementOrder = append(d.StatementOrder, title)
+	}
+	if body != "" && body != title {
+		statement.Text = body
+	}
+	if data != nil {
+		statement.Data = data
+	}
+	return argdownRef{Title: title}, nil
+}
+
+// splitArgdownData は行末のJSONオブジェクトをデータとして切り離します。
+// JSONとして読み込めない波括弧は本文の一部とみなします。
+func splitArgdownData(text string) (string, json.RawMessage) {
+	if !strings.HasSuffix(text, "}") {
+		return text, nil
+	}
+	for start := strings.LastIndex(text, "{"); start >= 0; start = strings.LastIndex(text[:start], "{") {
+		candidate := text[start:]
+		if json.Valid([]byte(candidate)) {
+			return strings.TrimSpace(text[:start]), json.RawMessage(candidate)
+		}
+	}
+	return text, nil
+}
@@ -0,0 +1,75 @@
+package graph_format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+func TestArgdownRoundTrip(t *testing.T) {
+	dg := newFormatTestGraph(t)
+	require.NoError(t, dg.AddNode(domain.NewDebateGraphNode("税収は[一時的に]減る", false)))
+	format := &ArgdownFormat{}
+
+	exported, err := format.Export(dg)
+	require.NoError(t, err)
+	assert.Contains(t, exported, "[s1]: 税収は[一時的に]減る\n")
+	assert.Contains(t, exported, "  _> <e1>\n")
+	assert.Contains(t, exported, "(1) [法人税を減税する]\n----\n(2) [雇用が増える]\n")
+
+	imported, err := format.Import(exported)
+	require.NoError(t, err)
+	expectedJSON, err := dg.ToJSON()
+	require.NoError(t, err)
+	importedJSON, err := imported.ToJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, expectedJSON, importedJSON)
+}
+
+func TestArgdownImportHandWritten(t *testing.T) {
+	data := `
+// 手書きの議論マップ
+# 減税
+
+[減税]: 法人税を減税する
+  +> [雇用]: 雇用が増える
+  <- 財政赤字が増える
+
+<雇用効果>: 減税で雇用が増える
+
+(1) [減税]
+(2) 企業の投資が増える
+--
+(3) [雇用]
+
+/* 反論
+   ここから */
+[内部留保]: 内部留保に回る
+  _> <雇用効果>
+`
+	dg, err := (&ArgdownFormat{}).Import(data)
+	require.NoError(t, err)
+	require.Len(t, dg.Nodes, 5)
+
+	for _, ref := range [][2]string{{"法人税を減税する", "雇用が増える"}, {"企業の投資が増える", "雇用が増える"}} {
+		_, exists := dg.GetEdge(ref[0], ref[1])
+		assert.True(t, exists, "%s -> %s", ref[0], ref[1])
+	}
+	deficit, _ := dg.GetNode("財政赤字が増える")
+	assert.True(t, deficit.IsRebuttal)
+
+	relations := dg.RebuttalRelations()
+	require.Len(t, relations, 3)
+	assert.Equal(t, domain.RebuttalKindCounterArgument, relations[0].Kind)
+	assert.Equal(t, "法人税を減税する", relations[0].TargetArgument)
+	for _, relation := range relations[1:] {
+		assert.Equal(t, domain.RebuttalKindEdge, relation.Kind)
+		assert.Equal(t, "雇用が増える", relation.TargetEffectArgument)
+	}
+
+	lg, err := (&ArgdownFormat{}).ImportLogicGraph(data)
+	require.NoError(t, err)
+	assert.Len(t, lg.NodeMap["雇用が増える"].Causes, 2)
+}
@@ -0,0 +1,94 @@
+package graph_format
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// CypherFormat はDebateGraphを、Neo4jなどで実行できるCypherのCREATE文に変換します。
+// 1つのグラフは1つのCREATE文で、ノードと関係は propertyGraph の表現に従います。
+type CypherFormat struct{}
+
+func (f *CypherFormat) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+// Export は1つのDebateGraphをCREATE文に変換します。graph 属性は付きません。
+func (f *CypherFormat) Export(dg *domain.DebateGraph) (string, error) {
+	graph, err := buildPropertyGraph(dg, "", "")
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	writeCypherCreate(&builder, graph)
+	return builder.String(), nil
+}
+
+// ExportBatch は複数のDebateGraphを、グラフごとのCREATE文を並べたスクリプトに変換します。
+func (f *CypherFormat) ExportBatch(graphs []NamedGraph) (string, error) {
+	if err := validateNamedGraphs(graphs); err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	for i, named := range graphs {
+		graph, err := buildPropertyGraph(named.Graph, named.Name, "")
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString("// graph: " + strings.Join(strings.Fields(named.Name), " ") + "\n")
+		writeCypherCreate(&builder, graph)
+	}
+	return builder.String(), nil
+}
+
+func writeCypherCreate(builder *strings.Builder, graph *propertyGraph) {
+	if len(graph.Nodes) == 0 {
+		return
+	}
+	builder.WriteString("CREATE\n")
+	items := make([]string, 0, len(graph.Nodes)+len(graph.Edges))
+	for _, node := range graph.Nodes {
+		items = append(items, "  ("+node.ID+":"+strings.Join(node.Labels, ":")+" "+cypherProperties(node.Properties)+")")
+	}
+	for _, edge := range graph.Edges {
+		items = append(items, "  ("+edge.Source+")-[:"+edge.Type+" "+cypherProperties(edge.Properties)+"]->("+edge.Target+")")
+	}
+	builder.WriteString(strings.Join(items, ",\n"))
+	builder.WriteString(";\n")
+}
+
+func cypherProperties(properties []property) string {
+	parts := make([]string, 0, len(properties))
+	for _, p := range properties {
+		parts = append(parts, p.Key+": "+cypherValue(p.Value))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+func cypherValue(value any) string {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		// 整数に見える値もNeo4jで浮動小数点数として読み込まれるようにする
+		text := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(text, ".eE") {
+			text += ".0"
+		}
+		return text
+	case string:
+		return cypherQuote(v)
+	}
+	return "null"
+}
+
+// cypherQuote は文字列をCypherのシングルクォートの文字列リテラルにします。
+func cypherQuote(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return "'" + replacer.Replace(text) + "'"
+}
@@ -0,0 +1,90 @@
+package graph_format
+
+import (
+	"fmt"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// 交換形式
+const (
+	FormatAIF     = "aif"
+	FormatArgdown = "argdown"
+	FormatCypher  = "cypher"
+	FormatGraphML = "graphml"
+	FormatJSONLD  = "jsonld"
+)
+
+// Exporter はDebateGraphを他のツールで読み込める形式の文字列に変換します。
+type Exporter interface {
+	Export(dg *domain.DebateGraph) (string, error)
+	// ContentType は出力をHTTPレスポンスとして返すときのContent-Typeです。
+	ContentType() string
+}
+
+// Importer は他のツールで作成された文字列からDebateGraphを復元します。
+type Importer interface {
+	Import(data string) (*domain.DebateGraph, error)
+}
+
+// LogicGraphExporter はLogicGraphも変換できるExporterです。
+type LogicGraphExporter interface {
+	ExportLogicGraph(lg *domain.LogicGraph) (string, error)
+}
+
+// LogicGraphImporter はLogicGraphも復元できるImporterです。
+type LogicGraphImporter interface {
+	ImportLogicGraph(data string) (*domain.LogicGraph, error)
+}
+
+// NewExporter は形式に対応するExporterを返します。
+func NewExporter(format string) (Exporter, error) {
+	switch format {
+	case FormatAIF:
+		return &AIFFormat{}, nil
+	case FormatArgdown:
+		return &ArgdownFormat{}, nil
+	case FormatCypher:
+		return &CypherFormat{}, nil
+	case FormatGraphML:
+		return &GraphMLFormat{}, nil
+	case FormatJSONLD:
+		return &JSONLDFormat{}, nil
+	}
+	return nil, fmt.Errorf("unknown export format '%s'", format)
+}
+
+// NewImporter は形式に対応するImporterを返します。
+func NewImporter(format string) (Importer, error) {
+	switch format {
+	case FormatAIF:
+		return &AIFFormat{}, nil
+	case FormatArgdown:
+		return &ArgdownFormat{}, nil
+	}
+	return nil, fmt.Errorf("unknown import format '%s'", format)
+}
+
+// ExportFormats は NewExporter が受け付ける形式の一覧です。
+func ExportFormats() []string {
+	return []string{FormatAIF, FormatArgdown, FormatCypher, FormatGraphML, FormatJSONLD}
+}
+
+// BatchExportFormats は NewExporter が返すExporterのうち、BatchExporter でもある形式の一覧です。
+func BatchExportFormats() []string {
+	return []string{FormatCypher, FormatGraphML}
+}
+
+// ImportFormats は NewImporter が受け付ける形式の一覧です。
+func ImportFormats() []string {
+	return []string{FormatAIF, FormatArgdown}
+}
+
+// snapshotGraph は変換中に他のゴルーチンからグラフが変更されないよう、dg の複製を返します。
+func snapshotGraph(dg *domain.DebateGraph) (*domain.DebateGraph, error) {
+	snapshot, err := dg.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot debate graph: %w", err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,126 @@
+package graph_format
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// graphMLKey はGraphMLの属性の宣言です。
+type graphMLKey struct {
+	ID   string
+	For  string // "node" または "edge"
+	Type string // "string", "boolean", "double"
+}
+
+// GraphMLFormat はDebateGraphを、GephiやyEdなどで読み込めるGraphMLに変換します。
+// ノードと関係は propertyGraph の表現に従い、ラベルは is_rebuttal、関係の種類は relation 属性で表します。
+// 複数のグラフは、IDに gN_ を付けて1つのグラフにまとめ、graph 属性で区別します。
+type GraphMLFormat struct{}
+
+func (f *GraphMLFormat) ContentType() string {
+	return "application/graphml+xml"
+}
+
+// Export は1つのDebateGraphをGraphMLに変換します。graph 属性は付きません。
+func (f *GraphMLFormat) Export(dg *domain.DebateGraph) (string, error) {
+	graph, err := buildPropertyGraph(dg, "", "")
+	if err != nil {
+		return "", err
+	}
+	return writeGraphML([]*propertyGraph{graph}), nil
+}
+
+// ExportBatch は複数のDebateGraphを1つのGraphMLに変換します。
+func (f *GraphMLFormat) ExportBatch(graphs []NamedGraph) (string, error) {
+	if err := validateNamedGraphs(graphs); err != nil {
+		return "", err
+	}
+	propertyGraphs := make([]*propertyGraph, 0, len(graphs))
+	for i, named := range graphs {
+		graph, err := buildPropertyGraph(named.Graph, named.Name, fmt.Sprintf("g%d_", i+1))
+		if err != nil {
+			return "", err
+		}
+		propertyGraphs = append(propertyGraphs, graph)
+	}
+	return writeGraphML(propertyGraphs), nil
+}
+
+func writeGraphML(graphs []*propertyGraph) string {
+	// 属性の宣言は、出現した順に1回ずつ書く
+	keys := make([]graphMLKey, 0)
+	declared := make(map[string]bool)
+	declare := func(target string, properties []property) {
+		for _, p := range properties {
+			id := target + "_" + p.Key
+			if declared[id] {
+				continue
+			}
+			declared[id] = true
+			keyType := "string"
+			switch p.Value.(type) {
+			case bool:
+				keyType = "boolean"
+			case float64:
+				keyType = "double"
+			}
+			keys = append(keys, graphMLKey{ID: id, For: target, Type: keyType})
+		}
+	}
+	for _, graph := range graphs {
+		for _, node := range graph.Nodes {
+			declare("node", node.Properties)
+		}
+		for _, edge := range graph.Edges {
+			declare("edge", append([]property{{Key: "relation", Value: edge.Type}}, edge.Properties...))
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString(xml.Header)
+	builder.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	for _, key := range keys {
+		name := strings.TrimPrefix(key.ID, key.For+"_")
+		fmt.Fprintf(&builder, `  <key id="%s" for="%s" attr.name="%s" attr.type="%s"/>`+"\n", key.ID, key.For, name, key.Type)
+	}
+	builder.WriteString(`  <graph id="DebateGraph" edgedefault="directed">` + "\n")
+	for _, graph := range graphs {
+		for _, node := range graph.Nodes {
+			fmt.Fprintf(&builder, `    <node id="%s">`+"\n", graphMLEscape(node.ID))
+			writeGraphMLData(&builder, "node", node.Properties)
+			builder.WriteString("    </node>\n")
+		}
+		for _, edge := range graph.Edges {
+			fmt.Fprintf(&builder, `    <edge id="%s" source="%s" target="%s">`+"\n", graphMLEscape(edge.ID), graphMLEscape(edge.Source), graphMLEscape(edge.Target))
+			writeGraphMLData(&builder, "edge", append([]property{{Key: "relation", Value: edge.Type}}, edge.Properties...))
+			builder.WriteString("    </edge>\n")
+		}
+	}
+	builder.WriteString("  </graph>\n</graphml>\n")
+	return builder.String()
+}
+
+func writeGraphMLData(builder *strings.Builder, target string, properties []property) {
+	for _, p := range properties {
+		value := ""
+		switch v := p.Value.(type) {
+		case bool:
+			value = strconv.FormatBool(v)
+		case float64:
+			value = strconv.FormatFloat(v, 'g', -1, 64)
+		case string:
+			value = v
+		}
+		fmt.Fprintf(builder, `      <data key="%s_%s">%s</data>`+"\n", target, p.Key, graphMLEscape(value))
+	}
+}
+
+func graphMLEscape(text string) string {
+	var builder strings.Builder
+	xml.EscapeText(&builder, []byte(text))
+	return builder.String()
+}
@@ -0,0 +1,228 @@
+package graph_format
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+//go:embed debate_context.jsonld
+var debateContextJSONLD string
+
+// JSONLDContext はJSON-LDで出力したDebateGraphの @context を含む文書を返します。
+// 出力した文書から URL で参照できるように、この文書をそのまま公開します。
+func JSONLDContext() string {
+	return debateContextJSONLD
+}
+
+type jsonldEvidence struct {
+	Type           string   `json:"@type"`
+	Text           string   `json:"text"`
+	SourceDocument string   `json:"sourceDocument,omitempty"`
+	QuotedSpan     string   `json:"quotedSpan,omitempty"`
+	Citation       string   `json:"citation,omitempty"`
+	URL            string   `json:"url,omitempty"`
+	Author         string   `json:"author,omitempty"`
+	Date           string   `json:"date,omitempty"`
+	Confidence     *float64 `json:"confidence,omitempty"`
+}
+
+type jsonldClaim struct {
+	ID                  string           `json:"@id"`
+	Type                string           `json:"@type"`
+	Text                string           `json:"text"`
+	IsRebuttal          bool             `json:"isRebuttal"`
+	Side                string           `json:"side,omitempty"`
+	Role                string           `json:"role,omitempty"`
+	Polarity            string           `json:"polarity,omitempty"`
+	Stakeholder         string           `json:"stakeholder,omitempty"`
+	Magnitude           *float64         `json:"magnitude,omitempty"`
+	IntroducedIn        string           `json:"introducedIn,omitempty"`
+	Importance          []jsonldEvidence `json:"importance,omitempty"`
+	Uniqueness          []jsonldEvidence `json:"uniqueness,omitempty"`
+	ImportanceRebuttals []jsonldEvidence `json:"importanceRebuttals,omitempty"`
+	UniquenessRebuttals []jsonldEvidence `json:"uniquenessRebuttals,omitempty"`
+}
+
+type jsonldSupport struct {
+	ID                  string           `json:"@id"`
+	Type                string           `json:"@type"`
+	Cause               string           `json:"cause"`
+	Effect              string           `json:"effect"`
+	IsRebuttal          bool             `json:"isRebuttal"`
+	Probability         *float64         `json:"probability,omitempty"`
+	Certainty           []jsonldEvidence `json:"certainty,omitempty"`
+	Uniqueness          []jsonldEvidence `json:"uniqueness,omitempty"`
+	CertaintyRebuttals  []jsonldEvidence `json:"certaintyRebuttals,omitempty"`
+	UniquenessRebuttals []jsonldEvidence `json:"uniquenessRebuttals,omitempty"`
+}
+
+type jsonldRebuttal struct {
+	ID             string   `json:"@id"`
+	Type           string   `json:"@type"`
+	RebuttalKind   string   `json:"rebuttalKind"`
+	RebuttalType   string   `json:"rebuttalType,omitempty"`
+	RebuttingClaim string   `json:"rebuttingClaim"`
+	Target         string   `json:"target,omitempty"`
+	Concedes       []string `json:"concedes,omitempty"`
+}
+
+type jsonldDocument struct {
+	Context   any              `json:"@context"`
+	Type      string           `json:"@type"`
+	Claims    []jsonldClaim    `json:"claims"`
+	Supports  []jsonldSupport  `json:"supports"`
+	Rebuttals []jsonldRebuttal `json:"rebuttals"`
+}
+
+// JSONLDFormat はDebateGraphを、debate_context.jsonld の語彙を使うJSON-LDに変換します。
+//
+// ノードは Claim、因果エッジは cause と effect を持つ CausalSupport、反論関係は RebuttalRelation で、
+// 反論関係の target は種類に応じて Claim・CausalSupport・RebuttalRelation のいずれかを指します。
+// ターンは target の代わりに concedes で認めている Claim を指します。IDは文書内の空白ノードです。
+// ContextURL が空の場合は @context を文書に埋め込み、そうでなければURLで参照します。
+type JSONLDFormat struct {
+	ContextURL string
+}
+
+func (f *JSONLDFormat) ContentType() string {
+	return "application/ld+json"
+}
+
+// Export はDebateGraphをJSON-LDに変換します。
+func (f *JSONLDFormat) Export(dg *domain.DebateGraph) (string, error) {
+	if dg == nil {
+		return "", fmt.Errorf("cannot export nil DebateGraph to JSON-LD")
+	}
+	dg, err := snapshotGraph(dg)
+	if err != nil {
+		return "", err
+	}
+
+	var context any = f.ContextURL
+	if f.ContextURL == "" {
+		var contextDocument struct {
+			Context json.RawMessage `json:"@context"`
+		}
+		if err := json.Unmarshal([]byte(debateContextJSONLD), &contextDocument); err != nil {
+			return "", fmt.Errorf("failed to read JSON-LD context: %w", err)
+		}
+		context = contextDocument.Context
+	}
+	document := jsonldDocument{
+		Context:   context,
+		Type:      "DebateGraph",
+		Claims:    make([]jsonldClaim, 0, len(dg.Nodes)),
+		Supports:  make([]jsonldSupport, 0),
+		Rebuttals: make([]jsonldRebuttal, 0),
+	}
+
+	nodes := append([]*domain.DebateGraphNode(nil), dg.Nodes...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Argument < nodes[j].Argument })
+	claimIDs := make(map[string]string, len(nodes))
+	for i, node := range nodes {
+		id := "_:claim" + strconv.Itoa(i+1)
+		claimIDs[node.Argument] = id
+		document.Claims = append(document.Claims, jsonldClaim{
+			ID:                  id,
+			Type:                "Claim",
+			Text:                node.Argument,
+			IsRebuttal:          node.IsRebuttal,
+			Side:                node.Side,
+			Role:                node.Role,
+			Polarity:            node.Polarity,
+			Stakeholder:         node.Stakeholder,
+			Magnitude:           node.Magnitude,
+			IntroducedIn:        node.IntroducedIn,
+			Importance:          jsonldEvidences(node.Importance),
+			Uniqueness:          jsonldEvidences(node.Uniqueness),
+			ImportanceRebuttals: jsonldEvidences(node.ImportanceRebuttals),
+			UniquenessRebuttals: jsonldEvidences(node.UniquenessRebuttals),
+		})
+	}
+
+	edges := dg.GetAllEdges()
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Cause.Argument != edges[j].Cause.Argument {
+			return edges[i].Cause.Argument < edges[j].Cause.Argument
+		}
+		return edges[i].Effect.Argument < edges[j].Effect.Argument
+	})
+	supportIDs := make(map[domain.EdgeRef]string, len(edges))
+	for i, edge := range edges {
+		id := "_:support" + strconv.Itoa(i+1)
+		supportIDs[domain.EdgeRef{Cause: edge.Cause.Argument, Effect: edge.Effect.Argument}] = id
+		document.Supports = append(document.Supports, jsonldSupport{
+			ID:                  id,
+			Type:                "CausalSupport",
+			Cause:               claimIDs[edge.Cause.Argument],
+			Effect:              claimIDs[edge.Effect.Argument],
+			IsRebuttal:          edge.IsRebuttal,
+			Probability:         edge.Probability,
+			Certainty:           jsonldEvidences(edge.Certainty),
+			Uniqueness:          jsonldEvidences(edge.Uniqueness),
+			CertaintyRebuttals:  jsonldEvidences(edge.CertaintyRebuttal),
+			UniquenessRebuttals: jsonldEvidences(edge.UniquenessRebuttals),
+		})
+	}
+
+	// 反論関係への反論が対象のIDを参照できるよう、入れ子の浅いものからIDを振る
+	relations := dg.RebuttalRelations()
+	sort.SliceStable(relations, func(i, j int) bool { return relationDepth(relations[i]) < relationDepth(relations[j]) })
+	relationIDs := make(map[string]string, len(relations))
+	for i, relation := range relations {
+		id := "_:rebuttal" + strconv.Itoa(i+1)
+		relationIDs[relation.Key()] = id
+		rebuttal := jsonldRebuttal{
+			ID:             id,
+			Type:           "RebuttalRelation",
+			RebuttalKind:   relation.Kind,
+			RebuttalType:   relation.RebuttalType,
+			RebuttingClaim: claimIDs[relation.RebuttalArgument],
+		}
+		switch relation.Kind {
+		case domain.RebuttalKindNode, domain.RebuttalKindCounterArgument:
+			rebuttal.Target = claimIDs[relation.TargetArgument]
+		case domain.RebuttalKindEdge:
+			rebuttal.Target = supportIDs[domain.EdgeRef{Cause: relation.TargetCauseArgument, Effect: relation.TargetEffectArgument}]
+		case domain.RebuttalKindTurnArgument:
+			for _, argument := range relation.TargetCauseArguments {
+				rebuttal.Concedes = append(rebuttal.Concedes, claimIDs[argument])
+			}
+		case domain.RebuttalKindRelation:
+			rebuttal.Target = relationIDs[relation.TargetRelation.Key()]
+		}
+		document.Rebuttals = append(document.Rebuttals, rebuttal)
+	}
+
+	jsonData, err := json.MarshalIndent(document, "", "    ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON-LD document: %w", err)
+	}
+	return string(jsonData), nil
+}
+
+func jsonldEvidences(evidences []domain.Evidence) []jsonldEvidence {
+	if len(evidences) == 0 {
+		return nil
+	}
+	result := make([]jsonldEvidence, 0, len(evidences))
+	for _, evidence := range evidences {
+		result = append(result, jsonldEvidence{
+			Type:           "Evidence",
+			Text:           evidence.Claim,
+			SourceDocument: evidence.SourceDocumentID,
+			QuotedSpan:     evidence.QuotedSpan,
+			Citation:       evidence.Citation,
+			URL:            evidence.URL,
+			Author:         evidence.Author,
+			Date:           evidence.Date,
+			Confidence:     evidence.Confidence,
+		})
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package graph_format
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLDExport(t *testing.T) {
+	dg := newFormatTestGraph(t)
+
+	exported, err := (&JSONLDFormat{}).Export(dg)
+	require.NoError(t, err)
+	var document jsonldDocument
+	require.NoError(t, json.Unmarshal([]byte(exported), &document))
+	assert.Equal(t, "DebateGraph", document.Type)
+	require.Len(t, document.Claims, 5)
+	require.Len(t, document.Supports, 2)
+	require.Len(t, document.Rebuttals, 5)
+
+	var context struct {
+		Context map[string]any `json:"@context"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(exported), &context))
+	assert.Contains(t, context.Context, "RebuttalRelation")
+
+	ids := make(map[string]bool)
+	for _, claim := range document.Claims {
+		ids[claim.ID] = true
+	}
+	for _, support := range document.Supports {
+		ids[support.ID] = true
+	}
+	for _, rebuttal := range document.Rebuttals {
+		// 反論関係への反論の対象は、それより前に出力される
+		if rebuttal.Target != "" {
+			assert.True(t, ids[rebuttal.Target], rebuttal.Target)
+		}
+		for _, conceded := range rebuttal.Concedes {
+			assert.True(t, ids[conceded], conceded)
+		}
+		ids[rebuttal.ID] = true
+	}
+	assert.Equal(t, "relation_rebuttal", document.Rebuttals[4].RebuttalKind)
+	assert.Equal(t, "白書", document.Supports[1].Certainty[0].Citation)
+
+	referenced, err := (&JSONLDFormat{ContextURL: "https://example.com/context.jsonld"}).Export(dg)
+	require.NoError(t, err)
+	assert.Contains(t, referenced, `"@context": "https://example.com/context.jsonld"`)
+}
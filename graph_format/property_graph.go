@@ -0,0 +1,213 @@
+package graph_format
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// 属性グラフのラベルと関係の種類
+const (
+	propertyLabelClaim    = "Claim"
+	propertyLabelRebuttal = "Rebuttal"
+	propertyTypeCauses    = "CAUSES"
+	propertyTypeRebuts    = "REBUTS"
+)
+
+// NamedGraph は複数のグラフをまとめて出力するときの、名前付きのDebateGraphです。
+type NamedGraph struct {
+	Name  string
+	Graph *domain.DebateGraph
+}
+
+// BatchExporter は複数のグラフを1つの文書にまとめて出力できるExporterです。
+// 各ノードとエッジには、グラフの名前が graph 属性として付きます。
+type BatchExporter interface {
+	ExportBatch(graphs []NamedGraph) (string, error)
+}
+
+// property は属性グラフの属性です。Value は string, bool, float64 のいずれかです。
+type property struct {
+	Key   string
+	Value any
+}
+
+// propertyNode は属性グラフのノードです。
+type propertyNode struct {
+	ID         string
+	Labels     []string
+	Properties []property
+}
+
+// propertyEdge は属性グラフの有向エッジです。
+type propertyEdge struct {
+	ID         string
+	Type       string // propertyTypeCauses または propertyTypeRebuts
+	Source     string
+	Target     string
+	Properties []property
+}
+
+// propertyGraph はグラフデータベースへの出力に共通する、DebateGraphの属性グラフとしての表現です。
+//
+// ノードは Claim (反論ノードは Rebuttal も付く)、因果エッジは CAUSES、反論関係は REBUTS です。
+// 関係の先にエッジや反論関係を置けないため、エッジへの反論はエッジの結果のノードに、
+// 反論関係への反論は対象の反論関係の反論ノードに向け、対象を属性に記録します。ターンは認めている各ノードに向けます。
+// アノテーションは根拠の一覧をJSON文字列にした属性です。
+type propertyGraph struct {
+	Nodes []propertyNode
+	Edges []propertyEdge
+}
+
+// buildPropertyGraph はDebateGraphを属性グラフに変換します。IDは idPrefix に正規化された順序の連番を付けたものです。
+func buildPropertyGraph(dg *domain.DebateGraph, name, idPrefix string) (*propertyGraph, error) {
+	if dg == nil {
+		return nil, fmt.Errorf("cannot export nil DebateGraph")
+	}
+	dg, err := snapshotGraph(dg)
+	if err != nil {
+		return nil, err
+	}
+	graph := &propertyGraph{Nodes: make([]propertyNode, 0, len(dg.Nodes)), Edges: make([]propertyEdge, 0)}
+
+	nodes := append([]*domain.DebateGraphNode(nil), dg.Nodes...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Argument < nodes[j].Argument })
+	nodeIDs := make(map[string]string, len(nodes))
+	for i, node := range nodes {
+		id := idPrefix + "n" + strconv.Itoa(i+1)
+		nodeIDs[node.Argument] = id
+		labels := []string{propertyLabelClaim}
+		if node.IsRebuttal {
+			labels = append(labels, propertyLabelRebuttal)
+		}
+		properties := graphProperty(name)
+		properties = append(properties, property{Key: "argument", Value: node.Argument}, property{Key: "is_rebuttal", Value: node.IsRebuttal})
+		properties = appendStringProperty(properties, "side", node.Side)
+		properties = appendStringProperty(properties, "role", node.Role)
+		properties = appendStringProperty(properties, "polarity", node.Polarity)
+		properties = appendStringProperty(properties, "stakeholder", node.Stakeholder)
+		if node.Magnitude != nil {
+			properties = append(properties, property{Key: "magnitude", Value: *node.Magnitude})
+		}
+		properties = appendStringProperty(properties, "introduced_in", node.IntroducedIn)
+		var err error
+		for _, annotation := range []struct {
+			key       string
+			evidences []domain.Evidence
+		}{
+			{domain.NodeAnnotationImportance, node.Importance},
+			{domain.NodeAnnotationUniqueness, node.Uniqueness},
+			{domain.NodeAnnotationImportanceRebuttals, node.ImportanceRebuttals},
+			{domain.NodeAnnotationUniquenessRebuttals, node.UniquenessRebuttals},
+		} {
+			if properties, err = appendEvidenceProperty(properties, annotation.key, annotation.evidences); err != nil {
+				return nil, err
+			}
+		}
+		graph.Nodes = append(graph.Nodes, propertyNode{ID: id, Labels: labels, Properties: properties})
+	}
+
+	edges := dg.GetAllEdges()
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Cause.Argument != edges[j].Cause.Argument {
+			return edges[i].Cause.Argument < edges[j].Cause.Argument
+		}
+		return edges[i].Effect.Argument < edges[j].Effect.Argument
+	})
+	for _, edge := range edges {
+		properties := graphProperty(name)
+		properties = append(properties, property{Key: "is_rebuttal", Value: edge.IsRebuttal})
+		if edge.Probability != nil {
+			properties = append(properties, property{Key: "probability", Value: *edge.Probability})
+		}
+		var err error
+		for _, annotation := range []struct {
+			key       string
+			evidences []domain.Evidence
+		}{
+			{domain.EdgeAnnotationCertainty, edge.Certainty},
+			{domain.EdgeAnnotationUniqueness, edge.Uniqueness},
+			{domain.EdgeAnnotationCertaintyRebuttal, edge.CertaintyRebuttal},
+			{domain.EdgeAnnotationUniquenessRebuttals, edge.UniquenessRebuttals},
+		} {
+			if properties, err = appendEvidenceProperty(properties, annotation.key, annotation.evidences); err != nil {
+				return nil, err
+			}
+		}
+		graph.addEdge(idPrefix, propertyTypeCauses, nodeIDs[edge.Cause.Argument], nodeIDs[edge.Effect.Argument], properties)
+	}
+
+	for _, relation := range dg.RebuttalRelations() {
+		properties := graphProperty(name)
+		properties = append(properties, property{Key: "kind", Value: relation.Kind})
+		properties = appendStringProperty(properties, "rebuttal_type", relation.RebuttalType)
+		source := nodeIDs[relation.RebuttalArgument]
+		switch relation.Kind {
+		case domain.RebuttalKindNode, domain.RebuttalKindCounterArgument:
+			graph.addEdge(idPrefix, propertyTypeRebuts, source, nodeIDs[relation.TargetArgument], properties)
+		case domain.RebuttalKindEdge:
+			properties = append(properties, property{Key: "target_cause", Value: relation.TargetCauseArgument}, property{Key: "target_effect", Value: relation.TargetEffectArgument})
+			graph.addEdge(idPrefix, propertyTypeRebuts, source, nodeIDs[relation.TargetEffectArgument], properties)
+		case domain.RebuttalKindTurnArgument:
+			for _, argument := range relation.TargetCauseArguments {
+				graph.addEdge(idPrefix, propertyTypeRebuts, source, nodeIDs[argument], properties)
+			}
+		case domain.RebuttalKindRelation:
+			target, err := json.Marshal(relation.TargetRelation)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal target relation of '%s': %w", relation.Describe(), err)
+			}
+			properties = append(properties, property{Key: "target_relation", Value: string(target)})
+			graph.addEdge(idPrefix, propertyTypeRebuts, source, nodeIDs[relation.TargetRelation.RebuttalArgument], properties)
+		}
+	}
+	return graph, nil
+}
+
+func (g *propertyGraph) addEdge(idPrefix, edgeType, source, target string, properties []property) {
+	id := idPrefix + "e" + strconv.Itoa(len(g.Edges)+1)
+	g.Edges = append(g.Edges, propertyEdge{ID: id, Type: edgeType, Source: source, Target: target, Properties: properties})
+}
+
+func graphProperty(name string) []property {
+	if name == "" {
+		return make([]property, 0)
+	}
+	return []property{{Key: "graph", Value: name}}
+}
+
+func appendStringProperty(properties []property, key, value string) []property {
+	if value == "" {
+		return properties
+	}
+	return append(properties, property{Key: key, Value: value})
+}
+
+func appendEvidenceProperty(properties []property, key string, evidences []domain.Evidence) ([]property, error) {
+	if len(evidences) == 0 {
+		return properties, nil
+	}
+	jsonData, err := json.Marshal(evidences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s evidence: %w", key, err)
+	}
+	return append(properties, property{Key: key, Value: string(jsonData)}), nil
+}
+
+// validateNamedGraphs は一括出力するグラフの名前が空でなく、重複していないことを確認します。
+func validateNamedGraphs(graphs []NamedGraph) error {
+	seen := make(map[string]bool, len(graphs))
+	for i, graph := range graphs {
+		if graph.Name == "" {
+			return fmt.Errorf("graph at index %d has no name", i)
+		}
+		if seen[graph.Name] {
+			return fmt.Errorf("duplicate graph name '%s'", graph.Name)
+		}
+		seen[graph.Name] = true
+	}
+	return nil
+}
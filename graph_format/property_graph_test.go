@@ -0,0 +1,59 @@
+package graph_format
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+func TestPropertyGraphExporters(t *testing.T) {
+	dg := newFormatTestGraph(t)
+	other := domain.NewDebateGraph()
+	require.NoError(t, other.AddNode(domain.NewDebateGraphNode("It's \\ fine", false)))
+	graphs := []NamedGraph{{Name: "tax", Graph: dg}, {Name: "other", Graph: other}}
+
+	for _, format := range BatchExportFormats() {
+		exporter, err := NewExporter(format)
+		require.NoError(t, err)
+		_, ok := exporter.(BatchExporter)
+		assert.True(t, ok, format)
+	}
+
+	cypher, err := (&CypherFormat{}).ExportBatch(graphs)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(cypher, "CREATE\n"))
+	assert.Contains(t, cypher, "(n1:Claim:Rebuttal {graph: 'tax', argument: '内部留保に回る', is_rebuttal: true})")
+	assert.Contains(t, cypher, "(n1:Claim {graph: 'other', argument: 'It\\'s \\\\ fine', is_rebuttal: false})")
+	assert.Contains(t, cypher, "magnitude: 2.0")
+	assert.Contains(t, cypher, "-[:CAUSES {graph: 'tax', is_rebuttal: false, probability: 0.7, certainty: '[{\"claim\":\"過去の減税で雇用が増えた\",\"citation\":\"白書\"}]'")
+	assert.Contains(t, cypher, "(n1)-[:REBUTS {graph: 'tax', kind: 'edge_rebuttal', rebuttal_type: 'certainty', target_cause: '法人税を減税する', target_effect: '雇用が増える'}]->(n5)")
+	assert.Contains(t, cypher, "kind: 'relation_rebuttal'")
+	assert.Contains(t, cypher, "kind: 'turn_argument'")
+
+	graphML, err := (&GraphMLFormat{}).ExportBatch(graphs)
+	require.NoError(t, err)
+	var document struct {
+		Graph struct {
+			Nodes []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(graphML), &document))
+	assert.Len(t, document.Graph.Nodes, 6)
+	assert.Equal(t, "g2_n1", document.Graph.Nodes[5].ID)
+	assert.Len(t, document.Graph.Edges, 7)
+	assert.Contains(t, graphML, `<key id="node_magnitude" for="node" attr.name="magnitude" attr.type="double"/>`)
+	assert.Contains(t, graphML, `<data key="edge_relation">REBUTS</data>`)
+
+	_, err = (&GraphMLFormat{}).ExportBatch([]NamedGraph{{Name: "tax", Graph: dg}, {Name: "tax", Graph: other}})
+	assert.Error(t, err)
+}
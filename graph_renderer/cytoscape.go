@@ -0,0 +1,117 @@
+package graph_renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// CytoscapeRenderer はCytoscape.jsの elements と style を持つJSONでグラフを出力します。
+// 反論ノード・反論エッジには rebuttal、反論関係のエッジには relation、影響ノードには impact のクラスが付きます。
+type CytoscapeRenderer struct{}
+
+type cytoscapeNodeData struct {
+	ID         string `json:"id"`
+	Label      string `json:"label"`
+	IsRebuttal bool   `json:"is_rebuttal"`
+	Role       string `json:"role,omitempty"`
+	Tooltip    string `json:"tooltip,omitempty"`
+}
+
+type cytoscapeEdgeData struct {
+	ID         string `json:"id"`
+	Source     string `json:"source"`
+	Target     string `json:"target"`
+	Kind       string `json:"kind"`
+	IsRebuttal bool   `json:"is_rebuttal"`
+	Label      string `json:"label,omitempty"`
+	Tooltip    string `json:"tooltip,omitempty"`
+}
+
+type cytoscapeElement[T any] struct {
+	Data    T      `json:"data"`
+	Classes string `json:"classes,omitempty"`
+}
+
+type cytoscapeStyle struct {
+	Selector string            `json:"selector"`
+	Style    map[string]string `json:"style"`
+}
+
+type cytoscapeDocument struct {
+	Elements struct {
+		Nodes []cytoscapeElement[cytoscapeNodeData] `json:"nodes"`
+		Edges []cytoscapeElement[cytoscapeEdgeData] `json:"edges"`
+	} `json:"elements"`
+	Style []cytoscapeStyle `json:"style"`
+}
+
+func (r *CytoscapeRenderer) RenderDebateGraph(dg *domain.DebateGraph) (string, error) {
+	graph, err := fromDebateGraph(dg)
+	if err != nil {
+		return "", err
+	}
+	return r.render(graph)
+}
+
+func (r *CytoscapeRenderer) RenderLogicGraph(lg *domain.LogicGraph) (string, error) {
+	graph, err := fromLogicGraph(lg)
+	if err != nil {
+		return "", err
+	}
+	return r.render(graph)
+}
+
+func (r *CytoscapeRenderer) ContentType() string {
+	return "application/json"
+}
+
+func (r *CytoscapeRenderer) render(graph *renderGraph) (string, error) {
+	var document cytoscapeDocument
+	document.Elements.Nodes = make([]cytoscapeElement[cytoscapeNodeData], 0, len(graph.Nodes))
+	document.Elements.Edges = make([]cytoscapeElement[cytoscapeEdgeData], 0, len(graph.Edges))
+
+	for _, node := range graph.Nodes {
+		classes := make([]string, 0)
+		if node.IsRebuttal {
+			classes = append(classes, "rebuttal")
+		}
+		if node.Role == domain.RoleImpact {
+			classes = append(classes, "impact")
+		}
+		document.Elements.Nodes = append(document.Elements.Nodes, cytoscapeElement[cytoscapeNodeData]{
+			Data:    cytoscapeNodeData{ID: node.ID, Label: node.Label, IsRebuttal: node.IsRebuttal, Role: node.Role, Tooltip: node.Tooltip},
+			Classes: strings.Join(classes, " "),
+		})
+	}
+	for _, edge := range graph.Edges {
+		classes := make([]string, 0)
+		if edge.IsRebuttal {
+			classes = append(classes, "rebuttal")
+		}
+		if edge.Kind == edgeKindRebuttal {
+			classes = append(classes, "relation")
+		}
+		document.Elements.Edges = append(document.Elements.Edges, cytoscapeElement[cytoscapeEdgeData]{
+			Data:    cytoscapeEdgeData{ID: edge.ID, Source: edge.Source, Target: edge.Target, Kind: edge.Kind, IsRebuttal: edge.IsRebuttal, Label: edge.Label, Tooltip: edge.Tooltip},
+			Classes: strings.Join(classes, " "),
+		})
+	}
+
+	document.Style = []cytoscapeStyle{
+		{Selector: "node", Style: map[string]string{"label": "data(label)", "shape": "round-rectangle", "background-color": "white", "border-width": "1", "text-wrap": "wrap", "text-valign": "center"}},
+		{Selector: "node.impact", Style: map[string]string{"background-color": impactFillColor}},
+		{Selector: "node.rebuttal", Style: map[string]string{"background-color": rebuttalFillColor, "border-color": rebuttalStrokeColor}},
+		{Selector: "edge", Style: map[string]string{"label": "data(label)", "curve-style": "bezier", "target-arrow-shape": "triangle"}},
+		{Selector: "edge.rebuttal", Style: map[string]string{"line-color": rebuttalStrokeColor, "target-arrow-color": rebuttalStrokeColor}},
+		{Selector: "edge.relation", Style: map[string]string{"line-style": "dashed", "target-arrow-shape": "tee"}},
+	}
+
+	jsonData, err := json.MarshalIndent(document, "", "    ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Cytoscape elements: %w", err)
+	}
+	return string(jsonData), nil
+}
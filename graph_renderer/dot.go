@@ -0,0 +1,90 @@
+package graph_renderer
+
+import (
+	"strings"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// 反論ノード・反論エッジの配色。各形式で共通の色を使用します。
+const (
+	rebuttalFillColor   = "#fde2e2"
+	rebuttalStrokeColor = "#c0392b"
+	impactFillColor     = "#e2f0fd"
+)
+
+// DOTRenderer はGraphvizのDOT言語でグラフを出力します。
+type DOTRenderer struct{}
+
+func (r *DOTRenderer) RenderDebateGraph(dg *domain.DebateGraph) (string, error) {
+	graph, err := fromDebateGraph(dg)
+	if err != nil {
+		return "", err
+	}
+	return r.render(graph), nil
+}
+
+func (r *DOTRenderer) RenderLogicGraph(lg *domain.LogicGraph) (string, error) {
+	graph, err := fromLogicGraph(lg)
+	if err != nil {
+		return "", err
+	}
+	return r.render(graph), nil
+}
+
+func (r *DOTRenderer) ContentType() string {
+	return "text/vnd.graphviz; charset=utf-8"
+}
+
+func (r *DOTRenderer) render(graph *renderGraph) string {
+	var builder strings.Builder
+	builder.WriteString("digraph " + graph.Name + " {\n")
+	builder.WriteString("    node [shape=box, style=\"rounded,filled\", fillcolor=\"white\"];\n")
+
+	for _, node := range graph.Nodes {
+		attributes := []string{"label=" + dotQuote(node.Label)}
+		if node.Tooltip != "" {
+			attributes = append(attributes, "tooltip="+dotQuote(node.Tooltip))
+		}
+		switch {
+		case node.IsRebuttal:
+			attributes = append(attributes, "fillcolor="+dotQuote(rebuttalFillColor), "color="+dotQuote(rebuttalStrokeColor))
+		case node.Role == domain.RoleImpact:
+			attributes = append(attributes, "fillcolor="+dotQuote(impactFillColor))
+		}
+		builder.WriteString("    " + node.ID + " [" + strings.Join(attributes, ", ") + "];\n")
+	}
+
+	for _, edge := range graph.Edges {
+		attributes := make([]string, 0)
+		if edge.Label != "" {
+			attributes = append(attributes, "label="+dotQuote(edge.Label))
+		}
+		if edge.Tooltip != "" {
+			attributes = append(attributes, "tooltip="+dotQuote(edge.Tooltip))
+		}
+		if edge.IsRebuttal {
+			attributes = append(attributes, "color="+dotQuote(rebuttalStrokeColor))
+		}
+		if edge.Kind == edgeKindRebuttal {
+			attributes = append(attributes, "style=dashed", "arrowhead=tee")
+		}
+		builder.WriteString("    " + edge.Source + " -> " + edge.Target)
+		if len(attributes) > 0 {
+			builder.WriteString(" [" + strings.Join(attributes, ", ") + "]")
+		}
+		builder.WriteString(";\n")
+	}
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// dotQuote はDOTの文字列リテラルを作成します。
+func dotQuote(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, `"`, `\"`)
+	text = strings.ReplaceAll(text, "\r\n", `\n`)
+	text = strings.ReplaceAll(text, "\n", `\n`)
+	return `"` + text + `"`
+}
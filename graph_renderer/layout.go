@@ -0,0 +1,457 @@
+package graph_renderer
+
+import (
+	"sort"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// レイアウトの寸法 (ピクセル)
+const (
+	layoutNodeWidth      = 200.0
+	layoutLineHeight     = 18.0
+	layoutNodePadding    = 10.0
+	layoutNodeGap        = 40.0 // 同じ層のノードの間隔
+	layoutDummyGap       = 20.0 // 長いエッジが通過する点と隣の要素の間隔
+	layoutLayerGap       = 60.0 // 層の間隔
+	layoutMargin         = 20.0
+	layoutLabelRunes     = 14 // ノードのラベルを折り返す文字数
+	layoutOrderingSweeps = 8  // 交差を減らすための並べ替えの反復回数
+	layoutSpacingSweeps  = 4  // 隣の層のノードに揃えるための反復回数
+)
+
+// Point はレイアウト上の座標です。
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// LayoutNode は配置されたノードです。X, Y はノードの中心の座標です。
+type LayoutNode struct {
+	ID         string   `json:"id"`
+	Label      string   `json:"label"`
+	Lines      []string `json:"lines"` // 折り返したラベル
+	IsRebuttal bool     `json:"is_rebuttal"`
+	Role       string   `json:"role,omitempty"`
+	Tooltip    string   `json:"tooltip,omitempty"`
+	Layer      int      `json:"layer"`
+	X          float64  `json:"x"`
+	Y          float64  `json:"y"`
+	Width      float64  `json:"width"`
+	Height     float64  `json:"height"`
+}
+
+// LayoutEdge は配置されたエッジです。Points は始点から終点までの折れ線です。
+type LayoutEdge struct {
+	ID         string  `json:"id"`
+	Source     string  `json:"source"`
+	Target     string  `json:"target"`
+	Kind       string  `json:"kind"`
+	IsRebuttal bool    `json:"is_rebuttal"`
+	Label      string  `json:"label,omitempty"`
+	Tooltip    string  `json:"tooltip,omitempty"`
+	Points     []Point `json:"points"`
+}
+
+// Layout はグラフ全体の配置です。Width, Height は全てのノードとエッジを含む大きさです。
+type Layout struct {
+	Width  float64      `json:"width"`
+	Height float64      `json:"height"`
+	Nodes  []LayoutNode `json:"nodes"`
+	Edges  []LayoutEdge `json:"edges"`
+}
+
+// LayoutDebateGraph はDebateGraphを、原因が上・結果が下になる層状に配置します。
+func LayoutDebateGraph(dg *domain.DebateGraph) (*Layout, error) {
+	graph, err := fromDebateGraph(dg)
+	if err != nil {
+		return nil, err
+	}
+	return layoutGraph(graph), nil
+}
+
+// LayoutLogicGraph はLogicGraphを、原因が上・結果が下になる層状に配置します。
+func LayoutLogicGraph(lg *domain.LogicGraph) (*Layout, error) {
+	graph, err := fromLogicGraph(lg)
+	if err != nil {
+		return nil, err
+	}
+	return layoutGraph(graph), nil
+}
+
+// layoutVertex はレイアウト計算中の頂点です。長いエッジが層をまたぐ点は、ノードに対応しないダミーの頂点になります。
+type layoutVertex struct {
+	node  int // graph.Nodes の添字。ダミーの場合は -1
+	layer int
+	x     float64
+	width float64
+	up    []int // 1つ上の層で隣接する頂点
+	down  []int // 1つ下の層で隣接する頂点
+}
+
+// layoutGraph は Sugiyama の方法でグラフを配置します。
+//
+//  1. 因果のエッジに沿って、各ノードを最長経路で層に割り当てる (循環がある場合は一部のエッジを無視する)
+//  2. 複数の層をまたぐエッジには、通過する層ごとにダミーの頂点を置く
+//  3. 隣の層の位置の重心で並べ替えることを上下に繰り返し、エッジの交差を減らす
+//  4. 並び順を保ったまま、隣の層で隣接する頂点の平均の位置に寄せて横の座標を決める
+//
+// 反論関係のエッジは層の割り当てには使わず、両端のノードを直線で結びます。
+func layoutGraph(graph *renderGraph) *Layout {
+	index := make(map[string]int, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		index[node.ID] = i
+	}
+	predecessors := make([][]int, len(graph.Nodes))
+	successors := make([][]int, len(graph.Nodes))
+	for _, edge := range graph.Edges {
+		source, target := index[edge.Source], index[edge.Target]
+		if edge.Kind != edgeKindCausal || source == target {
+			continue
+		}
+		predecessors[target] = append(predecessors[target], source)
+		successors[source] = append(successors[source], target)
+	}
+
+	layers := assignLayers(predecessors, successors)
+
+	// 頂点を作成し、層をまたぐ因果のエッジにダミーの頂点を置く
+	vertices := make([]*layoutVertex, 0, len(graph.Nodes))
+	for i := range graph.Nodes {
+		vertices = append(vertices, &layoutVertex{node: i, layer: layers[i], width: layoutNodeWidth})
+	}
+	edgeRoutes := make([][]int, len(graph.Edges)) // 因果のエッジが通過するダミーの頂点
+	for i, edge := range graph.Edges {
+		source, target := index[edge.Source], index[edge.Target]
+		if edge.Kind != edgeKindCausal || layers[target] <= layers[source] {
+			continue
+		}
+		previous := source
+		for layer := layers[source] + 1; layer < layers[target]; layer++ {
+			vertices = append(vertices, &layoutVertex{node: -1, layer: layer})
+			dummy := len(vertices) - 1
+			edgeRoutes[i] = append(edgeRoutes[i], dummy)
+			linkVertices(vertices, previous, dummy)
+			previous = dummy
+		}
+		linkVertices(vertices, previous, target)
+	}
+
+	layerCount := 0
+	for _, vertex := range vertices {
+		layerCount = max(layerCount, vertex.layer+1)
+	}
+	ordering := make([][]int, layerCount)
+	for i, vertex := range vertices {
+		ordering[vertex.layer] = append(ordering[vertex.layer], i)
+	}
+
+	reduceCrossings(vertices, ordering)
+	assignHorizontalPositions(vertices, ordering)
+
+	// ラベルの行数から各ノードの高さと、各層の高さを決める
+	lines := make([][]string, len(graph.Nodes))
+	layerHeights := make([]float64, layerCount)
+	for i, node := range graph.Nodes {
+		lines[i] = wrapLabel(node.Label, layoutLabelRunes)
+		layerHeights[layers[i]] = max(layerHeights[layers[i]], nodeHeight(len(lines[i])))
+	}
+	layerCenters := make([]float64, layerCount)
+	top := layoutMargin
+	for layer, height := range layerHeights {
+		layerCenters[layer] = top + height/2
+		top += height + layoutLayerGap
+	}
+
+	// 左端がマージンになるように平行移動する
+	minX, maxX := 0.0, 0.0
+	for i, vertex := range vertices {
+		left, right := vertex.x-vertex.width/2, vertex.x+vertex.width/2
+		if i == 0 || left < minX {
+			minX = left
+		}
+		if i == 0 || right > maxX {
+			maxX = right
+		}
+	}
+	for _, vertex := range vertices {
+		vertex.x += layoutMargin - minX
+	}
+
+	layout := &Layout{
+		Width:  maxX - minX + 2*layoutMargin,
+		Height: max(top-layoutLayerGap+layoutMargin, 2*layoutMargin),
+		Nodes:  make([]LayoutNode, 0, len(graph.Nodes)),
+		Edges:  make([]LayoutEdge, 0, len(graph.Edges)),
+	}
+	for i, node := range graph.Nodes {
+		layout.Nodes = append(layout.Nodes, LayoutNode{
+			ID:         node.ID,
+			Label:      node.Label,
+			Lines:      lines[i],
+			IsRebuttal: node.IsRebuttal,
+			Role:       node.Role,
+			Tooltip:    node.Tooltip,
+			Layer:      layers[i],
+			X:          vertices[i].x,
+			Y:          layerCenters[layers[i]],
+			Width:      layoutNodeWidth,
+			Height:     nodeHeight(len(lines[i])),
+		})
+	}
+	for i, edge := range graph.Edges {
+		source, target := layout.Nodes[index[edge.Source]], layout.Nodes[index[edge.Target]]
+		points := make([]Point, 0, len(edgeRoutes[i])+2)
+		if edge.Kind == edgeKindCausal && target.Layer > source.Layer {
+			points = append(points, Point{X: source.X, Y: source.Y + source.Height/2})
+			for _, dummy := range edgeRoutes[i] {
+				points = append(points, Point{X: vertices[dummy].x, Y: layerCenters[vertices[dummy].layer]})
+			}
+			points = append(points, Point{X: target.X, Y: target.Y - target.Height/2})
+		} else {
+			points = append(points, straightEdge(source, target)...)
+		}
+		layout.Edges = append(layout.Edges, LayoutEdge{
+			ID:         edge.ID,
+			Source:     edge.Source,
+			Target:     edge.Target,
+			Kind:       edge.Kind,
+			IsRebuttal: edge.IsRebuttal,
+			Label:      edge.Label,
+			Tooltip:    edge.Tooltip,
+			Points:     points,
+		})
+	}
+	return layout
+}
+
+// assignLayers は各ノードを、原因からの最長経路の長さの層に割り当てます。
+// 循環があって処理できないノードが残った場合は、添字の小さいノードから順に残りの原因を無視して割り当てます。
+func assignLayers(predecessors, successors [][]int) []int {
+	count := len(predecessors)
+	layers := make([]int, count)
+	remaining := make([]int, count)
+	done := make([]bool, count)
+	for i := range predecessors {
+		remaining[i] = len(predecessors[i])
+	}
+
+	ready := make([]int, 0)
+	for i := range remaining {
+		if remaining[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for processed := 0; processed < count; {
+		if len(ready) == 0 {
+			for i := range done {
+				if !done[i] {
+					ready = append(ready, i)
+					break
+				}
+			}
+		}
+		sort.Ints(ready)
+		current := ready[0]
+		ready = ready[1:]
+		if done[current] {
+			continue
+		}
+		done[current] = true
+		processed++
+		for _, predecessor := range predecessors[current] {
+			if done[predecessor] && predecessor != current {
+				layers[current] = max(layers[current], layers[predecessor]+1)
+			}
+		}
+		for _, successor := range successors[current] {
+			remaining[successor]--
+			if remaining[successor] == 0 && !done[successor] {
+				ready = append(ready, successor)
+			}
+		}
+	}
+	return layers
+}
+
+func linkVertices(vertices []*layoutVertex, upper, lower int) {
+	vertices[upper].down = append(vertices[upper].down, lower)
+	vertices[lower].up = append(vertices[lower].up, upper)
+}
+
+// reduceCrossings は各層の頂点を、隣の層で隣接する頂点の位置の重心の順に並べ替えます。
+// 下向きと上向きの走査を交互に繰り返し、最も交差の少なかった並び順を採用します。
+func reduceCrossings(vertices []*layoutVertex, ordering [][]int) {
+	best := cloneOrdering(ordering)
+	bestCrossings := countCrossings(vertices, ordering)
+	for sweep := 0; sweep < layoutOrderingSweeps && bestCrossings > 0; sweep++ {
+		if sweep%2 == 0 {
+			for layer := 1; layer < len(ordering); layer++ {
+				sortByBarycenter(vertices, ordering, layer, func(v *layoutVertex) []int { return v.up }, layer-1)
+			}
+		} else {
+			for layer := len(ordering) - 2; layer >= 0; layer-- {
+				sortByBarycenter(vertices, ordering, layer, func(v *layoutVertex) []int { return v.down }, layer+1)
+			}
+		}
+		if crossings := countCrossings(vertices, ordering); crossings < bestCrossings {
+			best, bestCrossings = cloneOrdering(ordering), crossings
+		}
+	}
+	for layer := range ordering {
+		copy(ordering[layer], best[layer])
+	}
+}
+
+func sortByBarycenter(vertices []*layoutVertex, ordering [][]int, layer int, neighbors func(*layoutVertex) []int, neighborLayer int) {
+	position := make(map[int]int, len(ordering[neighborLayer]))
+	for i, vertex := range ordering[neighborLayer] {
+		position[vertex] = i
+	}
+	barycenters := make(map[int]float64, len(ordering[layer]))
+	for i, vertex := range ordering[layer] {
+		adjacent := neighbors(vertices[vertex])
+		if len(adjacent) == 0 {
+			barycenters[vertex] = float64(i) // 隣接する頂点がなければ現在の位置に留める
+			continue
+		}
+		sum := 0.0
+		for _, neighbor := range adjacent {
+			sum += float64(position[neighbor])
+		}
+		barycenters[vertex] = sum / float64(len(adjacent))
+	}
+	sort.SliceStable(ordering[layer], func(i, j int) bool {
+		return barycenters[ordering[layer][i]] < barycenters[ordering[layer][j]]
+	})
+}
+
+// countCrossings は隣り合う層の間で交差するエッジの組の数を数えます。
+func countCrossings(vertices []*layoutVertex, ordering [][]int) int {
+	crossings := 0
+	for layer := 0; layer+1 < len(ordering); layer++ {
+		position := make(map[int]int, len(ordering[layer+1]))
+		for i, vertex := range ordering[layer+1] {
+			position[vertex] = i
+		}
+		type segment struct{ upper, lower int }
+		segments := make([]segment, 0)
+		for i, vertex := range ordering[layer] {
+			for _, lower := range vertices[vertex].down {
+				segments = append(segments, segment{upper: i, lower: position[lower]})
+			}
+		}
+		for i := range segments {
+			for j := i + 1; j < len(segments); j++ {
+				a, b := segments[i], segments[j]
+				if (a.upper < b.upper && a.lower > b.lower) || (a.upper > b.upper && a.lower < b.lower) {
+					crossings++
+				}
+			}
+		}
+	}
+	return crossings
+}
+
+func cloneOrdering(ordering [][]int) [][]int {
+	cloned := make([][]int, len(ordering))
+	for i, layer := range ordering {
+		cloned[i] = append([]int(nil), layer...)
+	}
+	return cloned
+}
+
+// assignHorizontalPositions は並び順を保ったまま、各頂点を隣の層で隣接する頂点の平均の位置に寄せます。
+func assignHorizontalPositions(vertices []*layoutVertex, ordering [][]int) {
+	for _, layer := range ordering {
+		x := 0.0
+		for i, vertex := range layer {
+			if i > 0 {
+				x += separation(vertices[layer[i-1]], vertices[vertex])
+			}
+			vertices[vertex].x = x
+		}
+	}
+
+	for sweep := 0; sweep < layoutSpacingSweeps; sweep++ {
+		if sweep%2 == 0 {
+			for layer := 1; layer < len(ordering); layer++ {
+				alignLayer(vertices, ordering[layer], func(v *layoutVertex) []int { return v.up })
+			}
+		} else {
+			for layer := len(ordering) - 2; layer >= 0; layer-- {
+				alignLayer(vertices, ordering[layer], func(v *layoutVertex) []int { return v.down })
+			}
+		}
+	}
+}
+
+// alignLayer は層の頂点を隣接する頂点の平均の位置に置き、重なった頂点を右へずらした後、
+// ずらした分だけ層全体を左に戻して、望ましい位置との平均のずれをなくします。
+func alignLayer(vertices []*layoutVertex, layer []int, neighbors func(*layoutVertex) []int) {
+	desired := make([]float64, len(layer))
+	for i, vertex := range layer {
+		desired[i] = vertices[vertex].x
+		if adjacent := neighbors(vertices[vertex]); len(adjacent) > 0 {
+			sum := 0.0
+			for _, neighbor := range adjacent {
+				sum += vertices[neighbor].x
+			}
+			desired[i] = sum / float64(len(adjacent))
+		}
+	}
+
+	shift := 0.0
+	for i, vertex := range layer {
+		x := desired[i]
+		if i > 0 {
+			x = max(x, vertices[layer[i-1]].x+separation(vertices[layer[i-1]], vertices[vertex]))
+		}
+		vertices[vertex].x = x
+		shift += x - desired[i]
+	}
+	if len(layer) > 0 {
+		shift /= float64(len(layer))
+		for _, vertex := range layer {
+			vertices[vertex].x -= shift
+		}
+	}
+}
+
+// separation は同じ層で隣り合う2つの頂点の中心の最小の間隔です。
+func separation(left, right *layoutVertex) float64 {
+	gap := layoutNodeGap
+	if left.node < 0 || right.node < 0 {
+		gap = layoutDummyGap
+	}
+	return (left.width+right.width)/2 + gap
+}
+
+func nodeHeight(lineCount int) float64 {
+	return float64(max(lineCount, 1))*layoutLineHeight + 2*layoutNodePadding
+}
+
+// straightEdge は層をまたがないエッジを、向かい合う辺の中点どうしを結ぶ線分で表します。
+func straightEdge(source, target LayoutNode) []Point {
+	switch {
+	case target.Y > source.Y:
+		return []Point{{X: source.X, Y: source.Y + source.Height/2}, {X: target.X, Y: target.Y - target.Height/2}}
+	case target.Y < source.Y:
+		return []Point{{X: source.X, Y: source.Y - source.Height/2}, {X: target.X, Y: target.Y + target.Height/2}}
+	case target.X > source.X:
+		return []Point{{X: source.X + source.Width/2, Y: source.Y}, {X: target.X - target.Width/2, Y: target.Y}}
+	default:
+		return []Point{{X: source.X - source.Width/2, Y: source.Y}, {X: target.X + target.Width/2, Y: target.Y}}
+	}
+}
+
+// wrapLabel はラベルを指定した文字数ごとに折り返します。
+func wrapLabel(label string, width int) []string {
+	runes := []rune(label)
+	lines := make([]string, 0, len(runes)/width+1)
+	for len(runes) > width {
+		lines = append(lines, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(lines, string(runes))
+}
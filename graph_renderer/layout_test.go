@@ -0,0 +1,64 @@
+package graph_renderer
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+func TestLayoutAndSVG(t *testing.T) {
+	lg, err := domain.NewLogicGraphFromJSON(`{"nodes":["A","B","C","D"],"edges":[["A","B"],["B","C"],["A","C"],["D","C"]]}`)
+	require.NoError(t, err)
+
+	layout, err := LayoutLogicGraph(lg)
+	require.NoError(t, err)
+
+	layers := make(map[string]int)
+	for _, node := range layout.Nodes {
+		layers[node.Label] = node.Layer
+	}
+	assert.Equal(t, map[string]int{"A": 0, "B": 1, "C": 2, "D": 0}, layers)
+
+	// 同じ層のノードは重ならない
+	for i, a := range layout.Nodes {
+		for _, b := range layout.Nodes[i+1:] {
+			if a.Layer == b.Layer {
+				assert.GreaterOrEqual(t, abs(a.X-b.X), (a.Width+b.Width)/2, "%s and %s overlap", a.Label, b.Label)
+			}
+		}
+		assert.GreaterOrEqual(t, a.X-a.Width/2, 0.0)
+		assert.LessOrEqual(t, a.X+a.Width/2, layout.Width)
+	}
+
+	// 2層をまたぐ A -> C は中間の層を通過する折れ線になる
+	for _, edge := range layout.Edges {
+		if edge.Source == "n0" && edge.Target == "n2" {
+			assert.Len(t, edge.Points, 3)
+		}
+	}
+
+	svg := WriteSVG(layout)
+	assert.True(t, strings.HasPrefix(svg, "<svg "))
+	var document struct {
+		XMLName xml.Name `xml:"svg"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(svg), &document), "SVG must be well-formed XML")
+
+	dg := newRenderTestGraph(t)
+	svg, err = (&SVGRenderer{}).RenderDebateGraph(dg)
+	require.NoError(t, err)
+	require.NoError(t, xml.Unmarshal([]byte(svg), &document))
+	assert.Contains(t, svg, "<title>雇用が&#34;増える&#34;</title>")
+	assert.Contains(t, svg, `stroke-dasharray="6,4"`)
+}
+
+func abs(value float64) float64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,97 @@
+package graph_renderer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// MermaidRenderer はMermaidのフローチャートでグラフを出力します。
+// ツールチップは click 文で付与するため、表示には Mermaid の securityLevel を 'loose' にする必要があります。
+// click 文はノードのクリック時に callback という名前の関数を呼び出すため、
+// 出力を埋め込むページでは window.callback = function (nodeId) { ... } のように関数を定義してください。
+// 定義しない場合、ツールチップは表示されますが、クリックするとブラウザのコンソールにエラーが出ます。
+type MermaidRenderer struct{}
+
+func (r *MermaidRenderer) RenderDebateGraph(dg *domain.DebateGraph) (string, error) {
+	graph, err := fromDebateGraph(dg)
+	if err != nil {
+		return "", err
+	}
+	return r.render(graph), nil
+}
+
+func (r *MermaidRenderer) RenderLogicGraph(lg *domain.LogicGraph) (string, error) {
+	graph, err := fromLogicGraph(lg)
+	if err != nil {
+		return "", err
+	}
+	return r.render(graph), nil
+}
+
+func (r *MermaidRenderer) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (r *MermaidRenderer) render(graph *renderGraph) string {
+	var builder strings.Builder
+	builder.WriteString("flowchart TD\n")
+
+	rebuttalNodes := make([]string, 0)
+	impactNodes := make([]string, 0)
+	for _, node := range graph.Nodes {
+		builder.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", node.ID, mermaidEscape(node.Label)))
+		switch {
+		case node.IsRebuttal:
+			rebuttalNodes = append(rebuttalNodes, node.ID)
+		case node.Role == domain.RoleImpact:
+			impactNodes = append(impactNodes, node.ID)
+		}
+	}
+
+	rebuttalEdges := make([]string, 0)
+	for i, edge := range graph.Edges {
+		arrow := "-->"
+		if edge.Kind == edgeKindRebuttal {
+			arrow = "-.->"
+		}
+		if edge.Label != "" {
+			arrow += "|\"" + mermaidEscape(edge.Label) + "\"|"
+		}
+		builder.WriteString(fmt.Sprintf("    %s %s %s\n", edge.Source, arrow, edge.Target))
+		if edge.IsRebuttal {
+			rebuttalEdges = append(rebuttalEdges, fmt.Sprintf("%d", i))
+		}
+	}
+
+	builder.WriteString(fmt.Sprintf("    classDef rebuttal fill:%s,stroke:%s\n", rebuttalFillColor, rebuttalStrokeColor))
+	builder.WriteString(fmt.Sprintf("    classDef impact fill:%s\n", impactFillColor))
+	if len(rebuttalNodes) > 0 {
+		builder.WriteString("    class " + strings.Join(rebuttalNodes, ",") + " rebuttal\n")
+	}
+	if len(impactNodes) > 0 {
+		builder.WriteString("    class " + strings.Join(impactNodes, ",") + " impact\n")
+	}
+	if len(rebuttalEdges) > 0 {
+		builder.WriteString(fmt.Sprintf("    linkStyle %s stroke:%s\n", strings.Join(rebuttalEdges, ","), rebuttalStrokeColor))
+	}
+
+	for _, node := range graph.Nodes {
+		if node.Tooltip != "" {
+			builder.WriteString(fmt.Sprintf("    click %s callback \"%s\"\n", node.ID, mermaidEscape(node.Tooltip)))
+		}
+	}
+	return builder.String()
+}
+
+// mermaidEscape はMermaidの引用符で囲まれた文字列に埋め込めるように、引用符・HTMLの特殊文字・改行を置き換えます。
+// Mermaidはラベルを HTML として描画するため、& < > もエンティティコードにしないとタグとして解釈されます。
+func mermaidEscape(text string) string {
+	text = strings.ReplaceAll(text, "&", "#amp;")
+	text = strings.ReplaceAll(text, "<", "#lt;")
+	text = strings.ReplaceAll(text, ">", "#gt;")
+	text = strings.ReplaceAll(text, `"`, "#quot;")
+	text = strings.ReplaceAll(text, "\r\n", " / ")
+	return strings.ReplaceAll(text, "\n", " / ")
+}
@@ -0,0 +1,229 @@
+package graph_renderer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// 出力形式
+const (
+	FormatDOT       = "dot"
+	FormatMermaid   = "mermaid"
+	FormatCytoscape = "cytoscape"
+	FormatSVG       = "svg"
+)
+
+// Renderer はDebateGraphとLogicGraphを特定の形式の文字列に変換します。
+type Renderer interface {
+	RenderDebateGraph(dg *domain.DebateGraph) (string, error)
+	RenderLogicGraph(lg *domain.LogicGraph) (string, error)
+	// ContentType は出力をHTTPレスポンスとして返すときのContent-Typeです。
+	ContentType() string
+}
+
+// NewRenderer は出力形式に対応するRendererを返します。
+func NewRenderer(format string) (Renderer, error) {
+	switch format {
+	case FormatDOT:
+		return &DOTRenderer{}, nil
+	case FormatMermaid:
+		return &MermaidRenderer{}, nil
+	case FormatCytoscape:
+		return &CytoscapeRenderer{}, nil
+	case FormatSVG:
+		return &SVGRenderer{}, nil
+	}
+	return nil, fmt.Errorf("unknown render format '%s'", format)
+}
+
+// Formats は NewRenderer が受け付ける出力形式の一覧です。
+func Formats() []string {
+	return []string{FormatDOT, FormatMermaid, FormatCytoscape, FormatSVG}
+}
+
+// 描画するエッジの種類
+const (
+	edgeKindCausal   = "causal"   // 因果関係のエッジ
+	edgeKindRebuttal = "rebuttal" // 反論ノードから反論の対象への反論関係
+)
+
+// renderNode は各形式に共通する描画用のノードです。
+type renderNode struct {
+	ID         string
+	Label      string
+	IsRebuttal bool
+	Role       string
+	Tooltip    string
+}
+
+// renderEdge は各形式に共通する描画用のエッジです。
+type renderEdge struct {
+	ID         string
+	Source     string
+	Target     string
+	Kind       string // edgeKind* のいずれか
+	IsRebuttal bool
+	Label      string
+	Tooltip    string
+}
+
+// renderGraph は各形式に共通する描画用のグラフです。ノードはArgumentの辞書順に並びます。
+type renderGraph struct {
+	Name  string
+	Nodes []renderNode
+	Edges []renderEdge
+}
+
+// fromDebateGraph はDebateGraphを描画用のグラフに変換します。
+// アノテーションはツールチップに、反論関係は反論ノードから対象への反論のエッジになります。
+// エッジへの反論はエッジの結果のノードに、反論関係への反論は対象の反論を行ったノードに向けて描画します。
+// ターンは認めているノードからターンのノードへの反論エッジとして既に含まれるため、反論関係としては描画しません。
+func fromDebateGraph(dg *domain.DebateGraph) (*renderGraph, error) {
+	if dg == nil {
+		return nil, fmt.Errorf("cannot render nil DebateGraph")
+	}
+	snapshot, err := dg.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot debate graph: %w", err)
+	}
+	dg = snapshot
+	nodes := append([]*domain.DebateGraphNode(nil), dg.Nodes...)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Argument < nodes[j].Argument
+	})
+
+	graph := &renderGraph{Name: "DebateGraph", Nodes: make([]renderNode, 0, len(nodes)), Edges: make([]renderEdge, 0)}
+	ids := make(map[string]string, len(nodes))
+	for i, node := range nodes {
+		ids[node.Argument] = fmt.Sprintf("n%d", i)
+		lines := make([]string, 0)
+		lines = appendEvidenceLines(lines, "重要性", node.Importance)
+		lines = appendEvidenceLines(lines, "固有性", node.Uniqueness)
+		lines = appendEvidenceLines(lines, "重要性への反論", node.ImportanceRebuttals)
+		lines = appendEvidenceLines(lines, "固有性への反論", node.UniquenessRebuttals)
+		if node.Magnitude != nil {
+			lines = append(lines, fmt.Sprintf("大きさ: %g", *node.Magnitude))
+		}
+		lines = appendMetadataLines(lines, node.NodeMetadata)
+		graph.Nodes = append(graph.Nodes, renderNode{
+			ID:         ids[node.Argument],
+			Label:      node.Argument,
+			IsRebuttal: node.IsRebuttal,
+			Role:       node.Role,
+			Tooltip:    strings.Join(lines, "\n"),
+		})
+	}
+
+	for _, edge := range dg.GetAllEdges() {
+		lines := make([]string, 0)
+		lines = appendEvidenceLines(lines, "確実性", edge.Certainty)
+		lines = appendEvidenceLines(lines, "固有性", edge.Uniqueness)
+		lines = appendEvidenceLines(lines, "確実性への反論", edge.CertaintyRebuttal)
+		lines = appendEvidenceLines(lines, "固有性への反論", edge.UniquenessRebuttals)
+		if edge.Probability != nil {
+			lines = append(lines, fmt.Sprintf("確率: %g", *edge.Probability))
+		}
+		graph.Edges = append(graph.Edges, renderEdge{
+			Source:     ids[edge.Cause.Argument],
+			Target:     ids[edge.Effect.Argument],
+			Kind:       edgeKindCausal,
+			IsRebuttal: edge.IsRebuttal,
+			Tooltip:    strings.Join(lines, "\n"),
+		})
+	}
+
+	for _, relation := range dg.RebuttalRelations() {
+		target := ""
+		label := relation.Kind
+		switch relation.Kind {
+		case domain.RebuttalKindNode, domain.RebuttalKindCounterArgument:
+			target = relation.TargetArgument
+		case domain.RebuttalKindEdge:
+			target = relation.TargetEffectArgument
+		case domain.RebuttalKindRelation:
+			target = relation.TargetRelation.RebuttalArgument
+		default:
+			continue
+		}
+		if relation.RebuttalType != "" {
+			label = relation.RebuttalType
+		}
+		graph.Edges = append(graph.Edges, renderEdge{
+			Source:     ids[relation.RebuttalArgument],
+			Target:     ids[target],
+			Kind:       edgeKindRebuttal,
+			IsRebuttal: true,
+			Label:      label,
+			Tooltip:    relation.Describe(),
+		})
+	}
+
+	assignEdgeIDs(graph)
+	return graph, nil
+}
+
+// fromLogicGraph はLogicGraphを描画用のグラフに変換します。ノードのメタデータはツールチップになります。
+func fromLogicGraph(lg *domain.LogicGraph) (*renderGraph, error) {
+	if lg == nil {
+		return nil, fmt.Errorf("cannot render nil LogicGraph")
+	}
+	nodes := append([]*domain.LogicGraphNode(nil), lg.Nodes...)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Argument < nodes[j].Argument
+	})
+
+	graph := &renderGraph{Name: "LogicGraph", Nodes: make([]renderNode, 0, len(nodes)), Edges: make([]renderEdge, 0)}
+	ids := make(map[string]string, len(nodes))
+	for i, node := range nodes {
+		ids[node.Argument] = fmt.Sprintf("n%d", i)
+	}
+	for _, node := range nodes {
+		lines := appendMetadataLines(make([]string, 0), node.NodeMetadata)
+		graph.Nodes = append(graph.Nodes, renderNode{
+			ID:      ids[node.Argument],
+			Label:   node.Argument,
+			Role:    node.Role,
+			Tooltip: strings.Join(lines, "\n"),
+		})
+
+		causes := append([]*domain.LogicGraphNode(nil), node.Causes...)
+		sort.Slice(causes, func(i, j int) bool {
+			return causes[i].Argument < causes[j].Argument
+		})
+		for _, cause := range causes {
+			graph.Edges = append(graph.Edges, renderEdge{
+				Source: ids[cause.Argument],
+				Target: ids[node.Argument],
+				Kind:   edgeKindCausal,
+			})
+		}
+	}
+
+	assignEdgeIDs(graph)
+	return graph, nil
+}
+
+func assignEdgeIDs(graph *renderGraph) {
+	for i := range graph.Edges {
+		graph.Edges[i].ID = fmt.Sprintf("e%d", i)
+	}
+}
+
+func appendEvidenceLines(lines []string, label string, evidences []domain.Evidence) []string {
+	for _, claim := range domain.EvidenceClaims(evidences) {
+		lines = append(lines, label+": "+claim)
+	}
+	return lines
+}
+
+func appendMetadataLines(lines []string, metadata domain.NodeMetadata) []string {
+	for _, field := range [][2]string{{"立場", metadata.Side}, {"役割", metadata.Role}, {"極性", metadata.Polarity}, {"利害関係者", metadata.Stakeholder}} {
+		if field[1] != "" {
+			lines = append(lines, field[0]+": "+field[1])
+		}
+	}
+	return lines
+}
@@ -0,0 +1,77 @@
+package graph_renderer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+func newRenderTestGraph(t *testing.T) *domain.DebateGraph {
+	dg := domain.NewDebateGraph()
+	require.NoError(t, dg.AddNode(domain.NewDebateGraphNode("法人税を減税する", false)))
+	require.NoError(t, dg.AddNode(domain.NewDebateGraphNode("雇用が\"増える\"", false)))
+	require.NoError(t, dg.AddNode(domain.NewDebateGraphNode("内部留保に回る", true)))
+	cause, _ := dg.GetNode("法人税を減税する")
+	effect, _ := dg.GetNode("雇用が\"増える\"")
+	require.NoError(t, dg.AddEdge(domain.NewDebateGraphEdge(cause, effect, false)))
+	require.NoError(t, dg.AddEdgeAnnotation(cause.Argument, effect.Argument, domain.EdgeAnnotationCertainty, domain.NewEvidence("過去の減税で雇用が増えた")))
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{
+		Kind: domain.RebuttalKindEdge, TargetCauseArgument: cause.Argument, TargetEffectArgument: effect.Argument,
+		RebuttalType: "certainty", RebuttalArgument: "内部留保に回る",
+	}))
+	return dg
+}
+
+func TestRenderers(t *testing.T) {
+	dg := newRenderTestGraph(t)
+
+	dot, err := (&DOTRenderer{}).RenderDebateGraph(dg)
+	require.NoError(t, err)
+	assert.Contains(t, dot, `n2 [label="雇用が\"増える\""`)
+	assert.Contains(t, dot, `n1 -> n2 [tooltip="確実性: 過去の減税で雇用が増えた"]`)
+	assert.Contains(t, dot, `n0 -> n2 [label="certainty"`)
+	assert.Contains(t, dot, "style=dashed")
+
+	mermaid, err := (&MermaidRenderer{}).RenderDebateGraph(dg)
+	require.NoError(t, err)
+	assert.Contains(t, mermaid, `n2["雇用が#quot;増える#quot;"]`)
+	assert.Contains(t, mermaid, `n0 -.->|"certainty"| n2`)
+	assert.Contains(t, mermaid, "class n0 rebuttal")
+	assert.Contains(t, mermaid, "linkStyle 1 stroke:")
+
+	cytoscape, err := (&CytoscapeRenderer{}).RenderDebateGraph(dg)
+	require.NoError(t, err)
+	var document cytoscapeDocument
+	require.NoError(t, json.Unmarshal([]byte(cytoscape), &document))
+	require.Len(t, document.Elements.Nodes, 3)
+	require.Len(t, document.Elements.Edges, 2)
+	assert.Equal(t, "rebuttal", document.Elements.Nodes[0].Classes)
+	assert.Equal(t, "rebuttal relation", document.Elements.Edges[1].Classes)
+
+	// ラベルやツールチップに含まれるHTMLのタグは、そのまま出力しない
+	scripted := domain.NewDebateGraph()
+	require.NoError(t, scripted.AddNode(domain.NewDebateGraphNode(`<script>alert("x")</script> & 減税`, false)))
+	require.NoError(t, scripted.AddNodeAnnotation(`<script>alert("x")</script> & 減税`, domain.NodeAnnotationImportance, domain.NewEvidence("<b>重要</b>")))
+	mermaid, err = (&MermaidRenderer{}).RenderDebateGraph(scripted)
+	require.NoError(t, err)
+	assert.Contains(t, mermaid, `n0["#lt;script#gt;alert(#quot;x#quot;)#lt;/script#gt; #amp; 減税"]`)
+	assert.Contains(t, mermaid, "#lt;b#gt;重要#lt;/b#gt;")
+	assert.NotContains(t, mermaid, "<script>")
+	assert.NotContains(t, mermaid, "<b>")
+	svg, err := (&SVGRenderer{}).RenderDebateGraph(scripted)
+	require.NoError(t, err)
+	assert.NotContains(t, svg, "<script>")
+
+	lg, err := domain.NewLogicGraphFromJSON(`{"nodes":["A","B"],"edges":[["A","B"]]}`)
+	require.NoError(t, err)
+	dot, err = (&DOTRenderer{}).RenderLogicGraph(lg)
+	require.NoError(t, err)
+	assert.Contains(t, dot, "digraph LogicGraph {")
+	assert.Contains(t, dot, "n0 -> n1;")
+
+	_, err = NewRenderer("png")
+	assert.Error(t, err)
+}
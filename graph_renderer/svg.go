@@ -0,0 +1,125 @@
+package graph_renderer
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// SVGRenderer は LayoutDebateGraph / LayoutLogicGraph の配置をもとに、グラフをSVG画像として出力します。
+// Graphviz などの外部コマンドは使用しません。ツールチップは各要素の title 要素になります。
+type SVGRenderer struct{}
+
+func (r *SVGRenderer) RenderDebateGraph(dg *domain.DebateGraph) (string, error) {
+	layout, err := LayoutDebateGraph(dg)
+	if err != nil {
+		return "", err
+	}
+	return WriteSVG(layout), nil
+}
+
+func (r *SVGRenderer) RenderLogicGraph(lg *domain.LogicGraph) (string, error) {
+	layout, err := LayoutLogicGraph(lg)
+	if err != nil {
+		return "", err
+	}
+	return WriteSVG(layout), nil
+}
+
+func (r *SVGRenderer) ContentType() string {
+	return "image/svg+xml"
+}
+
+const (
+	svgEdgeColor   = "#555555"
+	svgStrokeColor = "#333333"
+)
+
+// WriteSVG は配置済みのグラフをSVG文書に変換します。エッジはノードより先に描画されます。
+func WriteSVG(layout *Layout) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="sans-serif" font-size="13">`+"\n",
+		svgNumber(layout.Width), svgNumber(layout.Height), svgNumber(layout.Width), svgNumber(layout.Height)))
+	builder.WriteString("  <defs>\n")
+	builder.WriteString(fmt.Sprintf(`    <marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto"><path d="M0,0 L10,5 L0,10 z" fill="%s"/></marker>`+"\n", svgEdgeColor))
+	builder.WriteString(fmt.Sprintf(`    <marker id="arrow-rebuttal" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto"><path d="M0,0 L10,5 L0,10 z" fill="%s"/></marker>`+"\n", rebuttalStrokeColor))
+	builder.WriteString(fmt.Sprintf(`    <marker id="tee" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto"><path d="M8,0 L10,0 L10,10 L8,10 z" fill="%s"/></marker>`+"\n", rebuttalStrokeColor))
+	builder.WriteString("  </defs>\n")
+	builder.WriteString(`  <rect width="100%" height="100%" fill="white"/>` + "\n")
+
+	builder.WriteString(`  <g class="edges">` + "\n")
+	for _, edge := range layout.Edges {
+		color, marker, dash := svgEdgeColor, "arrow", ""
+		if edge.IsRebuttal {
+			color, marker = rebuttalStrokeColor, "arrow-rebuttal"
+		}
+		if edge.Kind == edgeKindRebuttal {
+			marker, dash = "tee", ` stroke-dasharray="6,4"`
+		}
+		points := make([]string, 0, len(edge.Points))
+		for _, point := range edge.Points {
+			points = append(points, svgNumber(point.X)+","+svgNumber(point.Y))
+		}
+		builder.WriteString(fmt.Sprintf(`    <g class="edge %s" id="%s">`, edge.Kind, svgEscape(edge.ID)))
+		if edge.Tooltip != "" {
+			builder.WriteString("<title>" + svgEscape(edge.Tooltip) + "</title>")
+		}
+		builder.WriteString(fmt.Sprintf(`<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5"%s marker-end="url(#%s)"/>`, strings.Join(points, " "), color, dash, marker))
+		if edge.Label != "" && len(edge.Points) > 0 {
+			middle := edgeMidpoint(edge.Points)
+			builder.WriteString(fmt.Sprintf(`<text x="%s" y="%s" text-anchor="middle" font-size="11" fill="%s">%s</text>`, svgNumber(middle.X), svgNumber(middle.Y-4), color, svgEscape(edge.Label)))
+		}
+		builder.WriteString("</g>\n")
+	}
+	builder.WriteString("  </g>\n")
+
+	builder.WriteString(`  <g class="nodes">` + "\n")
+	for _, node := range layout.Nodes {
+		fill, stroke := "white", svgStrokeColor
+		switch {
+		case node.IsRebuttal:
+			fill, stroke = rebuttalFillColor, rebuttalStrokeColor
+		case node.Role == domain.RoleImpact:
+			fill = impactFillColor
+		}
+		title := node.Label
+		if node.Tooltip != "" {
+			title += "\n" + node.Tooltip
+		}
+		builder.WriteString(fmt.Sprintf(`    <g class="node" id="%s"><title>%s</title>`, svgEscape(node.ID), svgEscape(title)))
+		builder.WriteString(fmt.Sprintf(`<rect x="%s" y="%s" width="%s" height="%s" rx="6" fill="%s" stroke="%s"/>`,
+			svgNumber(node.X-node.Width/2), svgNumber(node.Y-node.Height/2), svgNumber(node.Width), svgNumber(node.Height), fill, stroke))
+		firstLine := node.Y - float64(len(node.Lines)-1)*layoutLineHeight/2
+		builder.WriteString(fmt.Sprintf(`<text x="%s" y="%s" text-anchor="middle" dominant-baseline="middle">`, svgNumber(node.X), svgNumber(firstLine)))
+		for i, line := range node.Lines {
+			if i == 0 {
+				builder.WriteString(fmt.Sprintf(`<tspan x="%s">%s</tspan>`, svgNumber(node.X), svgEscape(line)))
+			} else {
+				builder.WriteString(fmt.Sprintf(`<tspan x="%s" dy="%s">%s</tspan>`, svgNumber(node.X), svgNumber(layoutLineHeight), svgEscape(line)))
+			}
+		}
+		builder.WriteString("</text></g>\n")
+	}
+	builder.WriteString("  </g>\n")
+	builder.WriteString("</svg>\n")
+	return builder.String()
+}
+
+// edgeMidpoint は折れ線の中央の線分の中点を返します。
+func edgeMidpoint(points []Point) Point {
+	if len(points) == 1 {
+		return points[0]
+	}
+	i := (len(points) - 1) / 2
+	return Point{X: (points[i].X + points[i+1].X) / 2, Y: (points[i].Y + points[i+1].Y) / 2}
+}
+
+func svgNumber(value float64) string {
+	return fmt.Sprintf("%.1f", value)
+}
+
+func svgEscape(text string) string {
+	return html.EscapeString(text)
+}
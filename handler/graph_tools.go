@@ -0,0 +1,711 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/wolfmagnate/auto_debater/case_report"
+	"github.com/wolfmagnate/auto_debater/domain"
+	"github.com/wolfmagnate/auto_debater/graph_format"
+	"github.com/wolfmagnate/auto_debater/graph_renderer"
+)
+
+// decodeJSONRequest は、POSTメソッドであることを確認してリクエストボディをvにデコードします。
+// 失敗した場合はエラーレスポンスを書き込み、falseを返します。
+func decodeJSONRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("ERROR: Could not read request body: %v", err)
+		http.Error(w, "Could not read request body", http.StatusInternalServerError)
+		return false
+	}
+	defer r.Body.Close()
+
+	if err := json.Unmarshal(body, v); err != nil {
+		log.Printf("ERROR: Could not unmarshal request JSON: %v", err)
+		http.Error(w, "Bad request: invalid JSON format", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// parseDebateGraphField は、リクエスト中のfieldName フィールドからDebateGraphを構築します。
+// 失敗した場合はエラーレスポンスを書き込み、falseを返します。
+func parseDebateGraphField(w http.ResponseWriter, raw json.RawMessage, fieldName string) (*domain.DebateGraph, bool) {
+	if len(raw) == 0 {
+		http.Error(w, fmt.Sprintf("Bad request: '%s' field is required", fieldName), http.StatusBadRequest)
+		return nil, false
+	}
+
+	debateGraph, err := domain.NewDebateGraphFromJSON(string(raw))
+	if err != nil {
+		log.Printf("ERROR: Could not create %s from JSON: %v", fieldName, err)
+		http.Error(w, fmt.Sprintf("Bad request: invalid %s structure", fieldName), http.StatusBadRequest)
+		return nil, false
+	}
+	return debateGraph, true
+}
+
+// writeJSONResponse は、vをJSONに変換して200 OKのレスポンスとして書き込みます。
+func writeJSONResponse(w http.ResponseWriter, v any) {
+	responseJSON, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("ERROR: Could not marshal response to JSON: %v", err)
+		http.Error(w, "Internal server error while formatting response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(responseJSON); err != nil {
+		log.Printf("ERROR: Could not write response: %v", err)
+	}
+}
+
+type DiffGraphsRequest struct {
+	BeforeJSON json.RawMessage `json:"before"`
+	AfterJSON  json.RawMessage `json:"after"`
+}
+
+// DiffGraphsEndpoint は、2つのDebateGraphの差分を返すHTTPハンドラです。
+// AIモデルは呼び出さないため、AnalyzeRebuttalなどによる変更内容の確認に使用できます。
+func (h *Handler) DiffGraphsEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req DiffGraphsRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	before, ok := parseDebateGraphField(w, req.BeforeJSON, "before")
+	if !ok {
+		return
+	}
+	after, ok := parseDebateGraphField(w, req.AfterJSON, "after")
+	if !ok {
+		return
+	}
+
+	diff, err := domain.DiffDebateGraphs(before, after)
+	if err != nil {
+		log.Printf("ERROR: Could not diff graphs: %v", err)
+		http.Error(w, "Internal server error while computing graph diff", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("INFO: Computed graph diff: +%d/-%d nodes, +%d/-%d edges, +%d/-%d rebuttals.",
+		len(diff.AddedNodes), len(diff.RemovedNodes), len(diff.AddedEdges), len(diff.RemovedEdges), len(diff.AddedRebuttals), len(diff.RemovedRebuttals))
+
+	writeJSONResponse(w, diff)
+}
+
+type HighlightDocumentRequest struct {
+	Document        string          `json:"document"`
+	DebateGraphJSON json.RawMessage `json:"debate_graph"`
+}
+
+// HighlightDocumentEndpoint は、DebateGraphの各ノード・エッジが文書のどの範囲から抽出されたかを返すHTTPハンドラです。
+func (h *Handler) HighlightDocumentEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req HighlightDocumentRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+	if req.Document == "" {
+		http.Error(w, "Bad request: 'document' field is required", http.StatusBadRequest)
+		return
+	}
+
+	debateGraph, ok := parseDebateGraphField(w, req.DebateGraphJSON, "debate_graph")
+	if !ok {
+		return
+	}
+
+	highlighted, err := domain.HighlightDocument(req.Document, debateGraph)
+	if err != nil {
+		log.Printf("ERROR: Could not highlight document: %v", err)
+		http.Error(w, "Internal server error while highlighting document", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("INFO: Highlighted document %s: %d nodes, %d edges.", highlighted.DocumentID, len(highlighted.Nodes), len(highlighted.Edges))
+
+	writeJSONResponse(w, highlighted)
+}
+
+type AdjudicateRequest struct {
+	DebateGraphJSON json.RawMessage            `json:"debate_graph"`
+	Config          *domain.AdjudicationConfig `json:"config,omitempty"`
+}
+
+// AdjudicateEndpoint は、DebateGraphを評価してどちらの立場が優勢かを返すHTTPハンドラです。
+// configを省略した場合は既定の割引率を使用します。
+func (h *Handler) AdjudicateEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req AdjudicateRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	debateGraph, ok := parseDebateGraphField(w, req.DebateGraphJSON, "debate_graph")
+	if !ok {
+		return
+	}
+
+	config := domain.DefaultAdjudicationConfig()
+	if req.Config != nil {
+		config = *req.Config
+	}
+	if err := config.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	result, err := domain.Adjudicate(debateGraph, config)
+	if err != nil {
+		log.Printf("ERROR: Could not adjudicate debate graph: %v", err)
+		http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("INFO: Adjudicated debate graph: proponent %.3f, opponent %.3f, verdict %s.", result.ProponentScore, result.OpponentScore, result.Verdict)
+
+	writeJSONResponse(w, result)
+}
+
+type PropagateBeliefsRequest struct {
+	DebateGraphJSON json.RawMessage `json:"debate_graph"`
+}
+
+// PropagateBeliefsEndpoint は、エッジの確率を伝播させて各影響の期待値とエッジの感度を返すHTTPハンドラです。
+func (h *Handler) PropagateBeliefsEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req PropagateBeliefsRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	debateGraph, ok := parseDebateGraphField(w, req.DebateGraphJSON, "debate_graph")
+	if !ok {
+		return
+	}
+
+	result, err := domain.PropagateBeliefs(debateGraph)
+	if err != nil {
+		log.Printf("ERROR: Could not propagate beliefs: %v", err)
+		http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("INFO: Propagated beliefs over %d nodes: total expected value %.3f.", len(result.Nodes), result.TotalExpectedValue)
+
+	writeJSONResponse(w, result)
+}
+
+type WeakestLinksRequest struct {
+	DebateGraphJSON json.RawMessage `json:"debate_graph"`
+}
+
+// WeakestLinksEndpoint は、否定されたときに最も多くの影響を失わせるノードとエッジを順に返すHTTPハンドラです。
+func (h *Handler) WeakestLinksEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req WeakestLinksRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	debateGraph, ok := parseDebateGraphField(w, req.DebateGraphJSON, "debate_graph")
+	if !ok {
+		return
+	}
+
+	analysis, err := domain.AnalyzeWeakestLinks(debateGraph)
+	if err != nil {
+		log.Printf("ERROR: Could not analyze weakest links: %v", err)
+		http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("INFO: Found %d weak links in debate graph.", len(analysis.Links))
+
+	writeJSONResponse(w, analysis)
+}
+
+type ReplayDebateRequest struct {
+	DebateJSON json.RawMessage `json:"debate"`
+}
+
+type ReplayDebateResponse struct {
+	Motion string                    `json:"motion"`
+	Teams  []domain.Team             `json:"teams"`
+	Steps  []domain.DebateReplayStep `json:"steps"`
+}
+
+// ReplayDebateEndpoint は、Debateの履歴を再生し、各スピーチによるグラフの変更内容を順に返すHTTPハンドラです。
+func (h *Handler) ReplayDebateEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req ReplayDebateRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	if len(req.DebateJSON) == 0 {
+		http.Error(w, "Bad request: 'debate' field is required", http.StatusBadRequest)
+		return
+	}
+	debate, err := domain.NewDebateFromJSON(string(req.DebateJSON))
+	if err != nil {
+		log.Printf("ERROR: Could not create debate from JSON: %v", err)
+		http.Error(w, fmt.Sprintf("Bad request: invalid debate structure: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	steps, err := debate.Replay()
+	if err != nil {
+		log.Printf("ERROR: Could not replay debate: %v", err)
+		http.Error(w, "Internal server error during debate replay", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("INFO: Replayed debate with %d speeches.", len(steps))
+
+	writeJSONResponse(w, ReplayDebateResponse{Motion: debate.Motion, Teams: debate.Teams, Steps: steps})
+}
+
+type FlowSheetRequest struct {
+	DebateJSON json.RawMessage `json:"debate"`
+	Format     string          `json:"format,omitempty"` // "json" (既定), "csv", "markdown" のいずれか
+}
+
+// FlowSheetEndpoint は、Debateの履歴から各議論のスピーチごとの状態を追跡したフローシートを返すHTTPハンドラです。
+func (h *Handler) FlowSheetEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req FlowSheetRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	if len(req.DebateJSON) == 0 {
+		http.Error(w, "Bad request: 'debate' field is required", http.StatusBadRequest)
+		return
+	}
+	if req.Format != "" && req.Format != "json" && req.Format != "csv" && req.Format != "markdown" {
+		http.Error(w, "Bad request: 'format' must be one of 'json', 'csv', 'markdown'", http.StatusBadRequest)
+		return
+	}
+	debate, err := domain.NewDebateFromJSON(string(req.DebateJSON))
+	if err != nil {
+		log.Printf("ERROR: Could not create debate from JSON: %v", err)
+		http.Error(w, fmt.Sprintf("Bad request: invalid debate structure: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	sheet, err := domain.BuildFlowSheet(debate)
+	if err != nil {
+		log.Printf("ERROR: Could not build flow sheet: %v", err)
+		http.Error(w, "Internal server error while building flow sheet", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("INFO: Built flow sheet with %d items over %d speeches.", len(sheet.Items), len(sheet.Speeches))
+
+	switch req.Format {
+	case "csv":
+		csvText, err := sheet.ToCSV()
+		if err != nil {
+			log.Printf("ERROR: Could not write flow sheet as CSV: %v", err)
+			http.Error(w, "Internal server error while formatting response", http.StatusInternalServerError)
+			return
+		}
+		writeTextResponse(w, "text/csv; charset=utf-8", csvText)
+	case "markdown":
+		writeTextResponse(w, "text/markdown; charset=utf-8", sheet.ToMarkdown())
+	default:
+		writeJSONResponse(w, sheet)
+	}
+}
+
+// writeTextResponse は文字列をそのままレスポンスとして書き込みます。
+func writeTextResponse(w http.ResponseWriter, contentType string, text string) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(text)); err != nil {
+		log.Printf("ERROR: Could not write response: %v", err)
+	}
+}
+
+type GraphReportRequest struct {
+	DebateGraphJSON json.RawMessage `json:"debate_graph"`
+	Format          string          `json:"format,omitempty"` // "json" (既定), "markdown" のいずれか
+}
+
+// GraphReportEndpoint は、グラフの規模と、根拠や応答が不足している箇所をまとめた報告を返すHTTPハンドラです。
+func (h *Handler) GraphReportEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req GraphReportRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	if req.Format != "" && req.Format != "json" && req.Format != "markdown" {
+		http.Error(w, "Bad request: 'format' must be one of 'json', 'markdown'", http.StatusBadRequest)
+		return
+	}
+	debateGraph, ok := parseDebateGraphField(w, req.DebateGraphJSON, "debate_graph")
+	if !ok {
+		return
+	}
+
+	report, err := domain.BuildGraphReport(debateGraph)
+	if err != nil {
+		log.Printf("ERROR: Could not build graph report: %v", err)
+		http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("INFO: Built graph report with %d unanswered rebuttals.", len(report.UnansweredRebuttals))
+
+	if req.Format == "markdown" {
+		writeTextResponse(w, "text/markdown; charset=utf-8", report.ToMarkdown())
+		return
+	}
+	writeJSONResponse(w, report)
+}
+
+type RenderRequest struct {
+	DebateGraphJSON json.RawMessage `json:"debate_graph,omitempty"`
+	LogicGraphJSON  json.RawMessage `json:"logic_graph,omitempty"`
+	Format          string          `json:"format"` // graph_renderer.Formats() のいずれか
+}
+
+// RenderEndpoint は、DebateGraphまたはLogicGraphを指定された形式で描画するHTTPハンドラです。
+func (h *Handler) RenderEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req RenderRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	renderer, err := graph_renderer.NewRenderer(req.Format)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: 'format' must be one of %v", graph_renderer.Formats()), http.StatusBadRequest)
+		return
+	}
+	if (len(req.DebateGraphJSON) == 0) == (len(req.LogicGraphJSON) == 0) {
+		http.Error(w, "Bad request: exactly one of 'debate_graph' or 'logic_graph' is required", http.StatusBadRequest)
+		return
+	}
+
+	var rendered string
+	if len(req.DebateGraphJSON) > 0 {
+		debateGraph, ok := parseDebateGraphField(w, req.DebateGraphJSON, "debate_graph")
+		if !ok {
+			return
+		}
+		rendered, err = renderer.RenderDebateGraph(debateGraph)
+	} else {
+		logicGraph, parseErr := domain.NewLogicGraphFromJSON(string(req.LogicGraphJSON))
+		if parseErr != nil {
+			log.Printf("ERROR: Could not create logic_graph from JSON: %v", parseErr)
+			http.Error(w, "Bad request: invalid logic_graph structure", http.StatusBadRequest)
+			return
+		}
+		rendered, err = renderer.RenderLogicGraph(logicGraph)
+	}
+	if err != nil {
+		log.Printf("ERROR: Could not render graph as %s: %v", req.Format, err)
+		http.Error(w, "Internal server error while rendering graph", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("INFO: Rendered graph as %s (%d bytes).", req.Format, len(rendered))
+
+	writeTextResponse(w, renderer.ContentType(), rendered)
+}
+
+type CaseReportRequest struct {
+	DebateGraphJSON json.RawMessage `json:"debate_graph"`
+	Title           string          `json:"title,omitempty"`
+	Format          string          `json:"format,omitempty"` // "markdown" (既定), "html", "json" のいずれか
+}
+
+// CaseReportEndpoint は、影響ごとの因果の連鎖・根拠・反論と弱点をまとめた印刷用の資料を返すHTTPハンドラです。
+func (h *Handler) CaseReportEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req CaseReportRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	if req.Format != "" && req.Format != "markdown" && req.Format != "html" && req.Format != "json" {
+		http.Error(w, "Bad request: 'format' must be one of 'markdown', 'html', 'json'", http.StatusBadRequest)
+		return
+	}
+	debateGraph, ok := parseDebateGraphField(w, req.DebateGraphJSON, "debate_graph")
+	if !ok {
+		return
+	}
+
+	report, err := case_report.BuildCaseReport(debateGraph, req.Title)
+	if err != nil {
+		log.Printf("ERROR: Could not build case report: %v", err)
+		http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("INFO: Built case report with %d sides.", len(report.Sides))
+
+	switch req.Format {
+	case "html":
+		html, err := report.ToHTML()
+		if err != nil {
+			log.Printf("ERROR: Could not write case report as HTML: %v", err)
+			http.Error(w, "Internal server error while formatting response", http.StatusInternalServerError)
+			return
+		}
+		writeTextResponse(w, "text/html; charset=utf-8", html)
+	case "json":
+		writeJSONResponse(w, report)
+	default:
+		writeTextResponse(w, "text/markdown; charset=utf-8", report.ToMarkdown())
+	}
+}
+
+type ExportGraphRequest struct {
+	DebateGraphJSON json.RawMessage `json:"debate_graph,omitempty"`
+	LogicGraphJSON  json.RawMessage `json:"logic_graph,omitempty"`
+	Format          string          `json:"format"`                // graph_format.ExportFormats() のいずれか
+	ContextURL      string          `json:"context_url,omitempty"` // jsonld のとき、@context を埋め込まずに参照するURL
+}
+
+// ExportGraphEndpoint は、DebateGraphまたはLogicGraphを他の議論ツールで読み込める交換形式に変換するHTTPハンドラです。
+func (h *Handler) ExportGraphEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req ExportGraphRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	exporter, err := graph_format.NewExporter(req.Format)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: 'format' must be one of %v", graph_format.ExportFormats()), http.StatusBadRequest)
+		return
+	}
+	if (len(req.DebateGraphJSON) == 0) == (len(req.LogicGraphJSON) == 0) {
+		http.Error(w, "Bad request: exactly one of 'debate_graph' or 'logic_graph' is required", http.StatusBadRequest)
+		return
+	}
+	if jsonldExporter, ok := exporter.(*graph_format.JSONLDFormat); ok {
+		jsonldExporter.ContextURL = req.ContextURL
+	}
+
+	var exported string
+	if len(req.DebateGraphJSON) > 0 {
+		debateGraph, ok := parseDebateGraphField(w, req.DebateGraphJSON, "debate_graph")
+		if !ok {
+			return
+		}
+		exported, err = exporter.Export(debateGraph)
+	} else {
+		logicGraphExporter, ok := exporter.(graph_format.LogicGraphExporter)
+		if !ok {
+			http.Error(w, fmt.Sprintf("Bad request: format '%s' does not support 'logic_graph'", req.Format), http.StatusBadRequest)
+			return
+		}
+		logicGraph, parseErr := domain.NewLogicGraphFromJSON(string(req.LogicGraphJSON))
+		if parseErr != nil {
+			log.Printf("ERROR: Could not create logic_graph from JSON: %v", parseErr)
+			http.Error(w, "Bad request: invalid logic_graph structure", http.StatusBadRequest)
+			return
+		}
+		exported, err = logicGraphExporter.ExportLogicGraph(logicGraph)
+	}
+	if err != nil {
+		log.Printf("ERROR: Could not export graph as %s: %v", req.Format, err)
+		http.Error(w, "Internal server error while exporting graph", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("INFO: Exported graph as %s (%d bytes).", req.Format, len(exported))
+
+	writeTextResponse(w, exporter.ContentType(), exported)
+}
+
+type ImportGraphRequest struct {
+	Format    string `json:"format"` // graph_format.ImportFormats() のいずれか
+	Data      string `json:"data"`
+	GraphType string `json:"graph_type,omitempty"` // "debate_graph" (デフォルト) または "logic_graph"
+}
+
+type ImportGraphResponse struct {
+	DebateGraphJSON json.RawMessage `json:"debate_graph,omitempty"`
+	LogicGraphJSON  json.RawMessage `json:"logic_graph,omitempty"`
+}
+
+// ImportGraphEndpoint は、他の議論ツールの交換形式からDebateGraphまたはLogicGraphを復元するHTTPハンドラです。
+func (h *Handler) ImportGraphEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req ImportGraphRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	importer, err := graph_format.NewImporter(req.Format)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad request: 'format' must be one of %v", graph_format.ImportFormats()), http.StatusBadRequest)
+		return
+	}
+	if req.GraphType != "" && req.GraphType != "debate_graph" && req.GraphType != "logic_graph" {
+		http.Error(w, "Bad request: 'graph_type' must be one of 'debate_graph', 'logic_graph'", http.StatusBadRequest)
+		return
+	}
+	if req.Data == "" {
+		http.Error(w, "Bad request: 'data' field is required", http.StatusBadRequest)
+		return
+	}
+
+	if req.GraphType == "logic_graph" {
+		logicGraphImporter, ok := importer.(graph_format.LogicGraphImporter)
+		if !ok {
+			http.Error(w, fmt.Sprintf("Bad request: format '%s' does not support 'logic_graph'", req.Format), http.StatusBadRequest)
+			return
+		}
+		logicGraph, err := logicGraphImporter.ImportLogicGraph(req.Data)
+		if err != nil {
+			log.Printf("ERROR: Could not import logic graph from %s: %v", req.Format, err)
+			http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+			return
+		}
+		logicGraphJSON, err := logicGraph.ToJSON()
+		if err != nil {
+			log.Printf("ERROR: Failed to marshal logic graph to JSON: %v", err)
+			http.Error(w, "Internal server error while formatting response", http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("INFO: Imported logic graph from %s with %d nodes.", req.Format, len(logicGraph.Nodes))
+
+		writeJSONResponse(w, ImportGraphResponse{LogicGraphJSON: json.RawMessage(logicGraphJSON)})
+		return
+	}
+
+	debateGraph, err := importer.Import(req.Data)
+	if err != nil {
+		log.Printf("ERROR: Could not import graph from %s: %v", req.Format, err)
+		http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	debateGraphJSON, err := debateGraph.ToCompactJSON()
+	if err != nil {
+		log.Printf("ERROR: Failed to marshal debate graph to JSON: %v", err)
+		http.Error(w, "Internal server error while formatting response", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("INFO: Imported graph from %s with %d nodes.", req.Format, len(debateGraph.Nodes))
+
+	writeJSONResponse(w, ImportGraphResponse{DebateGraphJSON: json.RawMessage(debateGraphJSON)})
+}
+
+type ExportGraphsRequest struct {
+	Graphs []NamedDebateGraph `json:"graphs"`
+	Format string             `json:"format"` // graph_format.BatchExportFormats() のいずれか
+}
+
+// NamedDebateGraph は一括出力するグラフの1つです。name は出力の graph 属性になります。
+type NamedDebateGraph struct {
+	Name            string          `json:"name"`
+	DebateGraphJSON json.RawMessage `json:"debate_graph"`
+}
+
+// ExportGraphsEndpoint は、複数のDebateGraphをグラフデータベース向けの1つの文書にまとめて出力するHTTPハンドラです。
+func (h *Handler) ExportGraphsEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req ExportGraphsRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	exporter, err := graph_format.NewExporter(req.Format)
+	batchExporter, ok := exporter.(graph_format.BatchExporter)
+	if err != nil || !ok {
+		http.Error(w, fmt.Sprintf("Bad request: 'format' must be one of %v", graph_format.BatchExportFormats()), http.StatusBadRequest)
+		return
+	}
+	if len(req.Graphs) == 0 {
+		http.Error(w, "Bad request: 'graphs' must contain at least one graph", http.StatusBadRequest)
+		return
+	}
+
+	graphs := make([]graph_format.NamedGraph, 0, len(req.Graphs))
+	for i, named := range req.Graphs {
+		debateGraph, ok := parseDebateGraphField(w, named.DebateGraphJSON, fmt.Sprintf("graphs[%d].debate_graph", i))
+		if !ok {
+			return
+		}
+		graphs = append(graphs, graph_format.NamedGraph{Name: named.Name, Graph: debateGraph})
+	}
+
+	exported, err := batchExporter.ExportBatch(graphs)
+	if err != nil {
+		log.Printf("ERROR: Could not export graphs as %s: %v", req.Format, err)
+		http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("INFO: Exported %d graphs as %s (%d bytes).", len(graphs), req.Format, len(exported))
+
+	writeTextResponse(w, exporter.ContentType(), exported)
+}
+
+// JSONLDContextEndpoint は、JSON-LDで出力したグラフが参照する @context の文書を返すHTTPハンドラです。
+func (h *Handler) JSONLDContextEndpoint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusMethodNotAllowed)
+		return
+	}
+	writeTextResponse(w, "application/ld+json", graph_format.JSONLDContext())
+}
+
+type ApplyOperationsRequest struct {
+	DebateGraphJSON json.RawMessage         `json:"debate_graph"`
+	Operations      []domain.GraphOperation `json:"operations"`
+}
+
+type ApplyOperationsResponse struct {
+	DebateGraphJSON   json.RawMessage         `json:"debate_graph"`
+	InverseOperations []domain.GraphOperation `json:"inverse_operations"`
+}
+
+// ApplyOperationsEndpoint は、DebateGraphに一連の変更操作を適用し、変更後のグラフと元に戻すための操作を返すHTTPハンドラです。
+func (h *Handler) ApplyOperationsEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req ApplyOperationsRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	debateGraph, ok := parseDebateGraphField(w, req.DebateGraphJSON, "debate_graph")
+	if !ok {
+		return
+	}
+	if len(req.Operations) == 0 {
+		http.Error(w, "Bad request: 'operations' must contain at least one operation", http.StatusBadRequest)
+		return
+	}
+
+	updated, inverse, err := domain.ApplyGraphOperations(debateGraph, req.Operations)
+	if err != nil {
+		log.Printf("ERROR: Could not apply operations to debate graph: %v", err)
+		http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	debateGraphJSON, err := updated.ToCompactJSON()
+	if err != nil {
+		log.Printf("ERROR: Failed to marshal debate graph to JSON: %v", err)
+		http.Error(w, "Internal server error while formatting response", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("INFO: Applied %d operations to debate graph (%d nodes).", len(req.Operations), len(updated.Nodes))
+
+	writeJSONResponse(w, ApplyOperationsResponse{
+		DebateGraphJSON:   json.RawMessage(debateGraphJSON),
+		InverseOperations: inverse,
+	})
+}
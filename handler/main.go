@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -9,22 +10,25 @@ import (
 	createrebuttal "github.com/wolfmagnate/auto_debater/create_rebuttal"
 	"github.com/wolfmagnate/auto_debater/domain"
 	"github.com/wolfmagnate/auto_debater/logic_composer"
+	"github.com/wolfmagnate/auto_debater/strength_estimator"
 )
 
 // Handler は、アプリケーションのHTTPハンドラと依存関係を保持します。
 // RebuttalCreatorはポインタで保持するのが一般的です。
 type Handler struct {
-	RebuttalCreator *createrebuttal.RebuttalCreator
-	LogicEnhancer   *logic_composer.LogicEnhancer
-	TODOEnhancer    *logic_composer.TODOEnhancer
+	RebuttalCreator   *createrebuttal.RebuttalCreator
+	LogicEnhancer     *logic_composer.LogicEnhancer
+	TODOEnhancer      *logic_composer.TODOEnhancer
+	StrengthEstimator *strength_estimator.StrengthEstimator
 }
 
 // NewHandler は、依存関係を注入して新しいHandlerを生成します。
-func NewHandler(creator *createrebuttal.RebuttalCreator, logicEnhancer *logic_composer.LogicEnhancer, todoEnhancer *logic_composer.TODOEnhancer) *Handler {
+func NewHandler(creator *createrebuttal.RebuttalCreator, logicEnhancer *logic_composer.LogicEnhancer, todoEnhancer *logic_composer.TODOEnhancer, strengthEstimator *strength_estimator.StrengthEstimator) *Handler {
 	return &Handler{
-		RebuttalCreator: creator,
-		LogicEnhancer:   logicEnhancer,
-		TODOEnhancer:    todoEnhancer,
+		RebuttalCreator:   creator,
+		LogicEnhancer:     logicEnhancer,
+		TODOEnhancer:      todoEnhancer,
+		StrengthEstimator: strengthEstimator,
 	}
 }
 
@@ -271,3 +275,72 @@ func (h *Handler) EnhanceTODOEndpoint(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("INFO: Successfully sent TODO suggestions as response.")
 }
+
+type EstimateStrengthsRequest struct {
+	DebateGraphJSON json.RawMessage `json:"debate_graph"`
+}
+
+type EstimateStrengthsResponse struct {
+	DebateGraphJSON json.RawMessage                       `json:"debate_graph"`
+	Estimates       *strength_estimator.StrengthEstimates `json:"estimates"`
+	Beliefs         *domain.BeliefPropagationResult       `json:"beliefs"`
+	Skipped         []string                              `json:"skipped"` // 対象が見つからない、または段階が未定義のため適用しなかった推定
+}
+
+// EstimateStrengthsEndpoint は、エッジの確率と影響の大きさをAIモデルに推定させるHTTPハンドラです。
+// 推定値を反映したDebateGraphと、それをもとにした確率の伝播結果を返します。
+func (h *Handler) EstimateStrengthsEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req EstimateStrengthsRequest
+	if !decodeJSONRequest(w, r, &req) {
+		return
+	}
+
+	debateGraph, ok := parseDebateGraphField(w, req.DebateGraphJSON, "debate_graph")
+	if !ok {
+		return
+	}
+
+	// 確率の伝播は循環のあるグラフを扱えないため、AIモデルを呼び出す前に確認する
+	if _, err := debateGraph.TopologicalOrder(); err != nil {
+		log.Printf("ERROR: Debate graph cannot be used for strength estimation: %v", err)
+		http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("INFO: Starting strength estimation for %d nodes.", len(debateGraph.Nodes))
+
+	estimates, err := h.StrengthEstimator.EstimateStrengths(r.Context(), debateGraph)
+	if err != nil {
+		log.Printf("ERROR: Strength estimation process failed: %v", err)
+		http.Error(w, "Internal server error during strength estimation", http.StatusInternalServerError)
+		return
+	}
+	skipped := make([]string, 0)
+	for _, err := range strength_estimator.ApplyStrengthEstimates(debateGraph, estimates) {
+		log.Printf("WARN: Strength estimate skipped: %v", err)
+		skipped = append(skipped, err.Error())
+	}
+
+	beliefs, err := domain.PropagateBeliefs(debateGraph)
+	if err != nil {
+		log.Printf("ERROR: Could not propagate beliefs: %v", err)
+		http.Error(w, fmt.Sprintf("Bad request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	debateGraphJSON, err := debateGraph.ToCompactJSON()
+	if err != nil {
+		log.Printf("ERROR: Failed to marshal debate graph to JSON: %v", err)
+		http.Error(w, "Internal server error while formatting response", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("INFO: Estimated %d edge probabilities and %d impact magnitudes.", len(estimates.Edges), len(estimates.Impacts))
+
+	writeJSONResponse(w, EstimateStrengthsResponse{
+		DebateGraphJSON: json.RawMessage(debateGraphJSON),
+		Estimates:       estimates,
+		Beliefs:         beliefs,
+		Skipped:         skipped,
+	})
+}
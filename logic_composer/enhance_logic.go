@@ -69,7 +69,7 @@ func (enhancer *LogicEnhancer) EnhanceLogic(ctx context.Context, debateGraph *do
 	}
 
 	// 全体の議論グラフはループ内で不変なので、最初にJSON化します。
-	debateGraphJSON, err := debateGraph.ToJSON()
+	debateGraphJSON, err := debateGraph.ToCompactJSON()
 	if err != nil {
 		return nil, fmt.Errorf("グラフのJSON化に失敗しました: %w", err)
 	}
@@ -80,7 +80,7 @@ func (enhancer *LogicEnhancer) EnhanceLogic(ctx context.Context, debateGraph *do
 	// 強化のプロセスを3回繰り返します。
 	for i := 0; i < 3; i++ {
 		// ループの都度、更新されたサブグラフをJSON化します。
-		subGraphJSON, err := subGraph.ToJSON()
+		subGraphJSON, err := subGraph.ToCompactJSON()
 		if err != nil {
 			return nil, fmt.Errorf("ループ%d回目のサブグラフのJSON化に失敗しました: %w", i+1, err)
 		}
@@ -109,38 +109,48 @@ func (enhancer *LogicEnhancer) EnhanceLogic(ctx context.Context, debateGraph *do
 		// AIから受け取った強化策をサブグラフに適用します。
 		if payload := enhancement.InsertNode; payload != nil {
 			// --- 中間ノードの挿入 ---
-			causeNode, _ := subGraph.GetNode(payload.CauseArgument)
-			effectNode, _ := subGraph.GetNode(payload.EffectArgument)
+			// AIモデルが返した文字列を既存のエッジに対応付ける
+			targetEdge, err := subGraph.ResolveEdge(payload.CauseArgument, payload.EffectArgument)
+			if err != nil {
+				return nil, fmt.Errorf("ループ%d回目, 中間ノードの挿入対象のエッジが特定できません: %w", i+1, err)
+			}
+			causeNode, effectNode := targetEdge.Cause, targetEdge.Effect
+			payload.CauseArgument, payload.EffectArgument = causeNode.Argument, effectNode.Argument
 
 			intermediateNode := domain.NewDebateGraphNode(payload.IntermediateArgument, false)
 			if err := subGraph.AddNode(intermediateNode); err != nil {
 				return nil, fmt.Errorf("ループ%d回目, 中間ノード '%s' の追加に失敗しました: %w", i+1, payload.IntermediateArgument, err)
 			}
-			if err := subGraph.RemoveEdge(payload.CauseArgument, payload.EffectArgument); err != nil {
-				return nil, fmt.Errorf("ループ%d回目, 元のエッジ '%s -> %s' の削除に失敗しました: %w", i+1, payload.CauseArgument, payload.EffectArgument, err)
+			if err := subGraph.RemoveEdge(causeNode.Argument, effectNode.Argument); err != nil {
+				return nil, fmt.Errorf("ループ%d回目, 元のエッジ '%s -> %s' の削除に失敗しました: %w", i+1, causeNode.Argument, effectNode.Argument, err)
 			}
 			edge1 := domain.NewDebateGraphEdge(causeNode, intermediateNode, false)
 			if err := subGraph.AddEdge(edge1); err != nil {
-				return nil, fmt.Errorf("ループ%d回目, 新しいエッジ '%s -> %s' の追加に失敗しました: %w", i+1, payload.CauseArgument, payload.IntermediateArgument, err)
+				return nil, fmt.Errorf("ループ%d回目, 新しいエッジ '%s -> %s' の追加に失敗しました: %w", i+1, causeNode.Argument, payload.IntermediateArgument, err)
 			}
 			edge2 := domain.NewDebateGraphEdge(intermediateNode, effectNode, false)
 			if err := subGraph.AddEdge(edge2); err != nil {
-				return nil, fmt.Errorf("ループ%d回目, 新しいエッジ '%s -> %s' の追加に失敗しました: %w", i+1, payload.IntermediateArgument, payload.EffectArgument, err)
+				return nil, fmt.Errorf("ループ%d回目, 新しいエッジ '%s -> %s' の追加に失敗しました: %w", i+1, payload.IntermediateArgument, effectNode.Argument, err)
 			}
 		} else if payload := enhancement.StrengthenEdge; payload != nil {
 			// --- 既存エッジの強化 ---
-			edge, exists := subGraph.GetEdge(payload.CauseArgument, payload.EffectArgument)
-			if !exists {
-				return nil, fmt.Errorf("ループ%d回目, 強化対象のエッジ '%s -> %s' が見つかりません", i+1, payload.CauseArgument, payload.EffectArgument)
+			edge, err := subGraph.ResolveEdge(payload.CauseArgument, payload.EffectArgument)
+			if err != nil {
+				return nil, fmt.Errorf("ループ%d回目, 強化対象のエッジが特定できません: %w", i+1, err)
 			}
+			payload.CauseArgument, payload.EffectArgument = edge.Cause.Argument, edge.Effect.Argument
+			var annotationType string
 			switch payload.EnhancementType {
 			case "uniqueness":
-				edge.Uniqueness = append(edge.Uniqueness, payload.Content)
+				annotationType = domain.EdgeAnnotationUniqueness
 			case "certainty":
-				edge.Certainty = append(edge.Certainty, payload.Content)
+				annotationType = domain.EdgeAnnotationCertainty
 			default:
 				return nil, fmt.Errorf("ループ%d回目, 不明なエッジ強化タイプです: '%s'", i+1, payload.EnhancementType)
 			}
+			if err := subGraph.AddEdgeAnnotation(payload.CauseArgument, payload.EffectArgument, annotationType, domain.NewEvidence(payload.Content)); err != nil {
+				return nil, fmt.Errorf("ループ%d回目, エッジの強化に失敗しました: %w", i+1, err)
+			}
 		} else {
 			return nil, fmt.Errorf("ループ%d回目にAIから返された強化策の形式が不正です", i+1)
 		}
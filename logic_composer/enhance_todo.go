@@ -57,12 +57,12 @@ func (enhancer *TODOEnhancer) EnhanceTODO(
 	ctx context.Context,
 	debateGraph *domain.DebateGraph,
 	subGraph *domain.DebateGraph) (*TODOSuggestions, error) {
-	debateGraphJSON, err := debateGraph.ToJSON()
+	debateGraphJSON, err := debateGraph.ToCompactJSON()
 	if err != nil {
 		return nil, fmt.Errorf("グラフのJSON化に失敗しました: %w", err)
 	}
 
-	targetDebateGraphJSON, err := subGraph.ToJSON()
+	targetDebateGraphJSON, err := subGraph.ToCompactJSON()
 	if err != nil {
 		return nil, fmt.Errorf("グラフのJSON化に失敗しました: %w", err)
 	}
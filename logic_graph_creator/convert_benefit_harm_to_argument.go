@@ -46,6 +46,8 @@ func ConvertBenefitHarmToJSON(benefitHarm *BenefitHarm) (string, error) {
 	return string(jsonBytes), nil
 }
 
+// ConvertImpactAnalysisToArguments は影響分析のメリット・デメリットをLogicGraphNodeに変換します。
+// 各ノードには、どちらの世界のメリット・デメリットであるか、誰にとっての影響であるかをメタデータとして設定します。
 func (converter *BenefitHarmConverter) ConvertImpactAnalysisToArguments(ctx context.Context, impactAnalysis *ImpactAnalysis) ([]*domain.LogicGraphNode, error) {
 	arguments := make([]*domain.LogicGraphNode, len(impactAnalysis.StatusQuo.Benefits)+len(impactAnalysis.StatusQuo.Harms)+len(impactAnalysis.AffirmativePlan.Benefits)+len(impactAnalysis.AffirmativePlan.Harms))
 	index := 0
@@ -57,6 +59,7 @@ func (converter *BenefitHarmConverter) ConvertImpactAnalysisToArguments(ctx cont
 			return nil, fmt.Errorf("status Quo BenefitのArgument変換に失敗しました: %w", err)
 		}
 		arguments[index] = domain.NewLogicGraphNode(arg)
+		arguments[index].NodeMetadata = domain.NewImpactMetadata(domain.SideStatusQuo, domain.PolarityBenefit, bh.Who)
 		index++
 	}
 
@@ -67,6 +70,7 @@ func (converter *BenefitHarmConverter) ConvertImpactAnalysisToArguments(ctx cont
 			return nil, fmt.Errorf("status Quo HarmのArgument変換に失敗しました: %w", err)
 		}
 		arguments[index] = domain.NewLogicGraphNode(arg)
+		arguments[index].NodeMetadata = domain.NewImpactMetadata(domain.SideStatusQuo, domain.PolarityHarm, bh.Who)
 		index++
 	}
 
@@ -77,6 +81,7 @@ func (converter *BenefitHarmConverter) ConvertImpactAnalysisToArguments(ctx cont
 			return nil, fmt.Errorf("affirmative Plan BenefitのArgument変換に失敗しました: %w", err)
 		}
 		arguments[index] = domain.NewLogicGraphNode(arg)
+		arguments[index].NodeMetadata = domain.NewImpactMetadata(domain.SideAffirmativePlan, domain.PolarityBenefit, bh.Who)
 		index++
 	}
 
@@ -87,6 +92,7 @@ func (converter *BenefitHarmConverter) ConvertImpactAnalysisToArguments(ctx cont
 			return nil, fmt.Errorf("affirmative Plan HarmのArgument変換に失敗しました: %w", err)
 		}
 		arguments[index] = domain.NewLogicGraphNode(arg)
+		arguments[index].NodeMetadata = domain.NewImpactMetadata(domain.SideAffirmativePlan, domain.PolarityHarm, bh.Who)
 		index++
 	}
 
@@ -3,6 +3,7 @@ package logic_graph_creator
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"github.com/wolfmagnate/auto_debater/domain"
 )
@@ -43,6 +44,9 @@ func (creator *LogicGraphCreator) CreateLogicGraph(ctx context.Context, document
 		return nil, fmt.Errorf("論理グラフの生成に失敗しました : %w", err)
 	}
 
+	// 補完で追加されたノードの役割と側を因果関係から推定する
+	logicGraph.InferNodeMetadata()
+
 	return logicGraph, nil
 }
 
@@ -96,9 +100,31 @@ func (completer *LogicGraphCompleter) CompleteTargetLogicNode(ctx context.Contex
 		logicGraph.AddNode(newNode)
 	}
 
-	for _, cause := range findNewArgumentsResult.UsedCauses {
-		targetNode.Causes = append(targetNode.Causes, logicGraph.NodeMap[cause])
+	// AIモデルが返した原因の文字列を既存ノードに対応付けてから因果関係を追加する
+	resolver := domain.NewArgumentResolverForLogicGraph(logicGraph)
+	resolvedCauses, unresolvedCauses := resolver.ResolveAll(findNewArgumentsResult.UsedCauses)
+	for _, err := range unresolvedCauses {
+		log.Printf("WARN: Skipping unresolved cause for '%s': %v", targetNode.Argument, err)
+	}
+	for _, resolved := range resolvedCauses {
+		if resolved.MatchType != domain.MatchTypeExact {
+			log.Printf("INFO: Resolved cause '%s' to '%s' (%s match)", resolved.Reference, resolved.Argument, resolved.MatchType)
+		}
+		causeNode := logicGraph.NodeMap[resolved.Argument]
+		if causeNode == targetNode || containsNode(targetNode.Causes, causeNode) {
+			continue
+		}
+		targetNode.Causes = append(targetNode.Causes, causeNode)
 	}
 
 	return newNodes, nil
 }
+
+func containsNode(nodes []*domain.LogicGraphNode, target *domain.LogicGraphNode) bool {
+	for _, node := range nodes {
+		if node == target {
+			return true
+		}
+	}
+	return false
+}
@@ -7,6 +7,7 @@ import (
 	createrebuttal "github.com/wolfmagnate/auto_debater/create_rebuttal"
 	"github.com/wolfmagnate/auto_debater/handler"
 	"github.com/wolfmagnate/auto_debater/logic_composer"
+	"github.com/wolfmagnate/auto_debater/strength_estimator"
 )
 
 func corsMiddleware(next http.Handler) http.Handler {
@@ -46,13 +47,34 @@ func main() {
 		log.Fatalf("FATAL: Failed to create logic enhancer: %v", err)
 	}
 
+	strengthEstimator, err := strength_estimator.CreateStrengthEstimator()
+	if err != nil {
+		log.Fatalf("FATAL: Failed to create strength estimator: %v", err)
+	}
+
 	// 2. ハンドラを初期化 (両方の依存を注入)
-	apiHandler := handler.NewHandler(rebuttalCreator, logicEnhancer, todoEnhancer)
+	apiHandler := handler.NewHandler(rebuttalCreator, logicEnhancer, todoEnhancer, strengthEstimator)
 
 	// 3. エンドポイントを登録
 	http.Handle("/api/create-rebuttal", corsMiddleware(http.HandlerFunc(apiHandler.CreateRebuttalEndpoint)))
 	http.Handle("/api/enhance-logic", corsMiddleware(http.HandlerFunc(apiHandler.EnhanceLogicEndpoint)))
 	http.Handle("/api/enhance-todo", corsMiddleware(http.HandlerFunc(apiHandler.EnhanceTODOEndpoint)))
+	http.Handle("/api/diff-graphs", corsMiddleware(http.HandlerFunc(apiHandler.DiffGraphsEndpoint)))
+	http.Handle("/api/highlight-document", corsMiddleware(http.HandlerFunc(apiHandler.HighlightDocumentEndpoint)))
+	http.Handle("/api/adjudicate", corsMiddleware(http.HandlerFunc(apiHandler.AdjudicateEndpoint)))
+	http.Handle("/api/propagate-beliefs", corsMiddleware(http.HandlerFunc(apiHandler.PropagateBeliefsEndpoint)))
+	http.Handle("/api/estimate-strengths", corsMiddleware(http.HandlerFunc(apiHandler.EstimateStrengthsEndpoint)))
+	http.Handle("/api/weakest-links", corsMiddleware(http.HandlerFunc(apiHandler.WeakestLinksEndpoint)))
+	http.Handle("/api/replay-debate", corsMiddleware(http.HandlerFunc(apiHandler.ReplayDebateEndpoint)))
+	http.Handle("/api/flow-sheet", corsMiddleware(http.HandlerFunc(apiHandler.FlowSheetEndpoint)))
+	http.Handle("/api/graph-report", corsMiddleware(http.HandlerFunc(apiHandler.GraphReportEndpoint)))
+	http.Handle("/api/render", corsMiddleware(http.HandlerFunc(apiHandler.RenderEndpoint)))
+	http.Handle("/api/case-report", corsMiddleware(http.HandlerFunc(apiHandler.CaseReportEndpoint)))
+	http.Handle("/api/export-graph", corsMiddleware(http.HandlerFunc(apiHandler.ExportGraphEndpoint)))
+	http.Handle("/api/import-graph", corsMiddleware(http.HandlerFunc(apiHandler.ImportGraphEndpoint)))
+	http.Handle("/api/export-graphs", corsMiddleware(http.HandlerFunc(apiHandler.ExportGraphsEndpoint)))
+	http.Handle("/api/jsonld-context", corsMiddleware(http.HandlerFunc(apiHandler.JSONLDContextEndpoint)))
+	http.Handle("/api/apply-operations", corsMiddleware(http.HandlerFunc(apiHandler.ApplyOperationsEndpoint)))
 
 	// 4. サーバーを起動
 	port := ":8080"
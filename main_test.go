@@ -75,7 +75,7 @@ func TestEnhanceLogicEndpoint_Integration(t *testing.T) {
 	}
 
 	// テスト対象のハンドラとテストサーバーをセットアップ
-	apiHandler := handler.NewHandler(rebuttalCreator, logicEnhancer, todoEnhancer)
+	apiHandler := handler.NewHandler(rebuttalCreator, logicEnhancer, todoEnhancer, nil)
 	testServer := httptest.NewServer(http.HandlerFunc(apiHandler.EnhanceLogicEndpoint))
 	defer testServer.Close()
 
@@ -172,7 +172,7 @@ func TestCreateRebuttalEndpoint_Integration(t *testing.T) {
 	}
 
 	// テスト対象のハンドラとテストサーバーをセットアップ
-	apiHandler := handler.NewHandler(rebuttalCreator, logicEnhancer, todoEnhancer)
+	apiHandler := handler.NewHandler(rebuttalCreator, logicEnhancer, todoEnhancer, nil)
 	testServer := httptest.NewServer(http.HandlerFunc(apiHandler.CreateRebuttalEndpoint))
 	defer testServer.Close()
 
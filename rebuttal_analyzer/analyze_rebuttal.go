@@ -3,6 +3,8 @@ package rebuttal_analyzer
 import (
 	"context"
 	"fmt"
+	"log"
+	"sync"
 
 	"github.com/wolfmagnate/auto_debater/domain"
 )
@@ -103,10 +105,19 @@ func (analyzer *RebuttalAnalyzer) AnalyzeRebuttal(ctx context.Context, debateGra
 			return fmt.Errorf("ノードが見つかりません: %s", targetArgumentAndCause.Argument)
 		}
 
-		for _, cause := range findNewArgumentResult.UsedCauses {
-			causeNode, exists := debateGraph.GetNode(cause)
-			if !exists {
-				return fmt.Errorf("ノードが見つかりません: %s", cause)
+		resolver := domain.NewArgumentResolverForDebateGraph(debateGraph)
+		resolvedCauses, unresolvedCauses := resolver.ResolveAll(findNewArgumentResult.UsedCauses)
+		for _, err := range unresolvedCauses {
+			log.Printf("WARN: Skipping unresolved cause for '%s': %v", effectNode.Argument, err)
+		}
+		for _, resolved := range resolvedCauses {
+			causeNode, _ := debateGraph.GetNode(resolved.Argument)
+			if causeNode == effectNode {
+				continue
+			}
+			// ターンで認めているノードからのエッジなど、既に存在するエッジは追加しない
+			if _, exists := debateGraph.GetEdge(causeNode.Argument, effectNode.Argument); exists {
+				continue
 			}
 			err = debateGraph.AddEdge(domain.NewDebateGraphEdge(causeNode, effectNode, true))
 			if err != nil {
@@ -121,54 +132,94 @@ func (analyzer *RebuttalAnalyzer) AnalyzeRebuttal(ctx context.Context, debateGra
 		return fmt.Errorf("failed to split document: %w", err)
 	}
 
-	var annotations []LogicAnnotation
-	for _, paragraph := range splittedDocument.Paragraphs {
-		paragraphAnnotations, err := analyzer.rebuttalAnnotationCreator.CreateRebuttalAnnotations(ctx, debateGraph, rebuttal, paragraph)
-		if err != nil {
+	// 段落ごとのアノテーションの作成はグラフを読むだけなので並行して行い、結果は段落の順序で適用する
+	paragraphResults := make([]*LogicAnnotations, len(splittedDocument.Paragraphs))
+	paragraphErrors := make([]error, len(splittedDocument.Paragraphs))
+	var wg sync.WaitGroup
+	for paragraphIndex, paragraph := range splittedDocument.Paragraphs {
+		wg.Add(1)
+		go func(paragraphIndex int, paragraph string) {
+			defer wg.Done()
+			paragraphResults[paragraphIndex], paragraphErrors[paragraphIndex] = analyzer.rebuttalAnnotationCreator.CreateRebuttalAnnotations(ctx, debateGraph, rebuttal, paragraph)
+		}(paragraphIndex, paragraph)
+	}
+	wg.Wait()
+
+	for paragraphIndex := range splittedDocument.Paragraphs {
+		if err := paragraphErrors[paragraphIndex]; err != nil {
 			return fmt.Errorf("failed to create debate annotations: %w", err)
 		}
-
-		if paragraphAnnotations != nil {
-			annotations = append(annotations, paragraphAnnotations.Annotations...)
+	}
+	for paragraphIndex, paragraph := range splittedDocument.Paragraphs {
+		if paragraphAnnotations := paragraphResults[paragraphIndex]; paragraphAnnotations != nil {
+			for _, ann := range paragraphAnnotations.Annotations {
+				ann.annotate(debateGraph, rebuttal, paragraphIndex, paragraph)
+			}
 		}
 	}
 
-	filteredAnnotations := []LogicAnnotation{}
-	for _, ann := range annotations {
-		if !(ann.TargetType == "node" && ann.NodeAnnotation.AnnotationType == "argument") {
-			filteredAnnotations = append(filteredAnnotations, ann)
+	return nil
+}
+
+// AnalyzeSpeech はスピーチの本文をAnalyzeRebuttalで解析し、その結果を新しい版としてdebateに追加します。
+// 直前の版は変更されません。最初のスピーチ (立論) のグラフはDebateGraphCreatorで作成し、debate.AddSpeechで追加してください。
+func (analyzer *RebuttalAnalyzer) AnalyzeSpeech(ctx context.Context, debate *domain.Debate, speech domain.Speech) error {
+	if len(debate.Speeches) == 0 {
+		return fmt.Errorf("スピーチ '%s' の解析には、立論のグラフが先に追加されている必要があります", speech.ID)
+	}
+
+	revision, err := debate.NextRevision()
+	if err != nil {
+		return fmt.Errorf("グラフの複製に失敗しました: %w", err)
+	}
+
+	if err := analyzer.AnalyzeRebuttal(ctx, revision, speech.Text); err != nil {
+		return fmt.Errorf("スピーチ '%s' の解析に失敗しました: %w", speech.ID, err)
+	}
+
+	if err := debate.AddSpeech(speech, revision); err != nil {
+		return fmt.Errorf("スピーチ '%s' の追加に失敗しました: %w", speech.ID, err)
+	}
+	return nil
+}
+
+// resolveTargetRelation はAIモデルが指定した反論ノードと対象から、グラフ内の反論関係を1つに特定する
+// 反論ノードによる反論関係が1つしかなければ対象の指定は使わず、複数ある場合は対象が一致するものを選ぶ
+func resolveTargetRelation(debateGraph *domain.DebateGraph, relationRebuttal *RelationRebuttal) (domain.RebuttalRelation, error) {
+	rebuttalNode, _, err := debateGraph.ResolveNode(relationRebuttal.TargetRebuttalArgument)
+	if err != nil {
+		return domain.RebuttalRelation{}, err
+	}
+
+	candidates := make([]domain.RebuttalRelation, 0)
+	for _, relation := range debateGraph.RebuttalRelations() {
+		if relation.RebuttalArgument == rebuttalNode.Argument {
+			candidates = append(candidates, relation)
 		}
 	}
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	if len(candidates) == 0 {
+		return domain.RebuttalRelation{}, fmt.Errorf("'%s' による反論関係がグラフ内にありません", rebuttalNode.Argument)
+	}
 
-	for _, ann := range filteredAnnotations {
-		if ann.TargetType == "node" {
-			targetNode, exists := debateGraph.GetNode(ann.NodeAnnotation.Argument)
-			if !exists {
-				fmt.Printf("Warning: Annotation for non-existent node '%s' skipped.\n", ann.NodeAnnotation.Argument)
-				continue
-			}
-			switch ann.NodeAnnotation.AnnotationType {
-			case "importance":
-				targetNode.Importance = append(targetNode.Importance, ann.NodeAnnotation.Importance)
-			case "uniqueness":
-				targetNode.Uniqueness = append(targetNode.Uniqueness, ann.NodeAnnotation.Uniqueness)
-			}
-		} else if ann.TargetType == "edge" {
-			targetEdge, exists := debateGraph.GetEdge(ann.EdgeAnnotation.CauseArgument, ann.EdgeAnnotation.EffectArgument)
-			if !exists {
-				fmt.Printf("Warning: Annotation for non-existent edge '%s -> %s' skipped.\n", ann.EdgeAnnotation.CauseArgument, ann.EdgeAnnotation.EffectArgument)
-				continue
-			}
-			switch ann.EdgeAnnotation.AnnotationType {
-			case "certainty":
-				targetEdge.Certainty = append(targetEdge.Certainty, ann.EdgeAnnotation.Certainty)
-			case "uniqueness":
-				targetEdge.Uniqueness = append(targetEdge.Uniqueness, ann.EdgeAnnotation.Uniqueness)
+	targetNode, _, err := debateGraph.ResolveNode(relationRebuttal.TargetArgument)
+	if err != nil {
+		return domain.RebuttalRelation{}, fmt.Errorf("'%s' による反論関係が複数あり、対象も特定できません: %w", rebuttalNode.Argument, err)
+	}
+	for _, relation := range candidates {
+		if relation.TargetArgument == targetNode.Argument || relation.TargetCauseArgument == targetNode.Argument {
+			return relation, nil
+		}
+		// ターンが攻撃しているのは認めているノード
+		for _, conceded := range relation.TargetCauseArguments {
+			if conceded == targetNode.Argument {
+				return relation, nil
 			}
 		}
 	}
-
-	return nil
+	return domain.RebuttalRelation{}, fmt.Errorf("'%s' による '%s' への反論関係がグラフ内にありません", rebuttalNode.Argument, targetNode.Argument)
 }
 
 // debateGraphに対応する反論ノードを作成する
@@ -178,6 +229,11 @@ func addRebuttalToDebateGraph(debateGraph *domain.DebateGraph, otherRebuttal Reb
 	switch otherRebuttal.RebuttalKind {
 	case "edge_rebuttal":
 		edgeRebuttal := otherRebuttal.EdgeRebuttal
+		targetEdge, err := debateGraph.ResolveEdge(edgeRebuttal.TargetEdgeCause, edgeRebuttal.TargetEdgeEffect)
+		if err != nil {
+			return nil, fmt.Errorf("エッジに対する反論の対象が特定できません: %w", err)
+		}
+		targetCause, targetEffect := targetEdge.Cause.Argument, targetEdge.Effect.Argument
 		switch edgeRebuttal.RebuttalType {
 		case "certainty":
 			newNode := domain.NewDebateGraphNode(edgeRebuttal.CertaintyRebuttal, true)
@@ -185,14 +241,16 @@ func addRebuttalToDebateGraph(debateGraph *domain.DebateGraph, otherRebuttal Reb
 			if err != nil {
 				return nil, fmt.Errorf("エッジに対する反論の作成に失敗しました: %w", err)
 			}
-			debateGraphEdgeRebuttal, err := domain.NewDebateGraphEdgeRebuttal(
-				debateGraph, edgeRebuttal.TargetEdgeCause, edgeRebuttal.TargetEdgeEffect,
-				"certainty", edgeRebuttal.CertaintyRebuttal,
-			)
+			err = debateGraph.AddRebuttalRelation(domain.RebuttalRelation{
+				Kind:                 domain.RebuttalKindEdge,
+				TargetCauseArgument:  targetCause,
+				TargetEffectArgument: targetEffect,
+				RebuttalType:         "certainty",
+				RebuttalArgument:     edgeRebuttal.CertaintyRebuttal,
+			})
 			if err != nil {
 				return nil, fmt.Errorf("エッジに対する反論の作成に失敗しました: %w", err)
 			}
-			debateGraph.EdgeRebuttals = append(debateGraph.EdgeRebuttals, debateGraphEdgeRebuttal)
 			return newNode, nil
 		case "uniqueness":
 			newNode := domain.NewDebateGraphNode(edgeRebuttal.UniquenessRebuttal, true)
@@ -200,18 +258,24 @@ func addRebuttalToDebateGraph(debateGraph *domain.DebateGraph, otherRebuttal Reb
 			if err != nil {
 				return nil, fmt.Errorf("エッジに対する反論の作成に失敗しました: %w", err)
 			}
-			debateGraphEdgeRebuttal, err := domain.NewDebateGraphEdgeRebuttal(
-				debateGraph, edgeRebuttal.TargetEdgeCause, edgeRebuttal.TargetEdgeEffect,
-				"uniqueness", edgeRebuttal.UniquenessRebuttal,
-			)
+			err = debateGraph.AddRebuttalRelation(domain.RebuttalRelation{
+				Kind:                 domain.RebuttalKindEdge,
+				TargetCauseArgument:  targetCause,
+				TargetEffectArgument: targetEffect,
+				RebuttalType:         "uniqueness",
+				RebuttalArgument:     edgeRebuttal.UniquenessRebuttal,
+			})
 			if err != nil {
 				return nil, fmt.Errorf("エッジに対する反論の作成に失敗しました: %w", err)
 			}
-			debateGraph.EdgeRebuttals = append(debateGraph.EdgeRebuttals, debateGraphEdgeRebuttal)
 			return newNode, nil
 		}
 	case "node_rebuttal":
 		nodeRebuttal := otherRebuttal.NodeRebuttal
+		targetNode, _, err := debateGraph.ResolveNode(nodeRebuttal.TargetNode)
+		if err != nil {
+			return nil, fmt.Errorf("ノードに対する反論の対象が特定できません: %w", err)
+		}
 		switch nodeRebuttal.RebuttalType {
 		case "importance":
 			newNode := domain.NewDebateGraphNode(nodeRebuttal.ImportanceRebuttal, true)
@@ -219,14 +283,15 @@ func addRebuttalToDebateGraph(debateGraph *domain.DebateGraph, otherRebuttal Reb
 			if err != nil {
 				return nil, fmt.Errorf("ノードに対する反論の作成に失敗しました: %w", err)
 			}
-			debateGraphNodeRebuttal, err := domain.NewDebateGraphNodeRebuttal(
-				debateGraph, nodeRebuttal.TargetNode,
-				"importance", nodeRebuttal.ImportanceRebuttal,
-			)
+			err = debateGraph.AddRebuttalRelation(domain.RebuttalRelation{
+				Kind:             domain.RebuttalKindNode,
+				TargetArgument:   targetNode.Argument,
+				RebuttalType:     "importance",
+				RebuttalArgument: nodeRebuttal.ImportanceRebuttal,
+			})
 			if err != nil {
 				return nil, fmt.Errorf("ノードに対する反論の作成に失敗しました: %w", err)
 			}
-			debateGraph.NodeRebuttals = append(debateGraph.NodeRebuttals, debateGraphNodeRebuttal)
 			return newNode, nil
 		case "uniqueness":
 			newNode := domain.NewDebateGraphNode(nodeRebuttal.UniquenessRebuttal, true)
@@ -234,42 +299,80 @@ func addRebuttalToDebateGraph(debateGraph *domain.DebateGraph, otherRebuttal Reb
 			if err != nil {
 				return nil, fmt.Errorf("ノードに対する反論の作成に失敗しました: %w", err)
 			}
-			debateGraphNodeRebuttal, err := domain.NewDebateGraphNodeRebuttal(
-				debateGraph, nodeRebuttal.TargetNode,
-				"uniqueness", nodeRebuttal.UniquenessRebuttal,
-			)
+			err = debateGraph.AddRebuttalRelation(domain.RebuttalRelation{
+				Kind:             domain.RebuttalKindNode,
+				TargetArgument:   targetNode.Argument,
+				RebuttalType:     "uniqueness",
+				RebuttalArgument: nodeRebuttal.UniquenessRebuttal,
+			})
 			if err != nil {
 				return nil, fmt.Errorf("ノードに対する反論の作成に失敗しました: %w", err)
 			}
-			debateGraph.NodeRebuttals = append(debateGraph.NodeRebuttals, debateGraphNodeRebuttal)
 			return newNode, nil
 		}
 	case "counter_argument":
 		counterArgument := otherRebuttal.CounterArgument
+		targetNode, _, err := debateGraph.ResolveNode(counterArgument.TargetNode)
+		if err != nil {
+			return nil, fmt.Errorf("反対意見の対象が特定できません: %w", err)
+		}
 		counterDebateGraphNode := domain.NewDebateGraphNode(counterArgument.Argument, true)
-		err := debateGraph.AddNode(counterDebateGraphNode)
+		err = debateGraph.AddNode(counterDebateGraphNode)
 		if err != nil {
 			return nil, fmt.Errorf("反論の追加に失敗しました: %w", err)
 		}
-		counterArgumentRebuttal, err := domain.NewCounterArgumentRebuttal(debateGraph, counterArgument.TargetNode, counterArgument.Argument)
+		err = debateGraph.AddRebuttalRelation(domain.RebuttalRelation{
+			Kind:             domain.RebuttalKindCounterArgument,
+			TargetArgument:   targetNode.Argument,
+			RebuttalArgument: counterArgument.Argument,
+		})
 		if err != nil {
 			return nil, fmt.Errorf("反論の追加に失敗しました: %w", err)
 		}
-		debateGraph.CounterArgumentRebuttals = append(debateGraph.CounterArgumentRebuttals, counterArgumentRebuttal)
 		return counterDebateGraphNode, nil
 	case "turn_argument":
 		turnArgument := otherRebuttal.TurnArgument
+		// 認めている範囲のノードを既存のノードに解決する
+		resolver := domain.NewArgumentResolverForDebateGraph(debateGraph)
+		resolvedCauses, unresolvedCauses := resolver.ResolveAll(turnArgument.TargetCauseNodes)
+		for _, err := range unresolvedCauses {
+			log.Printf("WARN: Skipping unresolved conceded node for turn '%s': %v", turnArgument.EffectArgument, err)
+		}
+		targetCauseArguments := make([]string, 0, len(resolvedCauses))
+		for _, resolved := range resolvedCauses {
+			targetCauseArguments = append(targetCauseArguments, resolved.Argument)
+		}
+
 		turnDebateGraphNode := domain.NewDebateGraphNode(turnArgument.EffectArgument, true)
 		err := debateGraph.AddNode(turnDebateGraphNode)
 		if err != nil {
 			return nil, fmt.Errorf("反論の追加に失敗しました: %w", err)
 		}
-		turnArgumentRebuttal, err := domain.NewTurnArgumentRebuttal(debateGraph, turnArgument.EffectArgument)
+		_, err = debateGraph.AddTurnArgumentRebuttal(turnArgument.EffectArgument, targetCauseArguments)
 		if err != nil {
 			return nil, fmt.Errorf("反論の追加に失敗しました: %w", err)
 		}
-		debateGraph.TurnArgumentRebuttals = append(debateGraph.TurnArgumentRebuttals, turnArgumentRebuttal)
 		return turnDebateGraphNode, nil
+	case "relation_rebuttal":
+		relationRebuttal := otherRebuttal.RelationRebuttal
+		targetRelation, err := resolveTargetRelation(debateGraph, relationRebuttal)
+		if err != nil {
+			return nil, fmt.Errorf("反論関係に対する反論の対象が特定できません: %w", err)
+		}
+		relationDebateGraphNode := domain.NewDebateGraphNode(relationRebuttal.Argument, true)
+		err = debateGraph.AddNode(relationDebateGraphNode)
+		if err != nil {
+			return nil, fmt.Errorf("反論の追加に失敗しました: %w", err)
+		}
+		err = debateGraph.AddRebuttalRelation(domain.RebuttalRelation{
+			Kind:             domain.RebuttalKindRelation,
+			TargetRelation:   &targetRelation,
+			RebuttalArgument: relationRebuttal.Argument,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("反論の追加に失敗しました: %w", err)
+		}
+		return relationDebateGraphNode, nil
 	}
 	return nil, fmt.Errorf("不明な反論の種類です: %s", otherRebuttal.RebuttalKind)
 }
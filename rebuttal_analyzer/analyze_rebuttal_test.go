@@ -0,0 +1,116 @@
+package rebuttal_analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+// newRelationTestGraph は「減税分は内部留保に回る」が4種類の反論関係すべてを行っているグラフを作成します。
+func newRelationTestGraph(t *testing.T) *domain.DebateGraph {
+	t.Helper()
+	dg := domain.NewDebateGraph()
+	for _, node := range []*domain.DebateGraphNode{
+		domain.NewDebateGraphNode("法人税を減税する", false),
+		domain.NewDebateGraphNode("企業の投資が増える", false),
+		domain.NewDebateGraphNode("雇用が増える", false),
+		domain.NewDebateGraphNode("減税分は内部留保に回る", true),
+	} {
+		require.NoError(t, dg.AddNode(node))
+	}
+	for _, pair := range [][2]string{{"法人税を減税する", "企業の投資が増える"}, {"企業の投資が増える", "雇用が増える"}} {
+		cause, _ := dg.GetNode(pair[0])
+		effect, _ := dg.GetNode(pair[1])
+		require.NoError(t, dg.AddEdge(domain.NewDebateGraphEdge(cause, effect, false)))
+	}
+	return dg
+}
+
+func TestResolveTargetRelation(t *testing.T) {
+	const rebuttal = "減税分は内部留保に回る"
+	nodeRelation := domain.RebuttalRelation{Kind: domain.RebuttalKindNode, TargetArgument: "雇用が増える", RebuttalType: "importance", RebuttalArgument: rebuttal}
+	edgeRelation := domain.RebuttalRelation{
+		Kind: domain.RebuttalKindEdge, TargetCauseArgument: "法人税を減税する", TargetEffectArgument: "企業の投資が増える",
+		RebuttalType: "certainty", RebuttalArgument: rebuttal,
+	}
+	counterRelation := domain.RebuttalRelation{Kind: domain.RebuttalKindCounterArgument, TargetArgument: "企業の投資が増える", RebuttalArgument: rebuttal}
+	turnRelation := domain.RebuttalRelation{Kind: domain.RebuttalKindTurnArgument, TargetCauseArguments: []string{"法人税を減税する"}, RebuttalArgument: rebuttal}
+
+	tests := []struct {
+		name      string
+		relations []domain.RebuttalRelation
+		target    string
+		want      domain.RebuttalRelation
+	}{
+		// 反論ノードによる反論関係が1つだけなら、対象の指定に関わらずそれを選ぶ
+		{"only node rebuttal", []domain.RebuttalRelation{nodeRelation}, "", nodeRelation},
+		{"only edge rebuttal", []domain.RebuttalRelation{edgeRelation}, "存在しない主張", edgeRelation},
+		// 複数ある場合は対象で選ぶ
+		{"node rebuttal by target", []domain.RebuttalRelation{nodeRelation, edgeRelation}, "雇用が増える", nodeRelation},
+		{"edge rebuttal by cause", []domain.RebuttalRelation{nodeRelation, edgeRelation}, "法人税を減税する", edgeRelation},
+		{"counter argument by target", []domain.RebuttalRelation{nodeRelation, counterRelation}, "企業の投資が増える", counterRelation},
+		{"turn by conceded node", []domain.RebuttalRelation{nodeRelation, turnRelation}, "法人税を減税する", turnRelation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dg := newRelationTestGraph(t)
+			for _, relation := range tt.relations {
+				require.NoError(t, dg.AddRebuttalRelation(relation))
+			}
+
+			got, err := resolveTargetRelation(dg, &RelationRebuttal{TargetRebuttalArgument: rebuttal, TargetArgument: tt.target})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Key(), got.Key())
+		})
+	}
+}
+
+func TestResolveTargetRelationNotFound(t *testing.T) {
+	const rebuttal = "減税分は内部留保に回る"
+	dg := newRelationTestGraph(t)
+
+	// 反論ノード自体が存在しない
+	_, err := resolveTargetRelation(dg, &RelationRebuttal{TargetRebuttalArgument: "減税分は配当に回る"})
+	assert.Error(t, err)
+
+	// 反論ノードはあるが、反論関係がない
+	_, err = resolveTargetRelation(dg, &RelationRebuttal{TargetRebuttalArgument: rebuttal})
+	assert.Error(t, err)
+
+	// 反論関係が複数あり、指定された対象への反論関係がない
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{Kind: domain.RebuttalKindCounterArgument, TargetArgument: "企業の投資が増える", RebuttalArgument: rebuttal}))
+	require.NoError(t, dg.AddRebuttalRelation(domain.RebuttalRelation{Kind: domain.RebuttalKindNode, TargetArgument: "雇用が増える", RebuttalType: "importance", RebuttalArgument: rebuttal}))
+	_, err = resolveTargetRelation(dg, &RelationRebuttal{TargetRebuttalArgument: rebuttal, TargetArgument: "法人税を減税する"})
+	assert.Error(t, err)
+	_, err = resolveTargetRelation(dg, &RelationRebuttal{TargetRebuttalArgument: rebuttal, TargetArgument: "存在しない主張"})
+	assert.Error(t, err)
+}
+
+func TestAddRelationRebuttalToDebateGraph(t *testing.T) {
+	const rebuttal = "減税分は内部留保に回る"
+	dg := newRelationTestGraph(t)
+	target := domain.RebuttalRelation{Kind: domain.RebuttalKindCounterArgument, TargetArgument: "企業の投資が増える", RebuttalArgument: rebuttal}
+	require.NoError(t, dg.AddRebuttalRelation(target))
+
+	node, err := addRebuttalToDebateGraph(dg, RebuttalItem{
+		RebuttalKind: "relation_rebuttal",
+		RelationRebuttal: &RelationRebuttal{
+			TargetRebuttalArgument: rebuttal, TargetArgument: "企業の投資が増える", Argument: "内部留保も設備投資に使われる",
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, node.IsRebuttal)
+	require.Len(t, dg.RelationRebuttals, 1)
+	assert.Equal(t, target.Key(), dg.RelationRebuttals[0].TargetRelation.Key())
+
+	// 対象の反論関係が特定できない場合は、ノードを追加せずにエラーを返す
+	_, err = addRebuttalToDebateGraph(dg, RebuttalItem{
+		RebuttalKind:     "relation_rebuttal",
+		RelationRebuttal: &RelationRebuttal{TargetRebuttalArgument: "減税分は配当に回る", Argument: "配当も消費に回る"},
+	})
+	assert.Error(t, err)
+	_, exists := dg.GetNode("配当も消費に回る")
+	assert.False(t, exists)
+}
@@ -46,6 +46,34 @@ type LogicAnnotation struct {
 	EdgeAnnotation EdgeAnnotation `json:"edge_annotation"` // TargetTypeが"edge"のときのみ有効
 }
 
+// annotate は段落 paragraph から作成したアノテーションを、TargetText を引用箇所とする根拠として debateGraph に追加します。
+// "argument" のアノテーションはノードが述べられている範囲としてのみ使用します。
+func (ann LogicAnnotation) annotate(debateGraph *domain.DebateGraph, document string, paragraphIndex int, paragraph string) {
+	var annotationType, claim string
+	switch ann.TargetType {
+	case "node":
+		annotationType, claim = ann.NodeAnnotation.typeAndClaim()
+	case "edge":
+		annotationType, claim = ann.EdgeAnnotation.typeAndClaim()
+	default:
+		return
+	}
+	evidence, err := domain.NewEvidenceFromExcerpt(document, paragraphIndex, paragraph, ann.TargetText, claim)
+	if err != nil {
+		log.Printf("WARN: Could not locate annotation source: %v", err)
+	}
+
+	if ann.TargetType == "node" {
+		if err := debateGraph.AnnotateNode(ann.NodeAnnotation.Argument, annotationType, evidence); err != nil {
+			log.Printf("WARN: Annotation for node '%s' skipped: %v", ann.NodeAnnotation.Argument, err)
+		}
+		return
+	}
+	if err := debateGraph.AnnotateEdge(ann.EdgeAnnotation.CauseArgument, ann.EdgeAnnotation.EffectArgument, annotationType, evidence); err != nil {
+		log.Printf("WARN: Annotation for edge '%s -> %s' skipped: %v", ann.EdgeAnnotation.CauseArgument, ann.EdgeAnnotation.EffectArgument, err)
+	}
+}
+
 type NodeAnnotation struct {
 	AnnotationType string `json:"annotation_type"` // "argument"または"importance"または"uniqueness"または"importance_rebuttal"または"uniqueness_rebuttal"のいずれか
 	Argument       string `json:"argument"`        // アノテーションを行う対象の論理構造グラフのノード
@@ -61,8 +89,31 @@ type EdgeAnnotation struct {
 	Uniqueness     string `json:"uniqueness"`      // なぜCauseArgumentがStatus QuoまたはAffirmative Planでのみ発生するのかの理由を表す文章。AnnotationTypeが"uniqueness"のときのみ有効
 }
 
+// typeAndClaim はアノテーションの種類に対応する domain の種類と本文を返します。
+// "argument" など本文を持たない種類の場合は空文字列を返します。
+func (ann NodeAnnotation) typeAndClaim() (string, string) {
+	switch ann.AnnotationType {
+	case "importance":
+		return domain.NodeAnnotationImportance, ann.Importance
+	case "uniqueness":
+		return domain.NodeAnnotationUniqueness, ann.Uniqueness
+	}
+	return "", ""
+}
+
+// typeAndClaim はアノテーションの種類に対応する domain の種類と本文を返します。
+func (ann EdgeAnnotation) typeAndClaim() (string, string) {
+	switch ann.AnnotationType {
+	case "certainty":
+		return domain.EdgeAnnotationCertainty, ann.Certainty
+	case "uniqueness":
+		return domain.EdgeAnnotationUniqueness, ann.Uniqueness
+	}
+	return "", ""
+}
+
 func (analyzer *RebuttalAnnotationCreator) CreateRebuttalAnnotations(ctx context.Context, debateGraph *domain.DebateGraph, rebuttal, targetParagraph string) (*LogicAnnotations, error) {
-	debateGraphJSON, err := debateGraph.ToJSON()
+	debateGraphJSON, err := debateGraph.ToCompactJSON()
 	if err != nil {
 		return nil, fmt.Errorf("ディベートグラフのJSON化に失敗しました: %w", err)
 	}
@@ -59,7 +59,7 @@ func (finder *NewArgumentFinder) FindNewArguments(ctx context.Context, debateGra
 		return nil, fmt.Errorf("ArgumentAndCausesのJSON文字列変換に失敗しました: %w", err)
 	}
 
-	debateGraphJSON, err := debateGraph.ToJSON()
+	debateGraphJSON, err := debateGraph.ToCompactJSON()
 	if err != nil {
 		return nil, fmt.Errorf("DebateGraphのJSON文字列変換に失敗しました: %w", err)
 	}
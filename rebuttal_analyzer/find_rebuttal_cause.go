@@ -41,7 +41,7 @@ type FoundCauses struct {
 }
 
 func (finder *RebuttalCauseFinder) FindRebuttalCauses(ctx context.Context, debateGraph *domain.DebateGraph, rebuttal string, targetArgument string) (*FoundCauses, error) {
-	debateGraphJSON, err := debateGraph.ToJSON()
+	debateGraphJSON, err := debateGraph.ToCompactJSON()
 	if err != nil {
 		return nil, fmt.Errorf("DebateGraphのJSON作成に失敗しました: %w", err)
 	}
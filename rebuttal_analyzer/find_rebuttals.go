@@ -64,16 +64,26 @@ type TurnArgument struct {
 	TargetCauseNodes []string `json:"target_cause_nodes"` // 途中まで元の主張の議論構造グラフのノードを認めている。どこまで認めているか。
 	EffectArgument   string   `json:"effect_argument"`    // 反論では最終的にどのようなメリット・デメリットを主張しているか
 }
+
+// 既に行われた反論が、その対象に当てはまらないという反論
+// 例えば、反論が元の主張を誤解している、対象とは別の状況について述べている、といった指摘
+type RelationRebuttal struct {
+	TargetRebuttalArgument string `json:"target_rebuttal_argument"` // どの反論に反論するか。反論を行ったノードのArgument
+	TargetArgument         string `json:"target_argument"`          // その反論が攻撃していたノードのArgument。エッジの場合は原因のノードのArgument
+	Argument               string `json:"argument"`                 // 反論が対象に当てはまらない理由
+}
+
 type RebuttalItem struct {
 	// RebuttalKind はこのアイテムがどの種類の反論であるかを示します。以下のいずれかの値のみをとります。
-	// "edge_rebuttal", "node_rebuttal", "counter_argument", "turn_argument"
+	// "edge_rebuttal", "node_rebuttal", "counter_argument", "turn_argument", "relation_rebuttal"
 	RebuttalKind string `json:"rebuttal_kind"`
 
 	// RebuttalKindに対応するものが1つだけ設定されます。
-	EdgeRebuttal    *EdgeRebuttal    `json:"edge_rebuttal,omitempty"`
-	NodeRebuttal    *NodeRebuttal    `json:"node_rebuttal,omitempty"`
-	CounterArgument *CounterArgument `json:"counter_argument,omitempty"`
-	TurnArgument    *TurnArgument    `json:"turn_argument,omitempty"`
+	EdgeRebuttal     *EdgeRebuttal     `json:"edge_rebuttal,omitempty"`
+	NodeRebuttal     *NodeRebuttal     `json:"node_rebuttal,omitempty"`
+	CounterArgument  *CounterArgument  `json:"counter_argument,omitempty"`
+	TurnArgument     *TurnArgument     `json:"turn_argument,omitempty"`
+	RelationRebuttal *RelationRebuttal `json:"relation_rebuttal,omitempty"`
 }
 
 // AnalyzedRebuttals は、すべての反論分析結果を格納するトップレベルの構造体です。
@@ -82,7 +92,7 @@ type AnalyzedRebuttals struct {
 }
 
 func (finder *RebuttalFinder) FindRebuttals(ctx context.Context, debateGraph *domain.DebateGraph, rebuttal string) (*AnalyzedRebuttals, error) {
-	debateGraphJSON, err := debateGraph.ToJSON()
+	debateGraphJSON, err := debateGraph.ToCompactJSON()
 	if err != nil {
 		return nil, fmt.Errorf("DebateGraphのJSON作成に失敗しました: %w", err)
 	}
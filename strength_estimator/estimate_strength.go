@@ -0,0 +1,149 @@
+package strength_estimator
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/wolfmagnate/auto_debater/domain"
+	"github.com/wolfmagnate/auto_debater/infra"
+)
+
+//go:embed estimate_strength_prompt.md
+var estimateStrengthPromptMarkdown string
+
+// certaintyLevelProbabilities はAIモデルが選んだ確実性の段階を、エッジの確率に対応付けます。
+var certaintyLevelProbabilities = map[string]float64{
+	"very_high": 0.9,
+	"high":      0.75,
+	"medium":    0.5,
+	"low":       0.25,
+	"very_low":  0.1,
+}
+
+// magnitudeLevelValues はAIモデルが選んだ大きさの段階を、影響ノードの大きさに対応付けます。
+// 未推定のノードの大きさ (domain.DefaultImpactMagnitude) が moderate と同じになるようにしています。
+var magnitudeLevelValues = map[string]float64{
+	"critical":   4,
+	"major":      2,
+	"moderate":   1,
+	"minor":      0.5,
+	"negligible": 0.25,
+}
+
+type StrengthEstimator struct {
+	tmpl *template.Template
+}
+
+func CreateStrengthEstimator() (*StrengthEstimator, error) {
+	tmpl, err := template.New("prompt").Parse(estimateStrengthPromptMarkdown)
+
+	if err != nil {
+		return nil, fmt.Errorf("起動時のテンプレート解析に失敗しました: %w", err)
+	}
+
+	return &StrengthEstimator{tmpl: tmpl}, nil
+}
+
+type EstimateStrengthTemplateData struct {
+	DebateGraphJSON string
+	ImpactArguments string
+}
+
+type StrengthEstimates struct {
+	Edges   []EdgeStrengthEstimate    `json:"edges"`
+	Impacts []ImpactMagnitudeEstimate `json:"impacts"`
+}
+
+type EdgeStrengthEstimate struct {
+	CauseArgument  string `json:"cause_argument"`
+	EffectArgument string `json:"effect_argument"`
+	CertaintyLevel string `json:"certainty_level"` // "very_high", "high", "medium", "low", "very_low" のいずれか
+	Reason         string `json:"reason"`
+}
+
+type ImpactMagnitudeEstimate struct {
+	Argument       string `json:"argument"`
+	MagnitudeLevel string `json:"magnitude_level"` // "critical", "major", "moderate", "minor", "negligible" のいずれか
+	Reason         string `json:"reason"`
+}
+
+// EstimateStrengths は、エッジのcertainty・uniquenessと影響ノードのimportanceのアノテーションをもとに、
+// 各エッジの確実性と各影響の大きさを段階評価させます。
+func (estimator *StrengthEstimator) EstimateStrengths(ctx context.Context, debateGraph *domain.DebateGraph) (*StrengthEstimates, error) {
+	debateGraphJSON, err := debateGraph.ToCompactJSON()
+	if err != nil {
+		return nil, fmt.Errorf("ディベートグラフのJSON化に失敗しました: %w", err)
+	}
+
+	impactArguments := make([]string, 0)
+	for _, node := range debateGraph.ImpactNodes() {
+		impactArguments = append(impactArguments, "- "+node.Argument)
+	}
+
+	data := EstimateStrengthTemplateData{
+		DebateGraphJSON: debateGraphJSON,
+		ImpactArguments: strings.Join(impactArguments, "\n"),
+	}
+
+	var processedPrompt bytes.Buffer
+	err = estimator.tmpl.Execute(&processedPrompt, data)
+	if err != nil {
+		log.Printf("テンプレートの実行に失敗しました: %v", err)
+		return nil, fmt.Errorf("テンプレートの実行に失敗しました: %w", err)
+	}
+
+	promptString := processedPrompt.String()
+
+	thinkingBudget := int32(8_000)
+	estimates, _, err := infra.ChatCompletionHandler[StrengthEstimates](ctx, promptString, &thinkingBudget)
+	if err != nil {
+		return nil, fmt.Errorf("AIモデルの呼び出しに失敗しました: %w", err)
+	}
+
+	return estimates, nil
+}
+
+// ApplyStrengthEstimates は段階評価を数値に変換し、まだ値が設定されていないエッジの確率と影響ノードの大きさに設定します。
+// 既に値が設定されている場合は、利用者が調整した値を優先して上書きしません。
+// 評価の対象はArgumentの完全一致でだけ特定します。似た別の主張に値を設定してしまうことを避けるためです。
+// 対象が見つからない評価や未定義の段階は適用せず、その理由を返します。
+func ApplyStrengthEstimates(debateGraph *domain.DebateGraph, estimates *StrengthEstimates) []error {
+	skipped := make([]error, 0)
+	for _, estimate := range estimates.Edges {
+		probability, ok := certaintyLevelProbabilities[estimate.CertaintyLevel]
+		if !ok {
+			skipped = append(skipped, fmt.Errorf("unknown certainty level '%s' for edge '%s -> %s'", estimate.CertaintyLevel, estimate.CauseArgument, estimate.EffectArgument))
+			continue
+		}
+		edge, exists := debateGraph.GetEdge(estimate.CauseArgument, estimate.EffectArgument)
+		if !exists {
+			skipped = append(skipped, fmt.Errorf("edge '%s -> %s' not found in debate graph", estimate.CauseArgument, estimate.EffectArgument))
+			continue
+		}
+		if edge.Probability == nil {
+			edge.Probability = &probability
+		}
+	}
+
+	for _, estimate := range estimates.Impacts {
+		magnitude, ok := magnitudeLevelValues[estimate.MagnitudeLevel]
+		if !ok {
+			skipped = append(skipped, fmt.Errorf("unknown magnitude level '%s' for node '%s'", estimate.MagnitudeLevel, estimate.Argument))
+			continue
+		}
+		node, exists := debateGraph.GetNode(estimate.Argument)
+		if !exists {
+			skipped = append(skipped, fmt.Errorf("node '%s' not found in debate graph", estimate.Argument))
+			continue
+		}
+		if node.Magnitude == nil {
+			node.Magnitude = &magnitude
+		}
+	}
+	return skipped
+}
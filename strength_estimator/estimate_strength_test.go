@@ -0,0 +1,124 @@
+package strength_estimator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfmagnate/auto_debater/domain"
+)
+
+func TestStrengthLevelMapping(t *testing.T) {
+	certaintyTests := []struct {
+		level       string
+		probability float64
+	}{
+		{"very_high", 0.9},
+		{"high", 0.75},
+		{"medium", 0.5},
+		{"low", 0.25},
+		{"very_low", 0.1},
+	}
+	for _, tt := range certaintyTests {
+		t.Run(tt.level, func(t *testing.T) {
+			assert.Equal(t, tt.probability, certaintyLevelProbabilities[tt.level])
+		})
+	}
+	assert.Len(t, certaintyLevelProbabilities, len(certaintyTests))
+
+	magnitudeTests := []struct {
+		level     string
+		magnitude float64
+	}{
+		{"critical", 4},
+		{"major", 2},
+		{"moderate", domain.DefaultImpactMagnitude},
+		{"minor", 0.5},
+		{"negligible", 0.25},
+	}
+	for _, tt := range magnitudeTests {
+		t.Run(tt.level, func(t *testing.T) {
+			assert.Equal(t, tt.magnitude, magnitudeLevelValues[tt.level])
+		})
+	}
+	assert.Len(t, magnitudeLevelValues, len(magnitudeTests))
+}
+
+func newStrengthTestGraph(t *testing.T) *domain.DebateGraph {
+	t.Helper()
+	dg := domain.NewDebateGraph()
+	for _, argument := range []string{"法人税率を引き下げる", "企業の投資が増える", "雇用が増える"} {
+		require.NoError(t, dg.AddNode(domain.NewDebateGraphNode(argument, false)))
+	}
+	for _, pair := range [][2]string{{"法人税率を引き下げる", "企業の投資が増える"}, {"企業の投資が増える", "雇用が増える"}} {
+		cause, _ := dg.GetNode(pair[0])
+		effect, _ := dg.GetNode(pair[1])
+		require.NoError(t, dg.AddEdge(domain.NewDebateGraphEdge(cause, effect, false)))
+	}
+	return dg
+}
+
+func TestApplyStrengthEstimates(t *testing.T) {
+	preset := 0.3
+	tests := []struct {
+		name            string
+		estimates       StrengthEstimates
+		presetEdge      bool
+		wantProbability *float64
+		wantMagnitude   *float64
+		wantSkipped     int
+	}{
+		{
+			name: "applies levels to exact matches",
+			estimates: StrengthEstimates{
+				Edges:   []EdgeStrengthEstimate{{CauseArgument: "企業の投資が増える", EffectArgument: "雇用が増える", CertaintyLevel: "high"}},
+				Impacts: []ImpactMagnitudeEstimate{{Argument: "雇用が増える", MagnitudeLevel: "major"}},
+			},
+			wantProbability: ptr(0.75),
+			wantMagnitude:   ptr(2),
+		},
+		{
+			name: "keeps values set by the user",
+			estimates: StrengthEstimates{
+				Edges: []EdgeStrengthEstimate{{CauseArgument: "企業の投資が増える", EffectArgument: "雇用が増える", CertaintyLevel: "very_high"}},
+			},
+			presetEdge:      true,
+			wantProbability: &preset,
+		},
+		{
+			name: "skips unknown levels",
+			estimates: StrengthEstimates{
+				Edges:   []EdgeStrengthEstimate{{CauseArgument: "企業の投資が増える", EffectArgument: "雇用が増える", CertaintyLevel: "certain"}},
+				Impacts: []ImpactMagnitudeEstimate{{Argument: "雇用が増える", MagnitudeLevel: "huge"}},
+			},
+			wantSkipped: 2,
+		},
+		{
+			name: "skips arguments that do not match exactly",
+			estimates: StrengthEstimates{
+				Edges:   []EdgeStrengthEstimate{{CauseArgument: "企業の投資が増える。", EffectArgument: "雇用が増える", CertaintyLevel: "high"}},
+				Impacts: []ImpactMagnitudeEstimate{{Argument: "雇用が減る", MagnitudeLevel: "major"}},
+			},
+			wantSkipped: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dg := newStrengthTestGraph(t)
+			edge, _ := dg.GetEdge("企業の投資が増える", "雇用が増える")
+			if tt.presetEdge {
+				edge.Probability = &preset
+			}
+
+			skipped := ApplyStrengthEstimates(dg, &tt.estimates)
+			assert.Len(t, skipped, tt.wantSkipped)
+			assert.Equal(t, tt.wantProbability, edge.Probability)
+			node, _ := dg.GetNode("雇用が増える")
+			assert.Equal(t, tt.wantMagnitude, node.Magnitude)
+		})
+	}
+}
+
+func ptr(value float64) *float64 {
+	return &value
+}